@@ -15,8 +15,117 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/auth/login": {
+            "post": {
+                "description": "Check staff credentials and issue access and refresh tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Staff login",
+                "parameters": [
+                    {
+                        "description": "login data",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new pair of tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh tokens",
+                "parameters": [
+                    {
+                        "description": "refresh token",
+                        "name": "refresh",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/barcode": {
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "barcode",
                 "consumes": [
                     "application/json"
@@ -43,7 +152,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Basket"
                         }
                     },
                     "400": {
@@ -58,6 +167,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -69,6 +184,11 @@ const docTemplate = `{
         },
         "/basket": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Get baskets list",
                 "consumes": [
                     "application/json"
@@ -128,7 +248,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new basket",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a product to an open sale, onto its line for the product when there is one, within the branch stock",
                 "consumes": [
                     "application/json"
                 ],
@@ -169,6 +294,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -180,6 +311,11 @@ const docTemplate = `{
         },
         "/basket/{id}": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Get basket by id",
                 "consumes": [
                     "application/json"
@@ -228,6 +364,11 @@ const docTemplate = `{
                 }
             },
             "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Update basket by id",
                 "consumes": [
                     "application/json"
@@ -276,6 +417,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -285,6 +432,11 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Delete Basket",
                 "consumes": [
                     "application/json"
@@ -324,6 +476,88 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/basket/{id}/discount": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Set a manual discount in percent on a basket line. Cashiers may give up to 10%, branch managers up to 30%",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "Set a manual discount on a basket line",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "basket id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "discount",
+                        "name": "discount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BasketDiscount"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -335,6 +569,11 @@ const docTemplate = `{
         },
         "/branch": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Get branchs list",
                 "consumes": [
                     "application/json"
@@ -394,6 +633,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Create a new branch",
                 "consumes": [
                     "application/json"
@@ -446,6 +690,11 @@ const docTemplate = `{
         },
         "/branch/{id}": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Get branch by id",
                 "consumes": [
                     "application/json"
@@ -494,6 +743,11 @@ const docTemplate = `{
                 }
             },
             "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Update branch by id",
                 "consumes": [
                     "application/json"
@@ -551,6 +805,11 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Delete Branch",
                 "consumes": [
                     "application/json"
@@ -601,6 +860,11 @@ const docTemplate = `{
         },
         "/category": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Get category list",
                 "consumes": [
                     "application/json"
@@ -660,6 +924,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Create a new category",
                 "consumes": [
                     "application/json"
@@ -712,6 +981,11 @@ const docTemplate = `{
         },
         "/category/{id}": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Get category by id",
                 "consumes": [
                     "application/json"
@@ -760,6 +1034,11 @@ const docTemplate = `{
                 }
             },
             "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Update category by id",
                 "consumes": [
                     "application/json"
@@ -817,6 +1096,11 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Delete Category",
                 "consumes": [
                     "application/json"
@@ -865,9 +1149,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/end_sell/{id}": {
-            "put": {
-                "description": "end sell",
+        "/customer": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get customers list, search matches name or phone",
                 "consumes": [
                     "application/json"
                 ],
@@ -875,28 +1164,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sell"
+                    "customer"
                 ],
-                "summary": "end sell",
+                "summary": "Get customers list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "sale_id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "description": "sale request",
-                        "name": "SaleRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SaleRequest"
-                        }
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomersResponse"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -916,11 +1213,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/income": {
+            },
             "post": {
-                "description": "Create a new income",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Register a customer at the caller's branch",
                 "consumes": [
                     "application/json"
                 ],
@@ -928,17 +1228,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income"
+                    "customer"
                 ],
-                "summary": "Create a new income",
+                "summary": "Create a new customer",
                 "parameters": [
                     {
-                        "description": "income data",
-                        "name": "income",
+                        "description": "customer data",
+                        "name": "customer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateIncome"
+                            "$ref": "#/definitions/models.CreateCustomer"
                         }
                     }
                 ],
@@ -946,7 +1246,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Income"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -955,6 +1255,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -970,9 +1276,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/income/{id}": {
+        "/customer/{id}": {
             "get": {
-                "description": "Get income by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get customer by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -980,13 +1291,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income"
+                    "customer"
                 ],
-                "summary": "Get income by id",
+                "summary": "Get customer by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "income",
+                        "description": "customer",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -996,7 +1307,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Income"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -1020,7 +1331,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update income by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update customer by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1028,24 +1344,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income"
+                    "customer"
                 ],
-                "summary": "Update income by id",
+                "summary": "Update customer by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "income id",
+                        "description": "customer id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "income",
-                        "name": "income",
+                        "description": "customer",
+                        "name": "customer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateIncome"
+                            "$ref": "#/definitions/models.UpdateCustomer"
                         }
                     }
                 ],
@@ -1053,7 +1369,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Income"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -1077,7 +1393,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Income",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -1085,13 +1406,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income"
+                    "customer"
                 ],
-                "summary": "Delete Income",
+                "summary": "Delete Customer",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "income id",
+                        "description": "customer id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1125,9 +1446,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/income_product": {
-            "post": {
-                "description": "Create a new income product",
+        "/customer/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the customer's sales and loyalty points ledger, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1135,25 +1461,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "customer"
                 ],
-                "summary": "Create a new income product",
+                "summary": "Get customer history",
                 "parameters": [
                     {
-                        "description": "income product data",
-                        "name": "income_product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateIncomeProduct"
-                        }
+                        "type": "string",
+                        "description": "customer id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomeProduct"
+                            "$ref": "#/definitions/models.CustomerHistory"
                         }
                     },
                     "400": {
@@ -1177,9 +1513,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/income_product/{id}": {
-            "get": {
-                "description": "Get income product by id",
+        "/end_sell/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "end sell, status must be \"completed\" or \"cancelled\". Cancelling a completed sale returns its products to storage and withdraws the staff commissions. Completing takes the card, cash and loyalty points payments, which must add up to the sale total; cash may be tendered above its amount and the change is returned. A sale with a customer earns loyalty points",
                 "consumes": [
                     "application/json"
                 ],
@@ -1187,23 +1528,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "sell"
                 ],
-                "summary": "Get income product by id",
+                "summary": "end sell",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "income_product",
+                        "description": "sale_id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "sale request",
+                        "name": "SaleRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SaleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomeProduct"
+                            "$ref": "#/definitions/models.SaleReceipt"
                         }
                     },
                     "400": {
@@ -1218,6 +1568,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1225,9 +1581,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update income product by id",
+            }
+        },
+        "/income": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new income",
                 "consumes": [
                     "application/json"
                 ],
@@ -1235,32 +1598,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "income"
                 ],
-                "summary": "Update income product by id",
+                "summary": "Create a new income",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "income id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "income product",
-                        "name": "income_product",
+                        "description": "income data",
+                        "name": "income",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateIncomeProduct"
+                            "$ref": "#/definitions/models.CreateIncome"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomeProduct"
+                            "$ref": "#/definitions/models.Income"
                         }
                     },
                     "400": {
@@ -1282,9 +1638,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Income",
+            }
+        },
+        "/income/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get income by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1292,13 +1655,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "income"
                 ],
-                "summary": "Delete Income",
+                "summary": "Get income by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "income product id",
+                        "description": "income",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1308,7 +1671,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Income"
                         }
                     },
                     "400": {
@@ -1330,11 +1693,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/income_products": {
-            "get": {
-                "description": "Get income products list",
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update income by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1342,34 +1708,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "income"
                 ],
-                "summary": "Get income products list",
+                "summary": "Update income by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "income id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "income",
+                        "name": "income",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateIncome"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomeProductsResponse"
+                            "$ref": "#/definitions/models.Income"
                         }
                     },
                     "400": {
@@ -1391,11 +1755,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/incomes": {
-            "get": {
-                "description": "Get incomes list",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Income",
                 "consumes": [
                     "application/json"
                 ],
@@ -1405,32 +1772,21 @@ const docTemplate = `{
                 "tags": [
                     "income"
                 ],
-                "summary": "Get incomes list",
+                "summary": "Delete Income",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "income id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomesResponse"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -1454,9 +1810,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/product": {
-            "get": {
-                "description": "Get products list",
+        "/income_product": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Receive a product against an income: the line is recorded, the income branch is restocked and the income price recomputed",
                 "consumes": [
                     "application/json"
                 ],
@@ -1464,76 +1825,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "income_product"
                 ],
-                "summary": "Get products list",
+                "summary": "Create a new income product",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.ProductsResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Create a new product",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "product"
-                ],
-                "summary": "Create a new product",
-                "parameters": [
-                    {
-                        "description": "product data",
-                        "name": "product",
+                        "description": "income product data",
+                        "name": "income_product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateProduct"
+                            "$ref": "#/definitions/models.CreateIncomeProduct"
                         }
                     }
                 ],
@@ -1541,7 +1843,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.IncomeProduct"
                         }
                     },
                     "400": {
@@ -1565,9 +1867,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/product/{id}": {
+        "/income_product/{id}": {
             "get": {
-                "description": "Get product by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get income product by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1575,13 +1882,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "income_product"
                 ],
-                "summary": "Get product by id",
+                "summary": "Get income product by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "product",
+                        "description": "income_product",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1591,7 +1898,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.IncomeProduct"
                         }
                     },
                     "400": {
@@ -1615,7 +1922,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update product by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Correct an income line, the old line is taken back off the branch storage and the corrected one received",
                 "consumes": [
                     "application/json"
                 ],
@@ -1623,24 +1935,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "income_product"
                 ],
-                "summary": "Update product by id",
+                "summary": "Update income product by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "product id",
+                        "description": "income id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "product",
-                        "name": "product",
+                        "description": "income product",
+                        "name": "income_product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateProduct"
+                            "$ref": "#/definitions/models.UpdateIncomeProduct"
                         }
                     }
                 ],
@@ -1648,7 +1960,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.IncomeProduct"
                         }
                     },
                     "400": {
@@ -1672,7 +1984,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Product",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete an income line and take its count back off the branch storage",
                 "consumes": [
                     "application/json"
                 ],
@@ -1680,13 +1997,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "income_product"
                 ],
-                "summary": "Delete Product",
+                "summary": "Delete Income",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "product id",
+                        "description": "income product id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1720,9 +2037,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sale": {
+        "/income_products": {
             "get": {
-                "description": "Get sales list",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get income products list",
                 "consumes": [
                     "application/json"
                 ],
@@ -1730,9 +2052,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "income_product"
                 ],
-                "summary": "Get sales list",
+                "summary": "Get income products list",
                 "parameters": [
                     {
                         "type": "string",
@@ -1757,7 +2079,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SalesResponse"
+                            "$ref": "#/definitions/models.IncomeProductsResponse"
                         }
                     },
                     "400": {
@@ -1779,9 +2101,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new sale",
+            }
+        },
+        "/incomes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get incomes list",
                 "consumes": [
                     "application/json"
                 ],
@@ -1789,25 +2118,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "income"
                 ],
-                "summary": "Create a new sale",
+                "summary": "Get incomes list",
                 "parameters": [
                     {
-                        "description": "sale data",
-                        "name": "sale",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateSale"
-                        }
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Sale"
+                            "$ref": "#/definitions/models.IncomesResponse"
                         }
                     },
                     "400": {
@@ -1831,9 +2169,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sale/{id}": {
+        "/inventory_count": {
             "get": {
-                "description": "Get sale by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get inventory counts list",
                 "consumes": [
                     "application/json"
                 ],
@@ -1841,23 +2184,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "inventory_count"
                 ],
-                "summary": "Get sale by id",
+                "summary": "Get inventory counts list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "sale",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "open or posted",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Sale"
+                            "$ref": "#/definitions/models.InventoryCountsResponse"
                         }
                     },
                     "400": {
@@ -1880,8 +2234,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update sale by id",
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Open a stocktake for a branch, optionally for a category and its subcategories. The storage counts at this moment become the expected quantities.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1889,32 +2248,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "inventory_count"
                 ],
-                "summary": "Update sale by id",
+                "summary": "Open an inventory count",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "sale id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "sale",
-                        "name": "sale",
+                        "description": "inventory count data",
+                        "name": "inventory_count",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateSale"
+                            "$ref": "#/definitions/models.CreateInventoryCount"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Sale"
+                            "$ref": "#/definitions/models.InventoryCount"
                         }
                     },
                     "400": {
@@ -1923,6 +2275,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1936,9 +2294,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Sale",
+            }
+        },
+        "/inventory_count/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get inventory count with its expected and counted quantities",
                 "consumes": [
                     "application/json"
                 ],
@@ -1946,13 +2311,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "inventory_count"
                 ],
-                "summary": "Delete Sale",
+                "summary": "Get inventory count by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "sale id",
+                        "description": "inventory count",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1962,7 +2327,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.InventoryCount"
                         }
                     },
                     "400": {
@@ -1984,11 +2349,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sell": {
-            "post": {
-                "description": "sell",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete an inventory count that was not posted",
                 "consumes": [
                     "application/json"
                 ],
@@ -1996,24 +2364,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sell"
+                    "inventory_count"
                 ],
-                "summary": "sell",
+                "summary": "Delete Inventory Count",
                 "parameters": [
                     {
-                        "description": "sell",
-                        "name": "sell",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateSale"
-                        }
+                        "type": "string",
+                        "description": "inventory count id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Sale"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -2022,8 +2389,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -2037,9 +2404,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/staff": {
-            "get": {
-                "description": "Get staffs list",
+        "/inventory_count/{id}/post": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Close the count and adjust the branch storage by the variances of the counted products",
                 "consumes": [
                     "application/json"
                 ],
@@ -2047,34 +2419,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "staff"
+                    "inventory_count"
                 ],
-                "summary": "Get staffs list",
+                "summary": "Post inventory count",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "inventory count id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "adjustment reason",
+                        "name": "post",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.PostInventoryCount"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.StaffsResponse"
+                            "$ref": "#/definitions/models.InventoryVariance"
                         }
                     },
                     "400": {
@@ -2089,52 +2458,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Create a new staff",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "staff"
-                ],
-                "summary": "Create a new staff",
-                "parameters": [
-                    {
-                        "description": "staff data",
-                        "name": "staff",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateStaff"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.Staff"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -2148,57 +2473,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/staff/{id}": {
-            "get": {
-                "description": "Get staff by id",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "staff"
-                ],
-                "summary": "Get staff by id",
-                "parameters": [
+        "/inventory_count/{id}/products": {
+            "put": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "staff",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "ApiKeyAuth": []
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Staff"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "Update staff by id",
+                "description": "Record counted quantities by product id or barcode. Counting a product again replaces its quantity.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2206,24 +2488,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "staff"
+                    "inventory_count"
                 ],
-                "summary": "Update staff by id",
+                "summary": "Submit counted quantities",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "staff id",
+                        "description": "inventory count id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "staff",
-                        "name": "staff",
+                        "description": "counted products",
+                        "name": "products",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateStaff"
+                            "$ref": "#/definitions/models.SubmitInventoryCount"
                         }
                     }
                 ],
@@ -2231,7 +2513,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Staff"
+                            "$ref": "#/definitions/models.InventoryCount"
                         }
                     },
                     "400": {
@@ -2246,6 +2528,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2253,9 +2541,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Staff",
+            }
+        },
+        "/inventory_count/{id}/variance": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the counted products that differ from storage, valued at their last income price",
                 "consumes": [
                     "application/json"
                 ],
@@ -2263,13 +2558,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "staff"
+                    "inventory_count"
                 ],
-                "summary": "Delete Staff",
+                "summary": "Get inventory variance report",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "staff id",
+                        "description": "inventory count id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2279,7 +2574,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.InventoryVariance"
                         }
                     },
                     "400": {
@@ -2303,9 +2598,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/storage": {
+        "/price_change": {
             "get": {
-                "description": "Get storages list",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get price changes list, latest effective first",
                 "consumes": [
                     "application/json"
                 ],
@@ -2313,9 +2613,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage"
+                    "price_change"
                 ],
-                "summary": "Get storages list",
+                "summary": "Get price changes list",
                 "parameters": [
                     {
                         "type": "string",
@@ -2331,8 +2631,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
+                        "description": "draft, approved or applied",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
@@ -2340,7 +2640,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.StoragesResponse"
+                            "$ref": "#/definitions/models.PriceChangesResponse"
                         }
                     },
                     "400": {
@@ -2364,7 +2664,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new storage",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Draft a percent or absolute change of the prices of a product list or of a category subtree, rounded nearest, up or down to round_to and due at effective_at. Without a branch it changes the product prices of every branch.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2372,17 +2677,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage"
+                    "price_change"
                 ],
-                "summary": "Create a new storage",
+                "summary": "Create a new price change",
                 "parameters": [
                     {
-                        "description": "storage data",
-                        "name": "storage",
+                        "description": "price change data",
+                        "name": "price_change",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateStorage"
+                            "$ref": "#/definitions/models.CreatePriceChange"
                         }
                     }
                 ],
@@ -2390,7 +2695,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Storage"
+                            "$ref": "#/definitions/models.PriceChange"
                         }
                     },
                     "400": {
@@ -2399,8 +2704,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -2414,9 +2719,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/storage/{id}": {
+        "/price_change/{id}": {
             "get": {
-                "description": "Get storage by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get price change by id with its products, priced before and after once applied",
                 "consumes": [
                     "application/json"
                 ],
@@ -2424,13 +2734,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage"
+                    "price_change"
                 ],
-                "summary": "Get storage by id",
+                "summary": "Get price change by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "storage",
+                        "description": "price change",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2440,7 +2750,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Storage"
+                            "$ref": "#/definitions/models.PriceChange"
                         }
                     },
                     "400": {
@@ -2463,8 +2773,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update storage by id",
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a price change that was not applied yet",
                 "consumes": [
                     "application/json"
                 ],
@@ -2472,32 +2787,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage"
+                    "price_change"
                 ],
-                "summary": "Update storage by id",
+                "summary": "Delete Price Change",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "storage id",
+                        "description": "price change id",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "storage data",
-                        "name": "storage",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateStorage"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Storage"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -2506,8 +2812,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -2519,9 +2825,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Storage",
+            }
+        },
+        "/price_change/{id}/approve": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Approve a draft price change as the caller, who can't be its author, the scheduler applies it once it is due",
                 "consumes": [
                     "application/json"
                 ],
@@ -2529,13 +2842,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage"
+                    "price_change"
                 ],
-                "summary": "Delete Storage",
+                "summary": "Approve price change",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "storage id",
+                        "description": "price change id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2545,7 +2858,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.PriceChange"
                         }
                     },
                     "400": {
@@ -2554,8 +2867,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -2569,9 +2888,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/storage_transaction": {
+        "/product": {
             "get": {
-                "description": "Get storage_transactions list",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get products list",
                 "consumes": [
                     "application/json"
                 ],
@@ -2579,9 +2903,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage_transaction"
+                    "product"
                 ],
-                "summary": "Get storage_transactions list",
+                "summary": "Get products list",
                 "parameters": [
                     {
                         "type": "string",
@@ -2606,7 +2930,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.StorageTransactionsResponse"
+                            "$ref": "#/definitions/models.ProductsResponse"
                         }
                     },
                     "400": {
@@ -2630,7 +2954,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new storage_transaction",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new product",
                 "consumes": [
                     "application/json"
                 ],
@@ -2638,17 +2967,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage_transaction"
+                    "product"
                 ],
-                "summary": "Create a new storage_transaction",
+                "summary": "Create a new product",
                 "parameters": [
                     {
-                        "description": "storage transaction  data",
-                        "name": "storage_transaction",
+                        "description": "product data",
+                        "name": "product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateStorageTransaction"
+                            "$ref": "#/definitions/models.CreateProduct"
                         }
                     }
                 ],
@@ -2656,7 +2985,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.StorageTransaction"
+                            "$ref": "#/definitions/models.Product"
                         }
                     },
                     "400": {
@@ -2680,9 +3009,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/storage_transaction/{id}": {
+        "/product/{id}": {
             "get": {
-                "description": "Get storage transaction by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get product by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2690,13 +3024,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage_transaction"
+                    "product"
                 ],
-                "summary": "Get storage transaction by id",
+                "summary": "Get product by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "storage transaction",
+                        "description": "product",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2706,7 +3040,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.StorageTransaction"
+                            "$ref": "#/definitions/models.Product"
                         }
                     },
                     "400": {
@@ -2730,7 +3064,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update storage transaction by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update product by id, admins only as the product price is the one of every branch without its own",
                 "consumes": [
                     "application/json"
                 ],
@@ -2738,24 +3077,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage_transaction"
+                    "product"
                 ],
-                "summary": "Update storage transaction by id",
+                "summary": "Update product by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "storage transaction id",
+                        "description": "product id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "storage_transaction",
-                        "name": "storage_transaction",
+                        "description": "product",
+                        "name": "product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateStorageTransaction"
+                            "$ref": "#/definitions/models.UpdateProduct"
                         }
                     }
                 ],
@@ -2763,7 +3102,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.StorageTransaction"
+                            "$ref": "#/definitions/models.Product"
                         }
                     },
                     "400": {
@@ -2787,7 +3126,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Storage Transaction",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Product",
                 "consumes": [
                     "application/json"
                 ],
@@ -2795,13 +3139,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage_transaction"
+                    "product"
                 ],
-                "summary": "Delete Storage Transaction",
+                "summary": "Delete Product",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "storage transaction id",
+                        "description": "product id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2835,9 +3179,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tarif": {
+        "/product/{id}/prices": {
             "get": {
-                "description": "Get tarifs list",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the price every branch sells the product at now and the price history, latest effective first",
                 "consumes": [
                     "application/json"
                 ],
@@ -2845,9 +3194,120 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tarif"
+                    "product"
                 ],
-                "summary": "Get tarifs list",
+                "summary": "Get product prices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "product id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductPrices"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Override the product price at a branch from the effective time on, at once when none is given. A branch manager sets the price of their own branch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Set a branch price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "product id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "branch price",
+                        "name": "price",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateProductPrice"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductPrices"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get promotions list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Get promotions list",
                 "parameters": [
                     {
                         "type": "string",
@@ -2872,7 +3332,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TarifsResponse"
+                            "$ref": "#/definitions/models.PromotionsResponse"
                         }
                     },
                     "400": {
@@ -2896,7 +3356,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new tarif",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a percent, fixed or \"buy N get M\" promotion for a product, category or branch, optionally limited in time",
                 "consumes": [
                     "application/json"
                 ],
@@ -2904,17 +3369,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tarif"
+                    "promotion"
                 ],
-                "summary": "Create a new tarif",
+                "summary": "Create a new promotion",
                 "parameters": [
                     {
-                        "description": "tarif data",
-                        "name": "tarif",
+                        "description": "promotion data",
+                        "name": "promotion",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateTarif"
+                            "$ref": "#/definitions/models.CreatePromotion"
                         }
                     }
                 ],
@@ -2922,7 +3387,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Tarif"
+                            "$ref": "#/definitions/models.Promotion"
                         }
                     },
                     "400": {
@@ -2931,6 +3396,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2946,9 +3417,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tarif/{id}": {
+        "/promotion/{id}": {
             "get": {
-                "description": "Get tarif by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get promotion by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2956,13 +3432,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tarif"
+                    "promotion"
                 ],
-                "summary": "Get tarif by id",
+                "summary": "Get promotion by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "tarif",
+                        "description": "promotion",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2972,7 +3448,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tarif"
+                            "$ref": "#/definitions/models.Promotion"
                         }
                     },
                     "400": {
@@ -2996,7 +3472,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update tarif by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update promotion by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -3004,24 +3485,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tarif"
+                    "promotion"
                 ],
-                "summary": "Update tarif by id",
+                "summary": "Update promotion by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "tarif id",
+                        "description": "promotion id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "tarif",
-                        "name": "tarif",
+                        "description": "promotion",
+                        "name": "promotion",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateTarif"
+                            "$ref": "#/definitions/models.UpdatePromotion"
                         }
                     }
                 ],
@@ -3029,7 +3510,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tarif"
+                            "$ref": "#/definitions/models.Promotion"
                         }
                     },
                     "400": {
@@ -3038,6 +3519,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -3053,7 +3540,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Tarif",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Promotion",
                 "consumes": [
                     "application/json"
                 ],
@@ -3061,13 +3553,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tarif"
+                    "promotion"
                 ],
-                "summary": "Delete Tarif",
+                "summary": "Delete Promotion",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "tarif id",
+                        "description": "promotion id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3101,9 +3593,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction": {
+        "/purchase_order": {
             "get": {
-                "description": "Get transactions list",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get purchase orders list",
                 "consumes": [
                     "application/json"
                 ],
@@ -3111,9 +3608,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "purchase_order"
                 ],
-                "summary": "Get transactions list",
+                "summary": "Get purchase orders list",
                 "parameters": [
                     {
                         "type": "string",
@@ -3129,14 +3626,14 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "from_amount",
-                        "name": "from_amount",
+                        "description": "supplier_id",
+                        "name": "supplier_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "to_amount",
-                        "name": "to_amount",
+                        "description": "ordered, received or cancelled",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
@@ -3144,7 +3641,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TransactionsResponse"
+                            "$ref": "#/definitions/models.PurchaseOrdersResponse"
                         }
                     },
                     "400": {
@@ -3168,7 +3665,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new transaction",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Order products from a supplier for a branch",
                 "consumes": [
                     "application/json"
                 ],
@@ -3176,17 +3678,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "purchase_order"
                 ],
-                "summary": "Create a new transaction",
+                "summary": "Create a new purchase order",
                 "parameters": [
                     {
-                        "description": "transaction data",
-                        "name": "transaction",
+                        "description": "purchase order data",
+                        "name": "purchase_order",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateTransactions"
+                            "$ref": "#/definitions/models.CreatePurchaseOrder"
                         }
                     }
                 ],
@@ -3194,7 +3696,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Transactions"
+                            "$ref": "#/definitions/models.PurchaseOrder"
                         }
                     },
                     "400": {
@@ -3203,6 +3705,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -3218,9 +3726,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction/{id}": {
+        "/purchase_order/{id}": {
             "get": {
-                "description": "Get transaction by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get purchase order with its lines and the discrepancies found on receipt",
                 "consumes": [
                     "application/json"
                 ],
@@ -3228,13 +3741,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "purchase_order"
                 ],
-                "summary": "Get transaction by id",
+                "summary": "Get purchase order by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "transaction",
+                        "description": "purchase order",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3244,7 +3757,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Transactions"
+                            "$ref": "#/definitions/models.PurchaseOrder"
                         }
                     },
                     "400": {
@@ -3266,9 +3779,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/purchase_order/{id}/cancel": {
             "put": {
-                "description": "Update transaction by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancel a purchase order that was not received yet",
                 "consumes": [
                     "application/json"
                 ],
@@ -3276,32 +3796,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "purchase_order"
                 ],
-                "summary": "Update transaction by id",
+                "summary": "Cancel purchase order",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "transaction id",
+                        "description": "purchase order id",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "transaction",
-                        "name": "transaction",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateTransactions"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tarif"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -3310,8 +3821,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -3323,9 +3834,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Transaction",
+            }
+        },
+        "/purchase_order/{id}/receive": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Book the delivered counts into a new income and the branch storage. Lines left out were not delivered.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3333,23 +3851,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "purchase_order"
                 ],
-                "summary": "Delete Transaction",
+                "summary": "Receive purchase order",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "transaction id",
+                        "description": "purchase order id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "delivered products",
+                        "name": "receive",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReceivePurchaseOrder"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.PurchaseOrder"
                         }
                     },
                     "400": {
@@ -3358,12 +3885,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3372,30 +3911,4552 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Barcode": {
-            "type": "object",
-            "properties": {
-                "barcode": {
-                    "type": "integer"
-                },
-                "count": {
+        },
+        "/reports/expiring-batches": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the batches in stock per branch that expire within the given days or have already expired",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get expiring batches report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "branch_id",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "days ahead to look at, 30 by default",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExpiringBatchesReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/low-stock": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the products below their minimum with a suggested order quantity from the sales of the last days",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get low stock report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "branch_id",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "days of sales to look at, 30 by default",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LowStockReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/margin": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the revenue, cost of goods sold and gross margin of completed sales net of returns, grouped by sale, product, category or branch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get gross margin report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sale, product, category or branch, product by default",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "branch_id",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "from, 2006-01-02 or RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "to, 2006-01-02 inclusive or RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarginReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/sales": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the revenue, sales count, items sold and average ticket of sales completed in the period net of returns, grouped by branch, day, hour, cashier, shop_assistant, category (rolled up to the top level) or payment_type, with the top products by revenue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get sales report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "branch, day, hour, cashier, shop_assistant, category or payment_type, day by default",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "branch_id",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "from, 2006-01-02 or RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "to, 2006-01-02 inclusive or RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "number of top products, 10 by default",
+                        "name": "top",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalesReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/stock-movement": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the opening balance, every movement in and out and the closing balance of a product at a branch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get stock movement report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "branch_id, the caller's branch by default",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "from, 2006-01-02 or RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "to, 2006-01-02 inclusive or RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockMovementReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/stock-on-date": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the quantity of every product per branch as it was at a past moment. A day means its end.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get stock on date report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "date, 2006-01-02 or RFC 3339, now by default",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "branch_id",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockOnDate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/sale": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get sales list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Get sales list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new sale",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Create a new sale",
+                "parameters": [
+                    {
+                        "description": "sale data",
+                        "name": "sale",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSale"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/sale/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get sale by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Get sale by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sale",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update sale by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Update sale by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sale id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "sale",
+                        "name": "sale",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSale"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Sale",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Delete Sale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sale id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/sale/{id}/payments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the card and cash tenders a sale was paid with",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Get sale payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sale id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SalePayment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/sale/{id}/receipt": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the receipt of a completed sale as JSON, as 80mm thermal printer text or as PDF, a sale cancelled or refunded since is marked with its status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Get sale receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sale id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json, text or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Receipt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/sale/{id}/returns": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get returns of a sale",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_return"
+                ],
+                "summary": "Get returns of a sale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sale id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SaleReturnsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Return products of a completed sale, restock the branch, withdraw staff commissions and earned points proportionally and refund the points tender share as points",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_return"
+                ],
+                "summary": "Return products of a sale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sale id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "returned basket lines",
+                        "name": "sale_return",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSaleReturn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SaleReturn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/sell": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "sell",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sell"
+                ],
+                "summary": "sell",
+                "parameters": [
+                    {
+                        "description": "sell",
+                        "name": "sell",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSale"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get staffs list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Get staffs list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaffsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new staff",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Create a new staff",
+                "parameters": [
+                    {
+                        "description": "staff data",
+                        "name": "staff",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStaff"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Staff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get staff by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Get staff by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "staff",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Staff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update staff by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Update staff by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "staff id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "staff",
+                        "name": "staff",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStaff"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Staff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Staff",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Delete Staff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "staff id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/{id}/password": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change staff password, the old password is required to change your own one, managers reset the password of other staff without it, only admins reset the password of managers and admins",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Change staff password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "staff id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "old and new password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStaffPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get storages list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage"
+                ],
+                "summary": "Get storages list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StoragesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Open the storage of a product at a branch, an opening count is posted to the ledger as an adjustment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage"
+                ],
+                "summary": "Create a new storage",
+                "parameters": [
+                    {
+                        "description": "storage data",
+                        "name": "storage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStorage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Storage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get storage by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage"
+                ],
+                "summary": "Get storage by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "storage",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Storage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Set the stock thresholds, a given count is reached with an adjustment posted to the ledger",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage"
+                ],
+                "summary": "Update storage by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "storage id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "storage data",
+                        "name": "storage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStorage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Storage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a storage whose count is adjusted to zero",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage"
+                ],
+                "summary": "Delete Storage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "storage id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage_transaction": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the stock ledger oldest first, each movement with the storage count left after it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage_transaction"
+                ],
+                "summary": "Get storage_transactions list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "branch_id",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "product_id",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "plus or minus",
+                        "name": "storage_transaction_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sale, sale_return, income, transfer, inventory_count or adjustment",
+                        "name": "source_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "source document id",
+                        "name": "source_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "from date, 2006-01-02",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "to date inclusive, 2006-01-02",
+                        "name": "to_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StorageTransactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adjust the branch storage by hand, recorded in the ledger as an adjustment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage_transaction"
+                ],
+                "summary": "Create a new storage_transaction",
+                "parameters": [
+                    {
+                        "description": "storage transaction  data",
+                        "name": "storage_transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStorageTransaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.StorageTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage_transaction/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get storage transaction by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage_transaction"
+                ],
+                "summary": "Get storage transaction by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "storage transaction",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StorageTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get suppliers list, search matches name or phone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Get suppliers list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuppliersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Create a new supplier",
+                "parameters": [
+                    {
+                        "description": "supplier data",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSupplier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get supplier by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Get supplier by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "supplier",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update supplier by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Update supplier by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "supplier id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "supplier",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSupplier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Delete Supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "supplier id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier/{id}/payable": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the value received from the supplier at every branch, the amount paid and the balance still owed, admins only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Get supplier payable",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "supplier id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierPayable"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier/{id}/payment": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Record a payment to the supplier against what they are owed, admins only as payments are not kept by branch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Pay a supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "supplier id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payment",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSupplierPayment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierPayment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tarif": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get tarifs list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tarif"
+                ],
+                "summary": "Get tarifs list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TarifsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new tarif",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tarif"
+                ],
+                "summary": "Create a new tarif",
+                "parameters": [
+                    {
+                        "description": "tarif data",
+                        "name": "tarif",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTarif"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tarif"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tarif/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get tarif by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tarif"
+                ],
+                "summary": "Get tarif by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "tarif",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tarif"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update tarif by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tarif"
+                ],
+                "summary": "Update tarif by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "tarif id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "tarif",
+                        "name": "tarif",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTarif"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tarif"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Tarif",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tarif"
+                ],
+                "summary": "Delete Tarif",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "tarif id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get transactions list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get transactions list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "from_amount",
+                        "name": "from_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "to_amount",
+                        "name": "to_amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Create a new transaction",
+                "parameters": [
+                    {
+                        "description": "transaction data",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransactions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transactions"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get transaction by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get transaction by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transactions"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update transaction by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Update transaction by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "transaction",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTransactions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tarif"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Delete Transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfer": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get transfers list, branch_id matches both the source and the destination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Get transfers list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, in_transit or received",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "branch_id",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransfersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Draft a transfer of products from one branch to another",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Create a new transfer",
+                "parameters": [
+                    {
+                        "description": "transfer data",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransfer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfer/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get transfer by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Get transfer by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transfer",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a transfer that was not dispatched yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Delete Transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transfer id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfer/{id}/dispatch": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Take the transfer products out of the source branch storage and put the transfer in transit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Dispatch transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transfer id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfer/{id}/receive": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Put the transfer products on the destination branch storage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Receive transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transfer id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.Barcode": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "sale_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Basket": {
+            "type": "object",
+            "properties": {
+                "base_price": {
+                    "type": "number"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manual_discount_percent": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "promotions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BasketPromotion"
+                    }
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BasketDiscount": {
+            "type": "object",
+            "properties": {
+                "percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BasketPromotion": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "basket_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "promotion_id": {
+                    "type": "string"
+                },
+                "staff_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BasketsResponse": {
+            "type": "object",
+            "properties": {
+                "baskets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Basket"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Branch": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BranchPrice": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "override": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BranchsResponse": {
+            "type": "object",
+            "properties": {
+                "branchs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Branch"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CategoriesResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CountedInventoryProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "counted_count": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateBasket": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sale_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateBranch": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateCategory": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateCustomer": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateIncome": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "supplier_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateIncomeProduct": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "income_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateInventoryCount": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePriceChange": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "change_type": {
+                    "type": "string"
+                },
+                "effective_at": {
+                    "type": "string"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "round_to": {
+                    "type": "number"
+                },
+                "rounding": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateProduct": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateProductPrice": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreatePromotion": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "promotion_type": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreatePurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreatePurchaseOrderProduct"
+                    }
+                },
+                "supplier_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePurchaseOrderProduct": {
+            "type": "object",
+            "properties": {
+                "ordered_count": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSale": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "cashier_id": {
+                    "type": "string"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "payment_type": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "shop_assistent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSalePayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "payment_type": {
+                    "type": "string"
+                },
+                "tendered": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateSaleReturn": {
+            "type": "object",
+            "properties": {
+                "payment_type": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateSaleReturnProduct"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSaleReturnProduct": {
+            "type": "object",
+            "properties": {
+                "basket_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateStaff": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "tarif_id": {
+                    "type": "string"
+                },
+                "type_staff": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateStorage": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "min_count": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reorder_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateStorageTransaction": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "staff_id": {
+                    "type": "string"
+                },
+                "storage_transaction_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSupplier": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSupplierPayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateTarif": {
+            "type": "object",
+            "properties": {
+                "amount_for_card": {
+                    "type": "number"
+                },
+                "amount_for_cash": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tarif_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateTransactions": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "source_type": {
+                    "type": "string"
+                },
+                "staff_id": {
+                    "type": "string"
+                },
+                "transaction_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateTransfer": {
+            "type": "object",
+            "properties": {
+                "from_branch_id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateTransferProduct"
+                    }
+                },
+                "to_branch_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateTransferProduct": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Customer": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CustomerHistory": {
+            "type": "object",
+            "properties": {
+                "customer": {
+                    "$ref": "#/definitions/models.Customer"
+                },
+                "loyalty": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LoyaltyTransaction"
+                    }
+                },
+                "sales": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Sale"
+                    }
+                }
+            }
+        },
+        "models.CustomersResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "customers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Customer"
+                    }
+                }
+            }
+        },
+        "models.ExpiringBatch": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "days_left": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ExpiringBatchesReport": {
+            "type": "object",
+            "properties": {
+                "batches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpiringBatch"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Income": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IncomeProduct": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "income_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IncomeProductsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "income_products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IncomeProduct"
+                    }
+                }
+            }
+        },
+        "models.IncomesResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "incomes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Income"
+                    }
+                }
+            }
+        },
+        "models.InventoryCount": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "opened_by": {
+                    "type": "string"
+                },
+                "posted_at": {
+                    "type": "string"
+                },
+                "posted_by": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryCountProduct"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryCountProduct": {
+            "type": "object",
+            "properties": {
+                "counted_count": {
+                    "type": "integer"
+                },
+                "expected_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inventory_count_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "variance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InventoryCountsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "inventory_counts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryCount"
+                    }
+                }
+            }
+        },
+        "models.InventoryVariance": {
+            "type": "object",
+            "properties": {
+                "inventory_count_id": {
+                    "type": "string"
+                },
+                "net_value": {
+                    "type": "number"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryVarianceLine"
+                    }
+                },
+                "shrinkage_value": {
+                    "type": "number"
+                },
+                "surplus_value": {
+                    "type": "number"
+                },
+                "uncounted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InventoryVarianceLine": {
+            "type": "object",
+            "properties": {
+                "counted_count": {
+                    "type": "integer"
+                },
+                "expected_count": {
+                    "type": "integer"
+                },
+                "last_income_price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "variance": {
+                    "type": "integer"
+                },
+                "variance_value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "staff": {
+                    "$ref": "#/definitions/models.Staff"
+                }
+            }
+        },
+        "models.LowStockItem": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "daily_velocity": {
+                    "type": "number"
+                },
+                "min_count": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "reorder_count": {
+                    "type": "integer"
+                },
+                "sold_count": {
+                    "type": "integer"
+                },
+                "storage_id": {
+                    "type": "string"
+                },
+                "suggested_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LowStockReport": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LowStockItem"
+                    }
+                }
+            }
+        },
+        "models.LoyaltyTransaction": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "number"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "transaction_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MarginLine": {
+            "type": "object",
+            "properties": {
+                "cogs": {
+                    "type": "number"
+                },
+                "gross_margin": {
+                    "type": "number"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "margin_percent": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.MarginReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MarginLine"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/models.MarginLine"
+                }
+            }
+        },
+        "models.PostInventoryCount": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceChange": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "type": "string"
+                },
+                "approved_at": {
+                    "type": "string"
+                },
+                "approved_by": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "change_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "effective_at": {
+                    "type": "string"
+                },
+                "failure": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceChangeProduct"
+                    }
+                },
+                "round_to": {
+                    "type": "number"
+                },
+                "rounding": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PriceChangeProduct": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "new_price": {
+                    "type": "number"
+                },
+                "old_price": {
+                    "type": "number"
+                },
+                "price_change_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceChangesResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
                     "type": "integer"
                 },
-                "sale_id": {
+                "price_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceChange"
+                    }
+                }
+            }
+        },
+        "models.Product": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.Basket": {
+        "models.ProductPrice": {
             "type": "object",
             "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "effective_from": {
                     "type": "string"
                 },
                 "id": {
@@ -3407,35 +8468,58 @@ const docTemplate = `{
                 "product_id": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
-                },
-                "sale_id": {
-                    "type": "string"
-                },
-                "updated_at": {
+                "staff_id": {
                     "type": "string"
                 }
             }
         },
-        "models.BasketsResponse": {
+        "models.ProductPrices": {
             "type": "object",
             "properties": {
-                "baskets": {
+                "branches": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Basket"
+                        "$ref": "#/definitions/models.BranchPrice"
+                    }
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductPrice"
                     }
                 },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductsResponse": {
+            "type": "object",
+            "properties": {
                 "count": {
                     "type": "integer"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
+                    }
                 }
             }
         },
-        "models.Branch": {
+        "models.Promotion": {
             "type": "object",
             "properties": {
-                "address": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "category_id": {
                     "type": "string"
                 },
                 "created_at": {
@@ -3444,61 +8528,80 @@ const docTemplate = `{
                 "deleted_at": {
                     "type": "string"
                 },
+                "ends_at": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
+                "product_id": {
+                    "type": "string"
+                },
+                "promotion_type": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "value": {
+                    "type": "number"
                 }
             }
         },
-        "models.BranchsResponse": {
+        "models.PromotionsResponse": {
             "type": "object",
             "properties": {
-                "branchs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Branch"
-                    }
-                },
                 "count": {
                     "type": "integer"
-                }
-            }
-        },
-        "models.CategoriesResponse": {
-            "type": "object",
-            "properties": {
-                "categories": {
+                },
+                "promotions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Category"
+                        "$ref": "#/definitions/models.Promotion"
                     }
-                },
-                "count": {
-                    "type": "integer"
                 }
             }
         },
-        "models.Category": {
+        "models.PurchaseOrder": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "branch_id": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "income_id": {
                     "type": "string"
                 },
-                "parent_id": {
+                "price": {
+                    "type": "number"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrderProduct"
+                    }
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "supplier_id": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -3506,63 +8609,131 @@ const docTemplate = `{
                 }
             }
         },
-        "models.CreateBasket": {
+        "models.PurchaseOrderProduct": {
             "type": "object",
             "properties": {
+                "discrepancy": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ordered_count": {
+                    "type": "integer"
+                },
                 "price": {
                     "type": "number"
                 },
                 "product_id": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
-                },
-                "sale_id": {
+                "purchase_order_id": {
                     "type": "string"
+                },
+                "received_count": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateBranch": {
+        "models.PurchaseOrdersResponse": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "purchase_orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrder"
+                    }
                 }
             }
         },
-        "models.CreateCategory": {
+        "models.Receipt": {
             "type": "object",
             "properties": {
-                "name": {
+                "branch_address": {
                     "type": "string"
                 },
-                "parent_id": {
+                "branch_name": {
+                    "type": "string"
+                },
+                "cashier_name": {
+                    "type": "string"
+                },
+                "change": {
+                    "type": "number"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReceiptLine"
+                    }
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SalePayment"
+                    }
+                },
+                "receipt_number": {
+                    "type": "integer"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
+                },
+                "total_price": {
+                    "type": "number"
                 }
             }
         },
-        "models.CreateIncome": {
+        "models.ReceiptLine": {
             "type": "object",
             "properties": {
-                "branch_id": {
+                "barcode": {
                     "type": "string"
                 },
                 "price": {
                     "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "type": "number"
                 }
             }
         },
-        "models.CreateIncomeProduct": {
+        "models.ReceivePurchaseOrder": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "income_id": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReceivePurchaseOrderProduct"
+                    }
+                }
+            }
+        },
+        "models.ReceivePurchaseOrderProduct": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
                     "type": "string"
                 },
                 "price": {
@@ -3570,160 +8741,150 @@ const docTemplate = `{
                 },
                 "product_id": {
                     "type": "string"
+                },
+                "received_count": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateProduct": {
+        "models.RefreshTokenRequest": {
             "type": "object",
             "properties": {
-                "category_id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "price": {
+                "refresh_token": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateSale": {
+        "models.Response": {
             "type": "object",
             "properties": {
-                "branch_id": {
-                    "type": "string"
-                },
-                "cashier_id": {
-                    "type": "string"
-                },
-                "client_name": {
-                    "type": "string"
-                },
-                "payment_type": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "string"
-                },
-                "shop_assistent_id": {
+                "data": {},
+                "description": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string"
+                "statusCode": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateStaff": {
+        "models.Sale": {
             "type": "object",
             "properties": {
-                "balance": {
-                    "type": "number"
-                },
-                "birth_date": {
+                "branch_id": {
                     "type": "string"
                 },
-                "branch_id": {
+                "cashier_id": {
                     "type": "string"
                 },
-                "gender": {
+                "client_name": {
                     "type": "string"
                 },
-                "login": {
+                "created_at": {
                     "type": "string"
                 },
-                "name": {
+                "customer_id": {
                     "type": "string"
                 },
-                "password": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "tarif_id": {
+                "id": {
                     "type": "string"
                 },
-                "type_staff": {
+                "payment_type": {
                     "type": "string"
-                }
-            }
-        },
-        "models.CreateStorage": {
-            "type": "object",
-            "properties": {
-                "branch_id": {
+                },
+                "price": {
                     "type": "string"
                 },
-                "count": {
+                "receipt_number": {
                     "type": "integer"
                 },
-                "product_id": {
+                "shop_assistent_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateStorageTransaction": {
+        "models.SalePayment": {
             "type": "object",
             "properties": {
-                "price": {
+                "amount": {
                     "type": "number"
                 },
-                "product_id": {
+                "change": {
+                    "type": "number"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "number"
+                "id": {
+                    "type": "string"
                 },
-                "staff_id": {
+                "payment_type": {
                     "type": "string"
                 },
-                "storage_transaction_type": {
+                "sale_id": {
                     "type": "string"
+                },
+                "tendered": {
+                    "type": "number"
                 }
             }
         },
-        "models.CreateTarif": {
+        "models.SaleReceipt": {
             "type": "object",
             "properties": {
-                "amount_for_card": {
-                    "type": "number"
+                "baskets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Basket"
+                    }
                 },
-                "amount_for_cash": {
+                "change": {
                     "type": "number"
                 },
-                "name": {
-                    "type": "string"
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SalePayment"
+                    }
                 },
-                "tarif_type": {
-                    "type": "string"
+                "sale": {
+                    "$ref": "#/definitions/models.Sale"
+                },
+                "total_price": {
+                    "type": "number"
                 }
             }
         },
-        "models.CreateTransactions": {
+        "models.SaleRequest": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "sale_id": {
-                    "type": "string"
-                },
-                "source_type": {
+                "id": {
                     "type": "string"
                 },
-                "staff_id": {
-                    "type": "string"
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateSalePayment"
+                    }
                 },
-                "transaction_type": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "models.Income": {
+        "models.SaleReturn": {
             "type": "object",
             "properties": {
-                "branch_id": {
-                    "type": "string"
+                "amount": {
+                    "type": "number"
                 },
                 "created_at": {
                     "type": "string"
@@ -3734,136 +8895,148 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
-                "price": {
+                "payment_type": {
+                    "type": "string"
+                },
+                "points": {
                     "type": "number"
                 },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SaleReturnProduct"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "staff_id": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.IncomeProduct": {
+        "models.SaleReturnProduct": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "deleted_at": {
+                "basket_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "income_id": {
-                    "type": "string"
-                },
                 "price": {
                     "type": "number"
                 },
                 "product_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "sale_return_id": {
                     "type": "string"
                 }
             }
         },
-        "models.IncomeProductsResponse": {
+        "models.SaleReturnsResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "income_products": {
+                "sale_returns": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.IncomeProduct"
+                        "$ref": "#/definitions/models.SaleReturn"
                     }
                 }
             }
         },
-        "models.IncomesResponse": {
+        "models.SalesReport": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
+                "from": {
+                    "type": "string"
                 },
-                "incomes": {
+                "group_by": {
+                    "type": "string"
+                },
+                "lines": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Income"
+                        "$ref": "#/definitions/models.SalesReportLine"
                     }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "top_products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TopProduct"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/models.SalesReportLine"
                 }
             }
         },
-        "models.Product": {
+        "models.SalesReportLine": {
             "type": "object",
             "properties": {
-                "barcode": {
-                    "type": "string"
-                },
-                "category_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "deleted_at": {
-                    "type": "string"
+                "average_ticket": {
+                    "type": "number"
                 },
-                "id": {
+                "key": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "price": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
                     "type": "number"
                 },
-                "updated_at": {
-                    "type": "string"
+                "sales_count": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ProductsResponse": {
+        "models.SalesResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "products": {
+                "sales": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Product"
+                        "$ref": "#/definitions/models.Sale"
                     }
                 }
             }
         },
-        "models.Response": {
+        "models.Staff": {
             "type": "object",
             "properties": {
-                "data": {},
-                "description": {
-                    "type": "string"
-                },
-                "statusCode": {
+                "age": {
                     "type": "integer"
-                }
-            }
-        },
-        "models.Sale": {
-            "type": "object",
-            "properties": {
-                "branch_id": {
-                    "type": "string"
                 },
-                "cashier_id": {
+                "balance": {
+                    "type": "number"
+                },
+                "birth_date": {
                     "type": "string"
                 },
-                "client_name": {
+                "branch_id": {
                     "type": "string"
                 },
                 "created_at": {
@@ -3872,115 +9045,150 @@ const docTemplate = `{
                 "deleted_at": {
                     "type": "string"
                 },
-                "id": {
+                "gender": {
                     "type": "string"
                 },
-                "payment_type": {
+                "id": {
                     "type": "string"
                 },
-                "price": {
+                "login": {
                     "type": "string"
                 },
-                "shop_assistent_id": {
+                "name": {
                     "type": "string"
                 },
-                "status": {
+                "tarif_id": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.SaleRequest": {
-            "type": "object",
-            "properties": {
-                "id": {
+                "type_staff": {
                     "type": "string"
                 },
-                "status": {
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.SalesResponse": {
+        "models.StaffsResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "sales": {
+                "staffs": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Sale"
+                        "$ref": "#/definitions/models.Staff"
                     }
                 }
             }
         },
-        "models.Staff": {
+        "models.StockMovement": {
             "type": "object",
             "properties": {
-                "age": {
+                "balance": {
                     "type": "integer"
                 },
-                "balance": {
+                "created_at": {
+                    "type": "string"
+                },
+                "in": {
+                    "type": "integer"
+                },
+                "out": {
+                    "type": "integer"
+                },
+                "price": {
                     "type": "number"
                 },
-                "birth_date": {
+                "reason": {
                     "type": "string"
                 },
-                "branch_id": {
+                "source_id": {
                     "type": "string"
                 },
-                "created_at": {
+                "source_type": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "staff_id": {
                     "type": "string"
                 },
-                "gender": {
+                "storage_transaction_id": {
                     "type": "string"
-                },
-                "id": {
+                }
+            }
+        },
+        "models.StockMovementReport": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
                     "type": "string"
                 },
-                "login": {
-                    "type": "string"
+                "closing_balance": {
+                    "type": "integer"
                 },
-                "name": {
+                "from": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
+                "movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockMovement"
+                    }
                 },
-                "tarif_id": {
+                "opening_balance": {
+                    "type": "integer"
+                },
+                "product_id": {
                     "type": "string"
                 },
-                "type_staff": {
+                "to": {
                     "type": "string"
                 },
-                "updated_at": {
+                "total_in": {
+                    "type": "integer"
+                },
+                "total_out": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockOnDate": {
+            "type": "object",
+            "properties": {
+                "at": {
                     "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockOnDateLine"
+                    }
                 }
             }
         },
-        "models.StaffsResponse": {
+        "models.StockOnDateLine": {
             "type": "object",
             "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
                 "count": {
                     "type": "integer"
                 },
-                "staffs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Staff"
-                    }
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
                 }
             }
         },
         "models.Storage": {
             "type": "object",
             "properties": {
+                "average_cost": {
+                    "type": "number"
+                },
                 "branch_id": {
                     "type": "string"
                 },
@@ -3996,9 +9204,15 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "min_count": {
+                    "type": "integer"
+                },
                 "product_id": {
                     "type": "string"
                 },
+                "reorder_count": {
+                    "type": "integer"
+                },
                 "updated_at": {
                     "type": "string"
                 }
@@ -4007,6 +9221,12 @@ const docTemplate = `{
         "models.StorageTransaction": {
             "type": "object",
             "properties": {
+                "balance_after": {
+                    "type": "integer"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -4025,6 +9245,15 @@ const docTemplate = `{
                 "quantity": {
                     "type": "number"
                 },
+                "reason": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "source_type": {
+                    "type": "string"
+                },
                 "staff_id": {
                     "type": "string"
                 },
@@ -4064,6 +9293,97 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SubmitInventoryCount": {
+            "type": "object",
+            "properties": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CountedInventoryProduct"
+                    }
+                }
+            }
+        },
+        "models.Supplier": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SupplierPayable": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "paid": {
+                    "type": "number"
+                },
+                "received": {
+                    "type": "number"
+                },
+                "supplier_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SupplierPayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "staff_id": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SuppliersResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "suppliers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Supplier"
+                    }
+                }
+            }
+        },
         "models.Tarif": {
             "type": "object",
             "properties": {
@@ -4107,6 +9427,23 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TopProduct": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                }
+            }
+        },
         "models.Transactions": {
             "type": "object",
             "properties": {
@@ -4156,6 +9493,78 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Transfer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "dispatched_at": {
+                    "type": "string"
+                },
+                "dispatched_by": {
+                    "type": "string"
+                },
+                "from_branch_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransferProduct"
+                    }
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "received_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_branch_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransferProduct": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "transfer_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransfersResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transfer"
+                    }
+                }
+            }
+        },
         "models.UpdateBasket": {
             "type": "object",
             "properties": {
@@ -4195,6 +9604,17 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateCustomer": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UpdateIncome": {
             "type": "object",
             "properties": {
@@ -4203,6 +9623,9 @@ const docTemplate = `{
                 },
                 "price": {
                     "type": "number"
+                },
+                "supplier_id": {
+                    "type": "string"
                 }
             }
         },
@@ -4212,6 +9635,9 @@ const docTemplate = `{
                 "count": {
                     "type": "integer"
                 },
+                "expires_at": {
+                    "type": "string"
+                },
                 "income_id": {
                     "type": "string"
                 },
@@ -4237,6 +9663,41 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdatePromotion": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "promotion_type": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
         "models.UpdateSale": {
             "type": "object",
             "properties": {
@@ -4249,6 +9710,9 @@ const docTemplate = `{
                 "client_name": {
                     "type": "string"
                 },
+                "customer_id": {
+                    "type": "string"
+                },
                 "payment_type": {
                     "type": "string"
                 },
@@ -4257,9 +9721,6 @@ const docTemplate = `{
                 },
                 "shop_assistent_id": {
                     "type": "string"
-                },
-                "status": {
-                    "type": "string"
                 }
             }
         },
@@ -4284,9 +9745,6 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
-                },
                 "tarif_id": {
                     "type": "string"
                 },
@@ -4295,36 +9753,47 @@ const docTemplate = `{
                 }
             }
         },
-        "models.UpdateStorage": {
+        "models.UpdateStaffPassword": {
             "type": "object",
             "properties": {
-                "branch_id": {
+                "new_password": {
                     "type": "string"
                 },
-                "count": {
-                    "type": "integer"
-                },
-                "product_id": {
+                "old_password": {
                     "type": "string"
                 }
             }
         },
-        "models.UpdateStorageTransaction": {
+        "models.UpdateStorage": {
             "type": "object",
             "properties": {
-                "price": {
+                "cost": {
                     "type": "number"
                 },
-                "product_id": {
+                "count": {
+                    "type": "integer"
+                },
+                "min_count": {
+                    "type": "integer"
+                },
+                "reason": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "number"
+                "reorder_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UpdateSupplier": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
                 },
-                "staff_id": {
+                "name": {
                     "type": "string"
                 },
-                "storage_transaction_type": {
+                "phone": {
                     "type": "string"
                 }
             }
@@ -4369,6 +9838,13 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
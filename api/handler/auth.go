@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/pkg/security"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Login godoc
+// @Router       /auth/login [POST]
+// @Summary      Staff login
+// @Description  Check staff credentials and issue access and refresh tokens
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        login  body  models.LoginRequest  true  "login data"
+// @Success      200  {object}  models.LoginResponse
+// @Failure      400  {object}  models.Response
+// @Failure      401  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) Login(c *gin.Context) {
+	loginRequest := models.LoginRequest{}
+
+	if err := c.ShouldBindJSON(&loginRequest); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	staff, err := h.storage.Staff().GetByLogin(c.Request.Context(), loginRequest.Login)
+	if err != nil {
+		handleResponse(c, h.log, "error while getting staff by login", http.StatusUnauthorized, "login or password is incorrect")
+		return
+	}
+
+	if err = security.CompareHashAndPassword(staff.Password, loginRequest.Password); err != nil {
+		h.log.Warning("wrong password on login", logger.String("staff_id", staff.ID))
+		handleResponse(c, h.log, "wrong password", http.StatusUnauthorized, "login or password is incorrect")
+		return
+	}
+
+	response, err := h.issueTokens(models.AuthInfo{
+		StaffID:   staff.ID,
+		BranchID:  staff.BranchID,
+		TypeStaff: staff.TypeStaff,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while generating tokens", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Staff = staff
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
+
+// RefreshToken godoc
+// @Router       /auth/refresh [POST]
+// @Summary      Refresh tokens
+// @Description  Exchange a refresh token for a new pair of tokens
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        refresh  body  models.RefreshTokenRequest  true  "refresh token"
+// @Success      200  {object}  models.LoginResponse
+// @Failure      400  {object}  models.Response
+// @Failure      401  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RefreshToken(c *gin.Context) {
+	request := models.RefreshTokenRequest{}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := security.ExtractClaims(request.RefreshToken, h.cfg.SecretKey)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing refresh token", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if claims.TokenType != security.RefreshToken {
+		handleResponse(c, h.log, "wrong token type", http.StatusUnauthorized, "refresh token is required")
+		return
+	}
+
+	staff, err := h.storage.Staff().Get(c.Request.Context(), models.PrimaryKey{
+		ID: claims.StaffID,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting staff by id", http.StatusUnauthorized, "staff not found")
+		return
+	}
+
+	response, err := h.issueTokens(models.AuthInfo{
+		StaffID:   staff.ID,
+		BranchID:  staff.BranchID,
+		TypeStaff: staff.TypeStaff,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while generating tokens", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Staff = staff
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
+
+func (h Handler) issueTokens(info models.AuthInfo) (models.LoginResponse, error) {
+	claims := security.Claims{
+		StaffID:   info.StaffID,
+		BranchID:  info.BranchID,
+		TypeStaff: info.TypeStaff,
+	}
+
+	claims.TokenType = security.AccessToken
+	accessToken, err := security.GenerateJWT(claims, h.cfg.AccessTokenTTL, h.cfg.SecretKey)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	claims.TokenType = security.RefreshToken
+	refreshToken, err := security.GenerateJWT(claims, h.cfg.RefreshTokenTTL, h.cfg.SecretKey)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return models.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
@@ -2,8 +2,8 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,12 +13,14 @@ import (
 // @Summary      barcode
 // @Description  barcode
 // @Tags         barcode
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param		 info body models.Barcode true "info"
-// @Success      200  {object}  models.Response
+// @Success      200  {object}  models.Basket
 // @Failure      400  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) Barcode(c *gin.Context) {
 	info := models.Barcode{}
@@ -27,148 +29,33 @@ func (h Handler) Barcode(c *gin.Context) {
 		return
 	}
 
-	sale, err := h.storage.Sale().Get(context.Background(), models.PrimaryKey{
-		ID: info.SaleID,
-	})
-	if err != nil {
-		handleResponse(c, h.log, "error is getting sale by id", http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	if sale.Status == "success" {
-		handleResponse(c, h.log, "sale ended", 300, "sale ended cannot add product")
+	if info.Count <= 0 {
+		handleResponse(c, h.log, "count must be positive", http.StatusBadRequest, "count must be positive")
 		return
 	}
 
-	if sale.Status == "cancel" {
-		handleResponse(c, h.log, "sale canceled", 300, "sale canceled cannot add product")
-		return
-	}
-
-	products, err := h.storage.Product().GetList(context.Background(), models.ProductGetListRequest{
-		Page:    1,
-		Limit:   10,
-		Barcode: info.Barcode,
-	})
-
+	product, err := h.storage.Product().GetByBarcode(c.Request.Context(), strconv.Itoa(info.Barcode))
 	if err != nil {
-		handleResponse(c, h.log, "error is while getting product list by barcode", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error is while getting product by barcode", errorStatus(err), err.Error())
 		return
 	}
 
-	var (
-		prodID    string
-		prodPrice int
-	)
-	for _, product := range products.Products {
-		prodID = product.ID
-		prodPrice = int(product.Price)
-	}
-
-	baskets, err := h.storage.Basket().GetList(context.Background(), models.GetBasketsListRequest{
-		Page:   1,
-		Limit:  10,
-		Search: info.SaleID,
+	// the line is priced at the sale's branch price when it is saved
+	id, err := h.storage.Basket().Create(c.Request.Context(), models.CreateBasket{
+		SaleID:    info.SaleID,
+		ProductID: product.ID,
+		Quantity:  info.Count,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error is while getting basket list", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error is while adding product to basket", errorStatus(err), err.Error())
 		return
 	}
 
-	var (
-		basketsMap = make(map[string]models.Basket)
-		totalPrice = 0
-	)
-
-	totalPrice = info.Count * prodPrice
-
-	for _, basket := range baskets.Baskets {
-		basketsMap[basket.ProductID] = basket
-	}
-
-	storage, err := h.storage.Storage().GetList(context.Background(), models.GetListRequest{
-		Page:  1,
-		Limit: 100,
-	})
+	basket, err := h.storage.Basket().Get(c.Request.Context(), models.PrimaryKey{ID: id})
 	if err != nil {
-		handleResponse(c, h.log, "error is while getting repo list", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error is while getting basket", errorStatus(err), err.Error())
 		return
 	}
 
-	for _, r := range storage.Storages {
-		if prodID == basketsMap[r.ProductID].ProductID {
-			if r.Count < (basketsMap[r.ProductID].Quantity + info.Count) {
-				handleResponse(c, h.log, "not enough product", 301, "not enough product")
-				return
-			}
-		}
-
-		if r.Count < info.Count {
-			handleResponse(c, h.log, "not enough product", 300, "not enough product")
-			return
-		}
-	}
-
-	isTrue := false
-
-	for _, value := range basketsMap {
-		if prodID == value.ProductID {
-			isTrue = true
-			id, err := h.storage.Basket().Update(context.Background(), models.UpdateBasket{
-				ID:        value.ID,
-				SaleID:    value.SaleID,
-				ProductID: prodID,
-				Quantity:  value.Quantity + info.Count,
-				Price:     value.Price + float64(totalPrice),
-			})
-			if err != nil {
-				handleResponse(c, h.log, "error is while updating basket", 500, err.Error())
-				return
-			}
-			updatedBasket, err := h.storage.Basket().Get(context.Background(), models.PrimaryKey{ID: id})
-			if err != nil {
-				handleResponse(c, h.log, "error is while getting basket", 500, err.Error())
-				return
-			}
-			handleResponse(c, h.log, "updated", http.StatusOK, updatedBasket)
-		}
-	}
-
-	if !isTrue {
-		id, err := h.storage.Basket().Create(context.Background(), models.CreateBasket{
-			SaleID:    info.SaleID,
-			ProductID: prodID,
-			Quantity:  info.Count,
-			Price:     float64(totalPrice),
-		})
-		if err != nil {
-			handleResponse(c, h.log, "error is while creating basket", 500, err.Error())
-			return
-		}
-		createdBasket, err := h.storage.Basket().Get(context.Background(), models.PrimaryKey{ID: id})
-		if err != nil {
-			handleResponse(c, h.log, "error is while getting basket", 500, err.Error())
-			return
-		}
-		handleResponse(c, h.log, "updated", http.StatusOK, createdBasket)
-	}
+	handleResponse(c, h.log, "updated", http.StatusOK, basket)
 }
-
-/*
-
-
-
-sale_id, barcode, count
-
-
-barcode orqali prod get
-
-count*price=batot
-
-ba = cou
-
-sal = said
-
-
-
-*/
@@ -2,8 +2,9 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
+	"bazaar/pkg/role"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -14,96 +15,40 @@ import (
 // CreateBasket godoc
 // @Router       /basket [POST]
 // @Summary      Create a new basket
-// @Description  Create a new basket
+// @Description  Add a product to an open sale, onto its line for the product when there is one, within the branch stock
 // @Tags         basket
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        basket  body  models.CreateBasket  true  "basket data"
 // @Success      201  {object}  models.Basket
 // @Failure      400  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateBasket(c *gin.Context) {
 	createBasket := models.CreateBasket{}
 
 	if err := c.ShouldBindJSON(&createBasket); err != nil {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
+		return
 	}
 
-	storage, err := h.storage.Storage().GetList(context.Background(), models.GetListRequest{
-		Page:   1,
-		Limit:  10,
-		Search: createBasket.ProductID,
-	})
+	id, err := h.storage.Basket().Create(c.Request.Context(), createBasket)
 	if err != nil {
-		handleResponse(c, h.log, "error while searching product in storage", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while adding product to basket", errorStatus(err), err.Error())
+		return
 	}
 
-	if createBasket.Quantity <= storage.Storages[0].Count {
-
-		basketForProductID, err := h.storage.Basket().GetList(context.Background(), models.GetBasketsListRequest{
-			Page:   1,
-			Limit:  100,
-			Search: createBasket.ProductID,
-		})
-		if err != nil {
-			handleResponse(c, h.log, "error while searching selected basket", http.StatusInternalServerError, "error searching selected basket")
-		}
-
-		basketForSaleID, err := h.storage.Basket().GetList(context.Background(), models.GetBasketsListRequest{
-			Page:   1,
-			Limit:  100,
-			Search: createBasket.SaleID,
-		})
-		if err != nil {
-			handleResponse(c, h.log, "error while searching selected basket", http.StatusInternalServerError, "error searching selected basket")
-		}
-
-		var foundProduct, foundSale bool
-
-		for _, basket := range basketForProductID.Baskets {
-			if basket.ProductID == createBasket.ProductID {
-				foundProduct = true
-				break
-			}
-		}
-
-		for _, basket := range basketForSaleID.Baskets {
-			if basket.SaleID == createBasket.SaleID {
-				foundSale = true
-				break
-			}
-		}
-
-		if foundProduct && foundSale {
-
-			h.storage.Basket().UpdateBasketQuantity(context.Background(), models.UpdateBasketQuantity{
-				ID:       basketForProductID.Baskets[0].ID,
-				Quantity: createBasket.Quantity,
-			})
-
-		} else {
-			id, err := h.storage.Basket().Create(context.Background(), createBasket)
-			if err != nil {
-				handleResponse(c, h.log, "error while creating basket", http.StatusInternalServerError, err)
-				return
-			}
-
-			basket, err := h.storage.Basket().Get(context.Background(), models.PrimaryKey{
-				ID: id,
-			})
-			if err != nil {
-				handleResponse(c, h.log, "error while get basket ", http.StatusInternalServerError, err)
-				return
-			}
-
-			handleResponse(c, h.log, "", http.StatusCreated, basket)
-		}
-
-	} else {
-		handleResponse(c, h.log, "", http.StatusOK, "the number of products is not enough")
+	basket, err := h.storage.Basket().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get basket ", errorStatus(err), err)
+		return
 	}
 
+	handleResponse(c, h.log, "", http.StatusCreated, basket)
 }
 
 // GetBasketByID godoc
@@ -111,6 +56,7 @@ func (h Handler) CreateBasket(c *gin.Context) {
 // @Summary      Get basket by id
 // @Description  Get basket by id
 // @Tags         basket
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "basket"
@@ -130,11 +76,11 @@ func (h Handler) GetBasketByID(c *gin.Context) {
 		return
 	}
 
-	basket, err := h.storage.Basket().Get(context.Background(), models.PrimaryKey{
+	basket, err := h.storage.Basket().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get basket by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get basket by id", errorStatus(err), err)
 		return
 	}
 
@@ -147,6 +93,7 @@ func (h Handler) GetBasketByID(c *gin.Context) {
 // @Summary      Get baskets list
 // @Description  Get baskets list
 // @Tags         basket
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -180,14 +127,14 @@ func (h Handler) GetBasketList(c *gin.Context) {
 
 	search = c.Query("search")
 
-	response, err := h.storage.Basket().GetList(context.Background(), models.GetBasketsListRequest{
+	response, err := h.storage.Basket().GetList(c.Request.Context(), models.GetBasketsListRequest{
 		Page:   page,
 		Limit:  limit,
 		Search: search,
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while getting basket", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting basket", errorStatus(err), err)
 		return
 	}
 
@@ -200,6 +147,7 @@ func (h Handler) GetBasketList(c *gin.Context) {
 // @Summary      Update basket by id
 // @Description  Update basket by id
 // @Tags         basket
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "basket id"
@@ -207,6 +155,7 @@ func (h Handler) GetBasketList(c *gin.Context) {
 // @Success      200  {object}  models.Basket
 // @Failure      400  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateBasket(c *gin.Context) {
 	updateBasket := models.UpdateBasket{}
@@ -224,17 +173,17 @@ func (h Handler) UpdateBasket(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.Basket().Update(context.Background(), updateBasket)
+	id, err := h.storage.Basket().Update(c.Request.Context(), updateBasket)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating basket", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error while updating basket", errorStatus(err), err.Error())
 		return
 	}
 
-	basket, err := h.storage.Basket().Get(context.Background(), models.PrimaryKey{
+	basket, err := h.storage.Basket().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while getting basket by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting basket by id", errorStatus(err), err)
 		return
 	}
 
@@ -247,12 +196,14 @@ func (h Handler) UpdateBasket(c *gin.Context) {
 // @Summary      Delete Basket
 // @Description  Delete Basket
 // @Tags         basket
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "basket id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteBasket(c *gin.Context) {
 
@@ -263,11 +214,77 @@ func (h Handler) DeleteBasket(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Basket().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting basket by id", http.StatusInternalServerError, err.Error())
+	if err = h.storage.Basket().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting basket by id", errorStatus(err), err.Error())
 		return
 	}
 
 	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// SetBasketDiscount godoc
+// @Router       /basket/{id}/discount [PUT]
+// @Summary      Set a manual discount on a basket line
+// @Description  Set a manual discount in percent on a basket line. Cashiers may give up to 10%, branch managers up to 30%
+// @Tags         basket
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "basket id"
+// @Param        discount body models.BasketDiscount true "discount"
+// @Success      200  {object}  models.Basket
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) SetBasketDiscount(c *gin.Context) {
+	discount := models.BasketDiscount{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = c.ShouldBindJSON(&discount); err != nil {
+		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if discount.Percent < 0 || discount.Percent > 100 {
+		handleResponse(c, h.log, "wrong discount", http.StatusBadRequest, "discount must be between 0 and 100 percent")
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if discount.Percent > role.MaxDiscountPercent(authInfo.TypeStaff) {
+		handleResponse(c, h.log, "discount is above the role limit", http.StatusForbidden,
+			fmt.Sprintf("%s may give at most %.0f%% discount", authInfo.TypeStaff, role.MaxDiscountPercent(authInfo.TypeStaff)))
+		return
+	}
+
+	discount.ID = id.String()
+	discount.StaffID = authInfo.StaffID
+
+	if err = h.storage.Basket().SetManualDiscount(c.Request.Context(), discount); err != nil {
+		handleResponse(c, h.log, "error while setting basket discount", errorStatus(err), err.Error())
+		return
+	}
+
+	basket, err := h.storage.Basket().Get(c.Request.Context(), models.PrimaryKey{
+		ID: discount.ID,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting basket by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, basket)
+}
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -16,6 +15,7 @@ import (
 // @Summary      Create a new branch
 // @Description  Create a new branch
 // @Tags         branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        branch  body  models.CreateBranch  true  "branch data"
@@ -30,17 +30,17 @@ func (h Handler) CreateBranch(c *gin.Context) {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
 	}
 
-	id, err := h.storage.Branch().Create(context.Background(), createBranch)
+	id, err := h.storage.Branch().Create(c.Request.Context(), createBranch)
 	if err != nil {
-		handleResponse(c, h.log, "error while creating branch", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while creating branch", errorStatus(err), err)
 		return
 	}
 
-	branch, err := h.storage.Branch().Get(context.Background(), models.PrimaryKey{
+	branch, err := h.storage.Branch().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get branch", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get branch", errorStatus(err), err)
 		return
 	}
 
@@ -53,6 +53,7 @@ func (h Handler) CreateBranch(c *gin.Context) {
 // @Summary      Get branch by id
 // @Description  Get branch by id
 // @Tags         branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "branch"
@@ -72,11 +73,11 @@ func (h Handler) GetBranchByID(c *gin.Context) {
 		return
 	}
 
-	branch, err := h.storage.Branch().Get(context.Background(), models.PrimaryKey{
+	branch, err := h.storage.Branch().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get branch by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get branch by id", errorStatus(err), err)
 		return
 	}
 
@@ -89,6 +90,7 @@ func (h Handler) GetBranchByID(c *gin.Context) {
 // @Summary      Get branchs list
 // @Description  Get branchs list
 // @Tags         branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -122,14 +124,14 @@ func (h Handler) GetBranchList(c *gin.Context) {
 
 	search = c.Query("search")
 
-	response, err := h.storage.Branch().GetList(context.Background(), models.GetListRequest{
+	response, err := h.storage.Branch().GetList(c.Request.Context(), models.GetListRequest{
 		Page:   page,
 		Limit:  limit,
 		Search: search,
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while getting branch", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting branch", errorStatus(err), err)
 		return
 	}
 
@@ -142,6 +144,7 @@ func (h Handler) GetBranchList(c *gin.Context) {
 // @Summary      Update branch by id
 // @Description  Update branch by id
 // @Tags         branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "branch id"
@@ -166,17 +169,17 @@ func (h Handler) UpdateBranch(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.Branch().Update(context.Background(), updateBranch)
+	id, err := h.storage.Branch().Update(c.Request.Context(), updateBranch)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating branch", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error while updating branch", errorStatus(err), err.Error())
 		return
 	}
 
-	branch, err := h.storage.Branch().Get(context.Background(), models.PrimaryKey{
+	branch, err := h.storage.Branch().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while getting branch by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting branch by id", errorStatus(err), err)
 		return
 	}
 
@@ -189,6 +192,7 @@ func (h Handler) UpdateBranch(c *gin.Context) {
 // @Summary      Delete Branch
 // @Description  Delete Branch
 // @Tags         branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "branch id"
@@ -205,8 +209,8 @@ func (h Handler) DeleteBranch(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Branch().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting branch by id", http.StatusInternalServerError, err.Error())
+	if err := h.storage.Branch().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting branch by id", errorStatus(err), err.Error())
 		return
 	}
 
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -16,6 +15,7 @@ import (
 // @Summary      Create a new category
 // @Description  Create a new category
 // @Tags         category
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        category  body  models.CreateCategory  true  "category data"
@@ -30,17 +30,17 @@ func (h Handler) CreateCategory(c *gin.Context) {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
 	}
 
-	id, err := h.storage.Category().Create(context.Background(), createCategory)
+	id, err := h.storage.Category().Create(c.Request.Context(), createCategory)
 	if err != nil {
-		handleResponse(c, h.log, "error while creating category", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while creating category", errorStatus(err), err)
 		return
 	}
 
-	category, err := h.storage.Category().Get(context.Background(), models.PrimaryKey{
+	category, err := h.storage.Category().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get category", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get category", errorStatus(err), err)
 		return
 	}
 
@@ -53,6 +53,7 @@ func (h Handler) CreateCategory(c *gin.Context) {
 // @Summary      Get category by id
 // @Description  Get category by id
 // @Tags         category
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "category"
@@ -71,11 +72,11 @@ func (h Handler) GetCategoryByID(c *gin.Context) {
 		return
 	}
 
-	category, err := h.storage.Category().Get(context.Background(), models.PrimaryKey{
+	category, err := h.storage.Category().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get category by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get category by id", errorStatus(err), err)
 		return
 	}
 
@@ -88,6 +89,7 @@ func (h Handler) GetCategoryByID(c *gin.Context) {
 // @Summary      Get category list
 // @Description  Get category list
 // @Tags         category
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -121,14 +123,14 @@ func (h Handler) GetCategoryList(c *gin.Context) {
 
 	search = c.Query("search")
 
-	response, err := h.storage.Category().GetList(context.Background(), models.GetListRequest{
+	response, err := h.storage.Category().GetList(c.Request.Context(), models.GetListRequest{
 		Page:   page,
 		Limit:  limit,
 		Search: search,
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while getting category", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting category", errorStatus(err), err)
 		return
 	}
 
@@ -141,6 +143,7 @@ func (h Handler) GetCategoryList(c *gin.Context) {
 // @Summary      Update category by id
 // @Description  Update category by id
 // @Tags         category
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "category id"
@@ -165,17 +168,17 @@ func (h Handler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.Category().Update(context.Background(), updateCategory)
+	id, err := h.storage.Category().Update(c.Request.Context(), updateCategory)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating category", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error while updating category", errorStatus(err), err.Error())
 		return
 	}
 
-	category, err := h.storage.Category().Get(context.Background(), models.PrimaryKey{
+	category, err := h.storage.Category().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get category by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get category by id", errorStatus(err), err)
 		return
 	}
 
@@ -188,6 +191,7 @@ func (h Handler) UpdateCategory(c *gin.Context) {
 // @Summary      Delete Category
 // @Description  Delete Category
 // @Tags         category
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "category id"
@@ -199,8 +203,8 @@ func (h Handler) DeleteCategory(c *gin.Context) {
 
 	uid := c.Param("id")
 
-	if err := h.storage.Category().Delete(context.Background(), uid); err != nil {
-		handleResponse(c, h.log, "error while deleting category by id", http.StatusInternalServerError, err.Error())
+	if err := h.storage.Category().Delete(c.Request.Context(), uid); err != nil {
+		handleResponse(c, h.log, "error while deleting category by id", errorStatus(err), err.Error())
 		return
 	}
 
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateCustomer godoc
+// @Router       /customer [POST]
+// @Summary      Create a new customer
+// @Description  Register a customer at the caller's branch
+// @Tags         customer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        customer  body  models.CreateCustomer  true  "customer data"
+// @Success      201  {object}  models.Customer
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateCustomer(c *gin.Context) {
+	createCustomer := models.CreateCustomer{}
+
+	if err := c.ShouldBindJSON(&createCustomer); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if createCustomer.Name == "" || createCustomer.Phone == "" {
+		handleResponse(c, h.log, "name and phone are required", http.StatusBadRequest, "name and phone are required")
+		return
+	}
+
+	id, err := h.storage.Customer().Create(c.Request.Context(), createCustomer)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating customer", errorStatus(err), err.Error())
+		return
+	}
+
+	customer, err := h.storage.Customer().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get customer", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, customer)
+}
+
+// GetCustomerByID godoc
+// @Router       /customer/{id} [GET]
+// @Summary      Get customer by id
+// @Description  Get customer by id
+// @Tags         customer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "customer"
+// @Success      200  {object}  models.Customer
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetCustomerByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	customer, err := h.storage.Customer().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get customer by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, customer)
+}
+
+// GetCustomerList godoc
+// @Router       /customer [GET]
+// @Summary      Get customers list
+// @Description  Get customers list, search matches name or phone
+// @Tags         customer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit"
+// @Param        search query string false "search"
+// @Success      200  {object}  models.CustomersResponse
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetCustomerList(c *gin.Context) {
+
+	var (
+		page, limit int
+		err         error
+	)
+
+	pageStr := c.DefaultQuery("page", "1")
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing page ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing limit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.storage.Customer().GetList(c.Request.Context(), models.GetListRequest{
+		Page:   page,
+		Limit:  limit,
+		Search: c.Query("search"),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting customers", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
+
+// UpdateCustomer godoc
+// @Router       /customer/{id} [PUT]
+// @Summary      Update customer by id
+// @Description  Update customer by id
+// @Tags         customer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "customer id"
+// @Param        customer body models.UpdateCustomer true "customer"
+// @Success      200  {object}  models.Customer
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) UpdateCustomer(c *gin.Context) {
+	updateCustomer := models.UpdateCustomer{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = c.ShouldBindJSON(&updateCustomer); err != nil {
+		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updateCustomer.ID = id.String()
+
+	if _, err = h.storage.Customer().Update(c.Request.Context(), updateCustomer); err != nil {
+		handleResponse(c, h.log, "error while updating customer", errorStatus(err), err.Error())
+		return
+	}
+
+	customer, err := h.storage.Customer().Get(c.Request.Context(), models.PrimaryKey{
+		ID: updateCustomer.ID,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get customer by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, customer)
+}
+
+// DeleteCustomer godoc
+// @Router       /customer/{id} [DELETE]
+// @Summary      Delete Customer
+// @Description  Delete Customer
+// @Tags         customer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "customer id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeleteCustomer(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = h.storage.Customer().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting customer by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
+}
+
+// GetCustomerHistory godoc
+// @Router       /customer/{id}/history [GET]
+// @Summary      Get customer history
+// @Description  Get the customer's sales and loyalty points ledger, newest first
+// @Tags         customer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "customer id"
+// @Param        page query string false "page"
+// @Param        limit query string false "limit"
+// @Success      200  {object}  models.CustomerHistory
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetCustomerHistory(c *gin.Context) {
+
+	var (
+		page, limit int
+		err         error
+	)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pageStr := c.DefaultQuery("page", "1")
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing page ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing limit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	history, err := h.storage.Customer().History(c.Request.Context(), models.GetCustomerHistoryRequest{
+		CustomerID: id.String(),
+		Page:       page,
+		Limit:      limit,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting customer history", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, history)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"bazaar/pkg/salestatus"
+	"bazaar/storage"
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// conflictErrors refuse a change the document is past.
+	conflictErrors = []error{
+		// sales
+		storage.ErrNoReceipt,
+		// purchase orders, transfers, inventory counts, price changes
+		storage.ErrOrderClosed,
+		storage.ErrTransferStatus,
+		storage.ErrCountClosed,
+		storage.ErrPriceChangeStatus,
+		// storage
+		storage.ErrStorageNotEmpty,
+	}
+
+	// badRequestErrors refuse quantities, amounts and filters that don't add
+	// up.
+	badRequestErrors = []error{
+		// sales, baskets and returns
+		storage.ErrNotEnoughProduct,
+		storage.ErrBasketQuantity,
+		storage.ErrReturnQuantity,
+		storage.ErrPaymentTotal,
+		storage.ErrNotEnoughPoints,
+		// purchase orders, incomes, transfers, inventory counts, adjustments
+		storage.ErrOrderQuantity,
+		storage.ErrIncomeQuantity,
+		storage.ErrTransferQuantity,
+		storage.ErrCountQuantity,
+		storage.ErrAdjustmentQuantity,
+		// prices
+		storage.ErrProductPrice,
+		storage.ErrPriceChange,
+		// reports
+		storage.ErrReportFilter,
+		storage.ErrReportGroup,
+	}
+
+	// forbiddenErrors refuse what the caller may not do to the data.
+	forbiddenErrors = []error{
+		storage.ErrBranchAccessDenied,
+		storage.ErrSelfApproval,
+	}
+
+	notFoundErrors = []error{
+		storage.ErrProductNotFound,
+		pgx.ErrNoRows,
+	}
+)
+
+// errorStatus picks the response code for an error returned by the storage.
+// Anything it doesn't know is an internal error.
+func errorStatus(err error) int {
+	var transitionErr salestatus.TransitionError
+
+	switch {
+	case errors.As(err, &transitionErr), isAny(err, conflictErrors):
+		return http.StatusConflict
+	case isAny(err, badRequestErrors):
+		return http.StatusBadRequest
+	case isAny(err, forbiddenErrors):
+		return http.StatusForbidden
+	case isAny(err, notFoundErrors):
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,20 +2,24 @@ package handler
 
 import (
 	"bazaar/api/models"
+	"bazaar/config"
 	"bazaar/pkg/logger"
 	"bazaar/storage"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
 	storage storage.IStorage
+	cfg     config.Config
 	log     logger.ILogger
 }
 
-func New(store storage.IStorage, log logger.ILogger) Handler {
+func New(store storage.IStorage, cfg config.Config, log logger.ILogger) Handler {
 	return Handler{
 		storage: store,
+		cfg:     cfg,
 		log:     log,
 	}
 }
@@ -29,6 +33,12 @@ func handleResponse(c *gin.Context, log logger.ILogger, msg string, statusCode i
 		log.Info("~~~~> OK", logger.String("msg", msg), logger.Any("status", code))
 	case code == 401:
 		response.Description = "Unauthorized"
+	case code == 403:
+		response.Description = "Forbidden"
+	case code == 404:
+		response.Description = "Not Found"
+	case code == 409:
+		response.Description = "Conflict"
 	case code < 500:
 		response.Description = "bad request"
 	default:
@@ -42,3 +52,42 @@ func handleResponse(c *gin.Context, log logger.ILogger, msg string, statusCode i
 	c.JSON(response.StatusCode, response)
 
 }
+
+// parseDateRange reads the fromKey and toKey query parameters with
+// parseMoment. A to day is taken up to its end, so the range includes it.
+func parseDateRange(c *gin.Context, fromKey, toKey string) (from, to time.Time, err error) {
+
+	if value := c.Query(fromKey); value != "" {
+		if from, err = parseMoment(value, false); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	if value := c.Query(toKey); value != "" {
+		if to, err = parseMoment(value, true); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	return from, to, nil
+}
+
+// parseMoment reads an RFC 3339 time or a 2006-01-02 day. A day stands for
+// its start, or for the start of the next day when endOfDay is set.
+func parseMoment(value string, endOfDay bool) (time.Time, error) {
+
+	if moment, err := time.Parse(time.RFC3339, value); err == nil {
+		return moment, nil
+	}
+
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return day, nil
+}
@@ -2,7 +2,7 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
+	"bazaar/storage"
 	"errors"
 	"net/http"
 	"strconv"
@@ -16,6 +16,7 @@ import (
 // @Summary      Create a new income
 // @Description  Create a new income
 // @Tags         income
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        income  body  models.CreateIncome  true  "income data"
@@ -30,17 +31,21 @@ func (h Handler) CreateIncome(c *gin.Context) {
 		handleResponse(c, h.log, "error while reading income body from client", http.StatusBadRequest, err)
 	}
 
-	id, err := h.storage.Income().Create(context.Background(), createIncome)
+	id, err := h.storage.Income().Create(c.Request.Context(), createIncome)
 	if err != nil {
-		handleResponse(c, h.log, "error while creating income", http.StatusInternalServerError, err)
+		if errors.Is(err, storage.ErrBranchAccessDenied) {
+			handleResponse(c, h.log, "branch access denied", http.StatusForbidden, err.Error())
+			return
+		}
+		handleResponse(c, h.log, "error while creating income", errorStatus(err), err)
 		return
 	}
 
-	income, err := h.storage.Income().Get(context.Background(), models.PrimaryKey{
+	income, err := h.storage.Income().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get income", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get income", errorStatus(err), err)
 		return
 	}
 
@@ -53,6 +58,7 @@ func (h Handler) CreateIncome(c *gin.Context) {
 // @Summary      Get income by id
 // @Description  Get income by id
 // @Tags         income
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "income"
@@ -72,11 +78,11 @@ func (h Handler) GetIncomeByID(c *gin.Context) {
 		return
 	}
 
-	income, err := h.storage.Income().Get(context.Background(), models.PrimaryKey{
+	income, err := h.storage.Income().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get income by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get income by id", errorStatus(err), err)
 		return
 	}
 
@@ -89,6 +95,7 @@ func (h Handler) GetIncomeByID(c *gin.Context) {
 // @Summary      Get incomes list
 // @Description  Get incomes list
 // @Tags         income
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -122,14 +129,14 @@ func (h Handler) GetIncomesList(c *gin.Context) {
 
 	search = c.Query("search")
 
-	response, err := h.storage.Income().GetList(context.Background(), models.GetListRequest{
+	response, err := h.storage.Income().GetList(c.Request.Context(), models.GetListRequest{
 		Page:   page,
 		Limit:  limit,
 		Search: search,
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while getting income", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting income", errorStatus(err), err)
 		return
 	}
 
@@ -142,6 +149,7 @@ func (h Handler) GetIncomesList(c *gin.Context) {
 // @Summary      Update income by id
 // @Description  Update income by id
 // @Tags         income
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "income id"
@@ -166,17 +174,17 @@ func (h Handler) UpdateIncome(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.Income().Update(context.Background(), updateIncome)
+	id, err := h.storage.Income().Update(c.Request.Context(), updateIncome)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating income", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error while updating income", errorStatus(err), err.Error())
 		return
 	}
 
-	income, err := h.storage.Income().Get(context.Background(), models.PrimaryKey{
+	income, err := h.storage.Income().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while getting income by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting income by id", errorStatus(err), err)
 		return
 	}
 
@@ -189,6 +197,7 @@ func (h Handler) UpdateIncome(c *gin.Context) {
 // @Summary      Delete Income
 // @Description  Delete Income
 // @Tags         income
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "income id"
@@ -205,8 +214,8 @@ func (h Handler) DeleteIncome(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Income().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting income by id", http.StatusInternalServerError, err.Error())
+	if err := h.storage.Income().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting income by id", errorStatus(err), err.Error())
 		return
 	}
 
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -14,8 +13,9 @@ import (
 // CreateIncomeProduct godoc
 // @Router       /income_product [POST]
 // @Summary      Create a new income product
-// @Description  Create a new income product
+// @Description  Receive a product against an income: the line is recorded, the income branch is restocked and the income price recomputed
 // @Tags         income_product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        income_product  body  models.CreateIncomeProduct  true  "income product data"
@@ -31,61 +31,29 @@ func (h Handler) CreateIncomeProduct(c *gin.Context) {
 		return
 	}
 
-	incomeData, err := h.storage.Income().Get(context.Background(), models.PrimaryKey{
-		ID: createIncomeProduct.IncomeID,
-	})
+	authInfo, err := getAuthInfo(c)
 	if err != nil {
-		handleResponse(c, h.log, "error while search income for create income product", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	storageDataForProduct, err := h.storage.Storage().GetList(context.Background(), models.GetListRequest{
-		Page:   1,
-		Limit:  100,
-		Search: createIncomeProduct.ProductID,
-	})
+	createIncomeProduct.StaffID = authInfo.StaffID
+
+	id, err := h.storage.IncomeProduct().Create(c.Request.Context(), createIncomeProduct)
 	if err != nil {
-		handleResponse(c, h.log, "error while search storage for create income product", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while creating income product", errorStatus(err), err.Error())
 		return
 	}
 
-	storageDataForBranch, err := h.storage.Storage().GetList(context.Background(), models.GetListRequest{
-		Page:   1,
-		Limit:  100,
-		Search: incomeData.BranchID,
+	incomeProduct, err := h.storage.IncomeProduct().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while search storage for create income product", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get income product", errorStatus(err), err)
 		return
 	}
 
-	if createIncomeProduct.ProductID == storageDataForProduct.Storages[0].ProductID && incomeData.BranchID == storageDataForBranch.Storages[0].BranchID {
-
-		if err := h.storage.Storage().UpdateCount(context.Background(), models.UpdateCount{
-			ID:    storageDataForBranch.Storages[0].ID,
-			Count: createIncomeProduct.Count,
-		}); err != nil {
-			handleResponse(c, h.log, "error while update storage count", http.StatusInternalServerError, err)
-			return
-		}
-
-	} else {
-		id, err := h.storage.IncomeProduct().Create(context.Background(), createIncomeProduct)
-		if err != nil {
-			handleResponse(c, h.log, "error while creating income product", http.StatusInternalServerError, err)
-			return
-		}
-
-		incomeProduct, err := h.storage.IncomeProduct().Get(context.Background(), models.PrimaryKey{
-			ID: id,
-		})
-		if err != nil {
-			handleResponse(c, h.log, "error while get income product", http.StatusInternalServerError, err)
-			return
-		}
-
-		handleResponse(c, h.log, "", http.StatusCreated, incomeProduct)
-	}
+	handleResponse(c, h.log, "", http.StatusCreated, incomeProduct)
 }
 
 // GetIncomeProductByID godoc
@@ -93,6 +61,7 @@ func (h Handler) CreateIncomeProduct(c *gin.Context) {
 // @Summary      Get income product by id
 // @Description  Get income product by id
 // @Tags         income_product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "income_product"
@@ -112,11 +81,11 @@ func (h Handler) GetIncomeProductByID(c *gin.Context) {
 		return
 	}
 
-	incomeProduct, err := h.storage.IncomeProduct().Get(context.Background(), models.PrimaryKey{
+	incomeProduct, err := h.storage.IncomeProduct().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get income product by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get income product by id", errorStatus(err), err)
 		return
 	}
 
@@ -129,6 +98,7 @@ func (h Handler) GetIncomeProductByID(c *gin.Context) {
 // @Summary      Get income products list
 // @Description  Get income products list
 // @Tags         income_product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -162,14 +132,14 @@ func (h Handler) GetIncomeProductsList(c *gin.Context) {
 
 	search = c.Query("search")
 
-	response, err := h.storage.IncomeProduct().GetList(context.Background(), models.GetListRequest{
+	response, err := h.storage.IncomeProduct().GetList(c.Request.Context(), models.GetListRequest{
 		Page:   page,
 		Limit:  limit,
 		Search: search,
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while getting income product", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting income product", errorStatus(err), err)
 		return
 	}
 
@@ -180,8 +150,9 @@ func (h Handler) GetIncomeProductsList(c *gin.Context) {
 // UpdateIncome godoc
 // @Router       /income_product/{id} [PUT]
 // @Summary      Update income product by id
-// @Description  Update income product by id
+// @Description  Correct an income line, the old line is taken back off the branch storage and the corrected one received
 // @Tags         income_product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "income id"
@@ -206,17 +177,25 @@ func (h Handler) UpdateIncomeProduct(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.IncomeProduct().Update(context.Background(), updateIncomeProduct)
+	authInfo, err := getAuthInfo(c)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating income product", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	incomeProduct, err := h.storage.IncomeProduct().Get(context.Background(), models.PrimaryKey{
+	updateIncomeProduct.StaffID = authInfo.StaffID
+
+	id, err := h.storage.IncomeProduct().Update(c.Request.Context(), updateIncomeProduct)
+	if err != nil {
+		handleResponse(c, h.log, "error while updating income product", errorStatus(err), err.Error())
+		return
+	}
+
+	incomeProduct, err := h.storage.IncomeProduct().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while getting income by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting income by id", errorStatus(err), err)
 		return
 	}
 
@@ -227,8 +206,9 @@ func (h Handler) UpdateIncomeProduct(c *gin.Context) {
 // DeleteIncomeProduct godoc
 // @Router       /income_product/{id} [DELETE]
 // @Summary      Delete Income
-// @Description  Delete Income
+// @Description  Delete an income line and take its count back off the branch storage
 // @Tags         income_product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "income product id"
@@ -245,8 +225,17 @@ func (h Handler) DeleteIncomeProduct(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.IncomeProduct().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting income product by id", http.StatusInternalServerError, err.Error())
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err = h.storage.IncomeProduct().Delete(c.Request.Context(), models.DeleteIncomeProduct{
+		ID:      id.String(),
+		StaffID: authInfo.StaffID,
+	}); err != nil {
+		handleResponse(c, h.log, "error while deleting income product by id", errorStatus(err), err.Error())
 		return
 	}
 
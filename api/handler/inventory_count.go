@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateInventoryCount godoc
+// @Router       /inventory_count [POST]
+// @Summary      Open an inventory count
+// @Description  Open a stocktake for a branch, optionally for a category and its subcategories. The storage counts at this moment become the expected quantities.
+// @Tags         inventory_count
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        inventory_count  body  models.CreateInventoryCount  true  "inventory count data"
+// @Success      201  {object}  models.InventoryCount
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateInventoryCount(c *gin.Context) {
+	createCount := models.CreateInventoryCount{}
+
+	if err := c.ShouldBindJSON(&createCount); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	createCount.StaffID = authInfo.StaffID
+
+	id, err := h.storage.InventoryCount().Create(c.Request.Context(), createCount)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating inventory count", errorStatus(err), err.Error())
+		return
+	}
+
+	count, err := h.storage.InventoryCount().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get inventory count", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, count)
+}
+
+// GetInventoryCountByID godoc
+// @Router       /inventory_count/{id} [GET]
+// @Summary      Get inventory count by id
+// @Description  Get inventory count with its expected and counted quantities
+// @Tags         inventory_count
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "inventory count"
+// @Success      200  {object}  models.InventoryCount
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetInventoryCountByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	count, err := h.storage.InventoryCount().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get inventory count by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, count)
+}
+
+// GetInventoryCountList godoc
+// @Router       /inventory_count [GET]
+// @Summary      Get inventory counts list
+// @Description  Get inventory counts list
+// @Tags         inventory_count
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit"
+// @Param        status query string false "open or posted"
+// @Success      200  {object}  models.InventoryCountsResponse
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetInventoryCountList(c *gin.Context) {
+
+	var (
+		page, limit int
+		err         error
+	)
+
+	pageStr := c.DefaultQuery("page", "1")
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing page ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing limit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.storage.InventoryCount().GetList(c.Request.Context(), models.GetInventoryCountsListRequest{
+		Page:   page,
+		Limit:  limit,
+		Status: c.Query("status"),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting inventory counts", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
+
+// DeleteInventoryCount godoc
+// @Router       /inventory_count/{id} [DELETE]
+// @Summary      Delete Inventory Count
+// @Description  Delete an inventory count that was not posted
+// @Tags         inventory_count
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "inventory count id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeleteInventoryCount(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = h.storage.InventoryCount().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting inventory count by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
+}
+
+// SubmitInventoryCount godoc
+// @Router       /inventory_count/{id}/products [PUT]
+// @Summary      Submit counted quantities
+// @Description  Record counted quantities by product id or barcode. Counting a product again replaces its quantity.
+// @Tags         inventory_count
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "inventory count id"
+// @Param        products body models.SubmitInventoryCount true "counted products"
+// @Success      200  {object}  models.InventoryCount
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) SubmitInventoryCount(c *gin.Context) {
+	submitCount := models.SubmitInventoryCount{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = c.ShouldBindJSON(&submitCount); err != nil {
+		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	submitCount.ID = id.String()
+
+	if err = h.storage.InventoryCount().Submit(c.Request.Context(), submitCount); err != nil {
+		handleResponse(c, h.log, "error while submitting inventory count", errorStatus(err), err.Error())
+		return
+	}
+
+	count, err := h.storage.InventoryCount().Get(c.Request.Context(), models.PrimaryKey{
+		ID: submitCount.ID,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get inventory count by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, count)
+}
+
+// PostInventoryCount godoc
+// @Router       /inventory_count/{id}/post [PUT]
+// @Summary      Post inventory count
+// @Description  Close the count and adjust the branch storage by the variances of the counted products
+// @Tags         inventory_count
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "inventory count id"
+// @Param        post body models.PostInventoryCount false "adjustment reason"
+// @Success      200  {object}  models.InventoryVariance
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) PostInventoryCount(c *gin.Context) {
+	postCount := models.PostInventoryCount{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if c.Request.ContentLength > 0 {
+		if err = c.ShouldBindJSON(&postCount); err != nil {
+			handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	postCount.ID = id.String()
+	postCount.StaffID = authInfo.StaffID
+
+	if err = h.storage.InventoryCount().Post(c.Request.Context(), postCount); err != nil {
+		handleResponse(c, h.log, "error while posting inventory count", errorStatus(err), err.Error())
+		return
+	}
+
+	report, err := h.storage.InventoryCount().Variance(c.Request.Context(), postCount.ID)
+	if err != nil {
+		handleResponse(c, h.log, "error while getting inventory variance", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, report)
+}
+
+// GetInventoryVariance godoc
+// @Router       /inventory_count/{id}/variance [GET]
+// @Summary      Get inventory variance report
+// @Description  Get the counted products that differ from storage, valued at their last income price
+// @Tags         inventory_count
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "inventory count id"
+// @Success      200  {object}  models.InventoryVariance
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetInventoryVariance(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.storage.InventoryCount().Variance(c.Request.Context(), id.String())
+	if err != nil {
+		handleResponse(c, h.log, "error while getting inventory variance", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, report)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/pkg/role"
+	"bazaar/pkg/scope"
+	"bazaar/pkg/security"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h Handler) AuthenticateMiddleware(c *gin.Context) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" {
+		handleResponse(c, h.log, "authorization header is empty", http.StatusUnauthorized, "unauthorized")
+		c.Abort()
+		return
+	}
+
+	token := strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+
+	claims, err := security.ExtractClaims(token, h.cfg.SecretKey)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing access token", http.StatusUnauthorized, err.Error())
+		c.Abort()
+		return
+	}
+
+	if claims.TokenType != security.AccessToken {
+		handleResponse(c, h.log, "wrong token type", http.StatusUnauthorized, "access token is required")
+		c.Abort()
+		return
+	}
+
+	c.Set("auth", models.AuthInfo{
+		StaffID:   claims.StaffID,
+		BranchID:  claims.BranchID,
+		TypeStaff: claims.TypeStaff,
+	})
+
+	if !role.Has(claims.TypeStaff, role.CrossBranch...) {
+		c.Request = c.Request.WithContext(scope.WithBranch(c.Request.Context(), claims.BranchID))
+	}
+
+	c.Next()
+}
+
+// Permit lets the request through only when the authenticated staff has one
+// of the given roles.
+func (h Handler) Permit(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authInfo, err := getAuthInfo(c)
+		if err != nil {
+			handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+			c.Abort()
+			return
+		}
+
+		if !role.Has(authInfo.TypeStaff, roles...) {
+			h.log.Warning("access denied",
+				logger.String("staff_id", authInfo.StaffID),
+				logger.String("type_staff", authInfo.TypeStaff),
+				logger.String("method", c.Request.Method),
+				logger.String("path", c.FullPath()),
+			)
+			handleResponse(c, h.log, "access denied", http.StatusForbidden, "you don't have permission for this action")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func getAuthInfo(c *gin.Context) (models.AuthInfo, error) {
+	value, exists := c.Get("auth")
+	if !exists {
+		return models.AuthInfo{}, errors.New("unauthorized")
+	}
+
+	authInfo, ok := value.(models.AuthInfo)
+	if !ok {
+		return models.AuthInfo{}, errors.New("unauthorized")
+	}
+
+	return authInfo, nil
+}
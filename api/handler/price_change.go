@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreatePriceChange godoc
+// @Router       /price_change [POST]
+// @Summary      Create a new price change
+// @Description  Draft a percent or absolute change of the prices of a product list or of a category subtree, rounded nearest, up or down to round_to and due at effective_at. Without a branch it changes the product prices of every branch.
+// @Tags         price_change
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        price_change  body  models.CreatePriceChange  true  "price change data"
+// @Success      201  {object}  models.PriceChange
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreatePriceChange(c *gin.Context) {
+	createPriceChange := models.CreatePriceChange{}
+
+	if err := c.ShouldBindJSON(&createPriceChange); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	createPriceChange.StaffID = authInfo.StaffID
+
+	id, err := h.storage.PriceChange().Create(c.Request.Context(), createPriceChange)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating price change", errorStatus(err), err.Error())
+		return
+	}
+
+	priceChange, err := h.storage.PriceChange().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get price change", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, priceChange)
+}
+
+// GetPriceChangeByID godoc
+// @Router       /price_change/{id} [GET]
+// @Summary      Get price change by id
+// @Description  Get price change by id with its products, priced before and after once applied
+// @Tags         price_change
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "price change"
+// @Success      200  {object}  models.PriceChange
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetPriceChangeByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	priceChange, err := h.storage.PriceChange().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get price change by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, priceChange)
+}
+
+// GetPriceChangeList godoc
+// @Router       /price_change [GET]
+// @Summary      Get price changes list
+// @Description  Get price changes list, latest effective first
+// @Tags         price_change
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit"
+// @Param        status query string false "draft, approved or applied"
+// @Success      200  {object}  models.PriceChangesResponse
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetPriceChangeList(c *gin.Context) {
+
+	var (
+		page, limit int
+		err         error
+	)
+
+	pageStr := c.DefaultQuery("page", "1")
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing page ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing limit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.storage.PriceChange().GetList(c.Request.Context(), models.GetPriceChangesListRequest{
+		Page:   page,
+		Limit:  limit,
+		Status: c.Query("status"),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting price changes", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
+
+// DeletePriceChange godoc
+// @Router       /price_change/{id} [DELETE]
+// @Summary      Delete Price Change
+// @Description  Delete a price change that was not applied yet
+// @Tags         price_change
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "price change id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeletePriceChange(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = h.storage.PriceChange().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting price change by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
+}
+
+// ApprovePriceChange godoc
+// @Router       /price_change/{id}/approve [PUT]
+// @Summary      Approve price change
+// @Description  Approve a draft price change as the caller, who can't be its author, the scheduler applies it once it is due
+// @Tags         price_change
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "price change id"
+// @Success      200  {object}  models.PriceChange
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) ApprovePriceChange(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err = h.storage.PriceChange().Approve(c.Request.Context(), models.ApprovePriceChange{
+		ID:      id.String(),
+		StaffID: authInfo.StaffID,
+	}); err != nil {
+		handleResponse(c, h.log, "error while approving price change", errorStatus(err), err.Error())
+		return
+	}
+
+	priceChange, err := h.storage.PriceChange().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get price change by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, priceChange)
+}
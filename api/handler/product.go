@@ -2,7 +2,6 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -16,6 +15,7 @@ import (
 // @Summary      Create a new product
 // @Description  Create a new product
 // @Tags         product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        product  body  models.CreateProduct  true  "product data"
@@ -30,17 +30,25 @@ func (h Handler) CreateProduct(c *gin.Context) {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
 	}
 
-	id, err := h.storage.Product().Create(context.Background(), createProduct)
+	authInfo, err := getAuthInfo(c)
 	if err != nil {
-		handleResponse(c, h.log, "error while creating product", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	product, err := h.storage.Product().Get(context.Background(), models.PrimaryKey{
+	createProduct.StaffID = authInfo.StaffID
+
+	id, err := h.storage.Product().Create(c.Request.Context(), createProduct)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating product", errorStatus(err), err)
+		return
+	}
+
+	product, err := h.storage.Product().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while getting product", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting product", errorStatus(err), err)
 		return
 	}
 
@@ -53,6 +61,7 @@ func (h Handler) CreateProduct(c *gin.Context) {
 // @Summary      Get product by id
 // @Description  Get product by id
 // @Tags         product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "product"
@@ -71,11 +80,11 @@ func (h Handler) GetProductByID(c *gin.Context) {
 		return
 	}
 
-	product, err := h.storage.Product().Get(context.Background(), models.PrimaryKey{
+	product, err := h.storage.Product().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get product by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get product by id", errorStatus(err), err)
 		return
 	}
 
@@ -88,6 +97,7 @@ func (h Handler) GetProductByID(c *gin.Context) {
 // @Summary      Get products list
 // @Description  Get products list
 // @Tags         product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -128,7 +138,7 @@ func (h Handler) GetProductList(c *gin.Context) {
 		return
 	}
 
-	response, err := h.storage.Product().GetList(context.Background(), models.ProductGetListRequest{
+	response, err := h.storage.Product().GetList(c.Request.Context(), models.ProductGetListRequest{
 		Page:    page,
 		Limit:   limit,
 		Search:  search,
@@ -136,7 +146,7 @@ func (h Handler) GetProductList(c *gin.Context) {
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while get product list", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get product list", errorStatus(err), err)
 		return
 	}
 
@@ -147,8 +157,9 @@ func (h Handler) GetProductList(c *gin.Context) {
 // UpdateProduct godoc
 // @Router       /product/{id} [PUT]
 // @Summary      Update product by id
-// @Description  Update product by id
+// @Description  Update product by id, admins only as the product price is the one of every branch without its own
 // @Tags         product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "product id"
@@ -173,17 +184,25 @@ func (h Handler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.Product().Update(context.Background(), updateProduct)
+	authInfo, err := getAuthInfo(c)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating product", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	product, err := h.storage.Product().Get(context.Background(), models.PrimaryKey{
+	updateProduct.StaffID = authInfo.StaffID
+
+	id, err := h.storage.Product().Update(c.Request.Context(), updateProduct)
+	if err != nil {
+		handleResponse(c, h.log, "error while updating product", errorStatus(err), err.Error())
+		return
+	}
+
+	product, err := h.storage.Product().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while update product by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while update product by id", errorStatus(err), err)
 		return
 	}
 
@@ -196,6 +215,7 @@ func (h Handler) UpdateProduct(c *gin.Context) {
 // @Summary      Delete Product
 // @Description  Delete Product
 // @Tags         product
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "product id"
@@ -212,11 +232,91 @@ func (h Handler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Product().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting product", http.StatusInternalServerError, err.Error())
+	if err := h.storage.Product().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting product", errorStatus(err), err.Error())
 		return
 	}
 
 	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// CreateProductPrice godoc
+// @Router       /product/{id}/prices [POST]
+// @Summary      Set a branch price
+// @Description  Override the product price at a branch from the effective time on, at once when none is given. A branch manager sets the price of their own branch.
+// @Tags         product
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "product id"
+// @Param        price body models.CreateProductPrice true "branch price"
+// @Success      201  {object}  models.ProductPrices
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateProductPrice(c *gin.Context) {
+	request := models.CreateProductPrice{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.ProductID = id.String()
+	request.StaffID = authInfo.StaffID
+
+	if _, err = h.storage.Product().CreatePrice(c.Request.Context(), request); err != nil {
+		handleResponse(c, h.log, "error while setting branch price", errorStatus(err), err.Error())
+		return
+	}
+
+	prices, err := h.storage.Product().Prices(c.Request.Context(), request.ProductID)
+	if err != nil {
+		handleResponse(c, h.log, "error while getting product prices", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, prices)
+}
+
+// GetProductPrices godoc
+// @Router       /product/{id}/prices [GET]
+// @Summary      Get product prices
+// @Description  Get the price every branch sells the product at now and the price history, latest effective first
+// @Tags         product
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "product id"
+// @Success      200  {object}  models.ProductPrices
+// @Failure      400  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetProductPrices(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prices, err := h.storage.Product().Prices(c.Request.Context(), id.String())
+	if err != nil {
+		handleResponse(c, h.log, "error while getting product prices", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, prices)
+}
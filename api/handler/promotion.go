@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/promotion"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreatePromotion godoc
+// @Router       /promotion [POST]
+// @Summary      Create a new promotion
+// @Description  Create a percent, fixed or "buy N get M" promotion for a product, category or branch, optionally limited in time
+// @Tags         promotion
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        promotion  body  models.CreatePromotion  true  "promotion data"
+// @Success      201  {object}  models.Promotion
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreatePromotion(c *gin.Context) {
+	createPromotion := models.CreatePromotion{}
+
+	if err := c.ShouldBindJSON(&createPromotion); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validatePromotion(models.Promotion{
+		PromotionType: createPromotion.PromotionType,
+		Value:         createPromotion.Value,
+		BuyQuantity:   createPromotion.BuyQuantity,
+		GetQuantity:   createPromotion.GetQuantity,
+		StartsAt:      createPromotion.StartsAt,
+		EndsAt:        createPromotion.EndsAt,
+	}); err != nil {
+		handleResponse(c, h.log, "wrong promotion", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.storage.Promotion().Create(c.Request.Context(), createPromotion)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating promotion", errorStatus(err), err.Error())
+		return
+	}
+
+	promotion, err := h.storage.Promotion().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get promotion", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, promotion)
+}
+
+// GetPromotionByID godoc
+// @Router       /promotion/{id} [GET]
+// @Summary      Get promotion by id
+// @Description  Get promotion by id
+// @Tags         promotion
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "promotion"
+// @Success      200  {object}  models.Promotion
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetPromotionByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	promotion, err := h.storage.Promotion().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get promotion by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, promotion)
+}
+
+// GetPromotionList godoc
+// @Router       /promotion [GET]
+// @Summary      Get promotions list
+// @Description  Get promotions list
+// @Tags         promotion
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit"
+// @Param        search query string false "search"
+// @Success      200  {object}  models.PromotionsResponse
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetPromotionList(c *gin.Context) {
+
+	var (
+		page, limit int
+		err         error
+	)
+
+	pageStr := c.DefaultQuery("page", "1")
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing page ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing limit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.storage.Promotion().GetList(c.Request.Context(), models.GetListRequest{
+		Page:   page,
+		Limit:  limit,
+		Search: c.Query("search"),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting promotions", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
+
+// UpdatePromotion godoc
+// @Router       /promotion/{id} [PUT]
+// @Summary      Update promotion by id
+// @Description  Update promotion by id
+// @Tags         promotion
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "promotion id"
+// @Param        promotion body models.UpdatePromotion true "promotion"
+// @Success      200  {object}  models.Promotion
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) UpdatePromotion(c *gin.Context) {
+	updatePromotion := models.UpdatePromotion{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = c.ShouldBindJSON(&updatePromotion); err != nil {
+		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updatePromotion.ID = id.String()
+
+	if err = validatePromotion(models.Promotion{
+		PromotionType: updatePromotion.PromotionType,
+		Value:         updatePromotion.Value,
+		BuyQuantity:   updatePromotion.BuyQuantity,
+		GetQuantity:   updatePromotion.GetQuantity,
+		StartsAt:      updatePromotion.StartsAt,
+		EndsAt:        updatePromotion.EndsAt,
+	}); err != nil {
+		handleResponse(c, h.log, "wrong promotion", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err = h.storage.Promotion().Update(c.Request.Context(), updatePromotion); err != nil {
+		handleResponse(c, h.log, "error while updating promotion", errorStatus(err), err.Error())
+		return
+	}
+
+	promotion, err := h.storage.Promotion().Get(c.Request.Context(), models.PrimaryKey{
+		ID: updatePromotion.ID,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get promotion by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, promotion)
+}
+
+// DeletePromotion godoc
+// @Router       /promotion/{id} [DELETE]
+// @Summary      Delete Promotion
+// @Description  Delete Promotion
+// @Tags         promotion
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "promotion id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeletePromotion(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = h.storage.Promotion().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting promotion by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
+}
+
+func validatePromotion(p models.Promotion) error {
+	if !promotion.IsValid(p) {
+		return errors.New("percent promotions take a value up to 100, fixed promotions a positive value and buy_get promotions positive buy and get quantities")
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+
+	return nil
+}
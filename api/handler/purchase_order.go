@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreatePurchaseOrder godoc
+// @Router       /purchase_order [POST]
+// @Summary      Create a new purchase order
+// @Description  Order products from a supplier for a branch
+// @Tags         purchase_order
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        purchase_order  body  models.CreatePurchaseOrder  true  "purchase order data"
+// @Success      201  {object}  models.PurchaseOrder
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreatePurchaseOrder(c *gin.Context) {
+	createOrder := models.CreatePurchaseOrder{}
+
+	if err := c.ShouldBindJSON(&createOrder); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.storage.PurchaseOrder().Create(c.Request.Context(), createOrder)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating purchase order", errorStatus(err), err.Error())
+		return
+	}
+
+	order, err := h.storage.PurchaseOrder().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get purchase order", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, order)
+}
+
+// GetPurchaseOrderByID godoc
+// @Router       /purchase_order/{id} [GET]
+// @Summary      Get purchase order by id
+// @Description  Get purchase order with its lines and the discrepancies found on receipt
+// @Tags         purchase_order
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "purchase order"
+// @Success      200  {object}  models.PurchaseOrder
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetPurchaseOrderByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.storage.PurchaseOrder().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get purchase order by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, order)
+}
+
+// GetPurchaseOrderList godoc
+// @Router       /purchase_order [GET]
+// @Summary      Get purchase orders list
+// @Description  Get purchase orders list
+// @Tags         purchase_order
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit"
+// @Param        supplier_id query string false "supplier_id"
+// @Param        status query string false "ordered, received or cancelled"
+// @Success      200  {object}  models.PurchaseOrdersResponse
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetPurchaseOrderList(c *gin.Context) {
+
+	var (
+		page, limit int
+		err         error
+	)
+
+	pageStr := c.DefaultQuery("page", "1")
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing page ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing limit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.storage.PurchaseOrder().GetList(c.Request.Context(), models.GetPurchaseOrdersListRequest{
+		Page:       page,
+		Limit:      limit,
+		SupplierID: c.Query("supplier_id"),
+		Status:     c.Query("status"),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting purchase orders", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
+
+// ReceivePurchaseOrder godoc
+// @Router       /purchase_order/{id}/receive [PUT]
+// @Summary      Receive purchase order
+// @Description  Book the delivered counts into a new income and the branch storage. Lines left out were not delivered.
+// @Tags         purchase_order
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "purchase order id"
+// @Param        receive body models.ReceivePurchaseOrder true "delivered products"
+// @Success      200  {object}  models.PurchaseOrder
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) ReceivePurchaseOrder(c *gin.Context) {
+	receiveOrder := models.ReceivePurchaseOrder{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = c.ShouldBindJSON(&receiveOrder); err != nil {
+		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	receiveOrder.ID = id.String()
+	receiveOrder.StaffID = authInfo.StaffID
+
+	if err = h.storage.PurchaseOrder().Receive(c.Request.Context(), receiveOrder); err != nil {
+		handleResponse(c, h.log, "error while receiving purchase order", errorStatus(err), err.Error())
+		return
+	}
+
+	order, err := h.storage.PurchaseOrder().Get(c.Request.Context(), models.PrimaryKey{
+		ID: receiveOrder.ID,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get purchase order by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, order)
+}
+
+// CancelPurchaseOrder godoc
+// @Router       /purchase_order/{id}/cancel [PUT]
+// @Summary      Cancel purchase order
+// @Description  Cancel a purchase order that was not received yet
+// @Tags         purchase_order
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "purchase order id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CancelPurchaseOrder(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = h.storage.PurchaseOrder().Cancel(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while cancelling purchase order", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, "purchase order cancelled")
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStockMovementReport godoc
+// @Router       /reports/stock-movement [GET]
+// @Summary      Get stock movement report
+// @Description  Get the opening balance, every movement in and out and the closing balance of a product at a branch
+// @Tags         report
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        branch_id query string false "branch_id, the caller's branch by default"
+// @Param        product_id query string true "product_id"
+// @Param        from query string false "from, 2006-01-02 or RFC 3339"
+// @Param        to query string false "to, 2006-01-02 inclusive or RFC 3339"
+// @Success      200  {object}  models.StockMovementReport
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetStockMovementReport(c *gin.Context) {
+
+	from, to, err := parseDateRange(c, "from", "to")
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing dates", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.storage.Report().StockMovement(c.Request.Context(), models.StockMovementRequest{
+		BranchID:  c.Query("branch_id"),
+		ProductID: c.Query("product_id"),
+		From:      from,
+		To:        to,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting stock movement report", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, report)
+}
+
+// GetStockOnDateReport godoc
+// @Router       /reports/stock-on-date [GET]
+// @Summary      Get stock on date report
+// @Description  Get the quantity of every product per branch as it was at a past moment. A day means its end.
+// @Tags         report
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        date query string false "date, 2006-01-02 or RFC 3339, now by default"
+// @Param        branch_id query string false "branch_id"
+// @Param        product_id query string false "product_id"
+// @Success      200  {object}  models.StockOnDate
+// @Failure      400  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetStockOnDateReport(c *gin.Context) {
+
+	request := models.StockOnDateRequest{
+		BranchID:  c.Query("branch_id"),
+		ProductID: c.Query("product_id"),
+	}
+
+	if value := c.Query("date"); value != "" {
+		at, err := parseMoment(value, true)
+		if err != nil {
+			handleResponse(c, h.log, "error while parsing date", http.StatusBadRequest, err.Error())
+			return
+		}
+		request.At = at
+	}
+
+	report, err := h.storage.Report().StockOnDate(c.Request.Context(), request)
+	if err != nil {
+		handleResponse(c, h.log, "error while getting stock on date report", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, report)
+}
+
+// GetLowStockReport godoc
+// @Router       /reports/low-stock [GET]
+// @Summary      Get low stock report
+// @Description  Get the products below their minimum with a suggested order quantity from the sales of the last days
+// @Tags         report
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        branch_id query string false "branch_id"
+// @Param        days query string false "days of sales to look at, 30 by default"
+// @Success      200  {object}  models.LowStockReport
+// @Failure      400  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetLowStockReport(c *gin.Context) {
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing days", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.storage.Report().LowStock(c.Request.Context(), models.LowStockRequest{
+		BranchID: c.Query("branch_id"),
+		Days:     days,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting low stock report", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, report)
+}
+
+// GetExpiringBatchesReport godoc
+// @Router       /reports/expiring-batches [GET]
+// @Summary      Get expiring batches report
+// @Description  Get the batches in stock per branch that expire within the given days or have already expired
+// @Tags         report
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        branch_id query string false "branch_id"
+// @Param        days query string false "days ahead to look at, 30 by default"
+// @Success      200  {object}  models.ExpiringBatchesReport
+// @Failure      400  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetExpiringBatchesReport(c *gin.Context) {
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing days", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.storage.Report().ExpiringBatches(c.Request.Context(), models.ExpiringBatchesRequest{
+		BranchID: c.Query("branch_id"),
+		Days:     days,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting expiring batches report", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, report)
+}
+
+// GetMarginReport godoc
+// @Router       /reports/margin [GET]
+// @Summary      Get gross margin report
+// @Description  Get the revenue, cost of goods sold and gross margin of completed sales net of returns, grouped by sale, product, category or branch
+// @Tags         report
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        group_by query string false "sale, product, category or branch, product by default"
+// @Param        branch_id query string false "branch_id"
+// @Param        from query string false "from, 2006-01-02 or RFC 3339"
+// @Param        to query string false "to, 2006-01-02 inclusive or RFC 3339"
+// @Success      200  {object}  models.MarginReport
+// @Failure      400  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetMarginReport(c *gin.Context) {
+
+	from, to, err := parseDateRange(c, "from", "to")
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing dates", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.storage.Report().Margin(c.Request.Context(), models.MarginRequest{
+		GroupBy:  c.DefaultQuery("group_by", "product"),
+		BranchID: c.Query("branch_id"),
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting margin report", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, report)
+}
+
+// GetSalesReport godoc
+// @Router       /reports/sales [GET]
+// @Summary      Get sales report
+// @Description  Get the revenue, sales count, items sold and average ticket of sales completed in the period net of returns, grouped by branch, day, hour, cashier, shop_assistant, category (rolled up to the top level) or payment_type, with the top products by revenue
+// @Tags         report
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        group_by query string false "branch, day, hour, cashier, shop_assistant, category or payment_type, day by default"
+// @Param        branch_id query string false "branch_id"
+// @Param        from query string false "from, 2006-01-02 or RFC 3339"
+// @Param        to query string false "to, 2006-01-02 inclusive or RFC 3339"
+// @Param        top query string false "number of top products, 10 by default"
+// @Success      200  {object}  models.SalesReport
+// @Failure      400  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetSalesReport(c *gin.Context) {
+
+	from, to, err := parseDateRange(c, "from", "to")
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing dates", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	top, err := strconv.Atoi(c.DefaultQuery("top", "10"))
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing top", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.storage.Report().Sales(c.Request.Context(), models.SalesReportRequest{
+		GroupBy:  c.DefaultQuery("group_by", "day"),
+		BranchID: c.Query("branch_id"),
+		From:     from,
+		To:       to,
+		Top:      top,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting sales report", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, report)
+}
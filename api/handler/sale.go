@@ -2,8 +2,10 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
+	"bazaar/pkg/receipt"
+	"bazaar/storage"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -16,6 +18,7 @@ import (
 // @Summary      Create a new sale
 // @Description  Create a new sale
 // @Tags         sale
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        sale body  models.CreateSale  true  "sale data"
@@ -28,19 +31,24 @@ func (h Handler) CreateSale(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&createSale); err != nil {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
+		return
 	}
 
-	id, err := h.storage.Sale().Create(context.Background(), createSale)
+	id, err := h.storage.Sale().Create(c.Request.Context(), createSale)
 	if err != nil {
-		handleResponse(c, h.log, "error while creating sale", http.StatusInternalServerError, err)
+		if errors.Is(err, storage.ErrBranchAccessDenied) {
+			handleResponse(c, h.log, "branch access denied", http.StatusForbidden, err.Error())
+			return
+		}
+		handleResponse(c, h.log, "error while creating sale", errorStatus(err), err)
 		return
 	}
 
-	sale, err := h.storage.Sale().Get(context.Background(), models.PrimaryKey{
+	sale, err := h.storage.Sale().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get sale", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get sale", errorStatus(err), err)
 		return
 	}
 
@@ -53,6 +61,7 @@ func (h Handler) CreateSale(c *gin.Context) {
 // @Summary      Get sale by id
 // @Description  Get sale by id
 // @Tags         sale
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "sale"
@@ -71,11 +80,11 @@ func (h Handler) GetSaleByID(c *gin.Context) {
 		return
 	}
 
-	sale, err := h.storage.Sale().Get(context.Background(), models.PrimaryKey{
+	sale, err := h.storage.Sale().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get sale by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get sale by id", errorStatus(err), err.Error())
 		return
 	}
 
@@ -88,6 +97,7 @@ func (h Handler) GetSaleByID(c *gin.Context) {
 // @Summary      Get sales list
 // @Description  Get sales list
 // @Tags         sale
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -121,14 +131,14 @@ func (h Handler) GetSaleList(c *gin.Context) {
 
 	search = c.Query("search")
 
-	response, err := h.storage.Sale().GetList(context.Background(), models.GetListRequest{
+	response, err := h.storage.Sale().GetList(c.Request.Context(), models.GetListRequest{
 		Page:   page,
 		Limit:  limit,
 		Search: search,
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while getting sale", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting sale", errorStatus(err), err)
 		return
 	}
 
@@ -141,6 +151,7 @@ func (h Handler) GetSaleList(c *gin.Context) {
 // @Summary      Update sale by id
 // @Description  Update sale by id
 // @Tags         sale
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "sale id"
@@ -148,6 +159,7 @@ func (h Handler) GetSaleList(c *gin.Context) {
 // @Success      200  {object}  models.Sale
 // @Failure      400  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateSale(c *gin.Context) {
 	updateSale := models.UpdateSale{}
@@ -165,17 +177,17 @@ func (h Handler) UpdateSale(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.Sale().Update(context.Background(), updateSale)
+	id, err := h.storage.Sale().Update(c.Request.Context(), updateSale)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating sale", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error while updating sale", errorStatus(err), err.Error())
 		return
 	}
 
-	sale, err := h.storage.Sale().Get(context.Background(), models.PrimaryKey{
+	sale, err := h.storage.Sale().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get sale by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get sale by id", errorStatus(err), err.Error())
 		return
 	}
 
@@ -188,12 +200,14 @@ func (h Handler) UpdateSale(c *gin.Context) {
 // @Summary      Delete Sale
 // @Description  Delete Sale
 // @Tags         sale
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "sale id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteSale(c *gin.Context) {
 
@@ -204,11 +218,95 @@ func (h Handler) DeleteSale(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Sale().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting sale by id", http.StatusInternalServerError, err.Error())
+	if err := h.storage.Sale().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting sale by id", errorStatus(err), err.Error())
 		return
 	}
 
 	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// GetSalePayments godoc
+// @Router       /sale/{id}/payments [GET]
+// @Summary      Get sale payments
+// @Description  Get the card and cash tenders a sale was paid with
+// @Tags         sale
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "sale id"
+// @Success      200  {object}  []models.SalePayment
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetSalePayments(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payments, err := h.storage.Sale().GetPayments(c.Request.Context(), id.String())
+	if err != nil {
+		handleResponse(c, h.log, "error while getting sale payments", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, payments)
+}
+
+// GetSaleReceipt godoc
+// @Router       /sale/{id}/receipt [GET]
+// @Summary      Get sale receipt
+// @Description  Get the receipt of a completed sale as JSON, as 80mm thermal printer text or as PDF, a sale cancelled or refunded since is marked with its status
+// @Tags         sale
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Produce      plain
+// @Produce      application/pdf
+// @Param        id path string true "sale id"
+// @Param        format query string false "json, text or pdf"
+// @Success      200  {object}  models.Receipt
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetSaleReceipt(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "text" && format != "pdf" {
+		handleResponse(c, h.log, "wrong receipt format", http.StatusBadRequest, "format must be json, text or pdf")
+		return
+	}
+
+	saleReceipt, err := h.storage.Sale().GetReceipt(c.Request.Context(), id.String())
+	if err != nil {
+		handleResponse(c, h.log, "error while getting sale receipt", errorStatus(err), err.Error())
+		return
+	}
+
+	switch format {
+	case "text":
+		c.String(http.StatusOK, receipt.Text(saleReceipt))
+	case "pdf":
+		pdf, err := receipt.PDF(saleReceipt)
+		if err != nil {
+			handleResponse(c, h.log, "error while rendering sale receipt", http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="receipt-%d.pdf"`, saleReceipt.ReceiptNumber))
+		c.Data(http.StatusOK, "application/pdf", pdf)
+	default:
+		handleResponse(c, h.log, "", http.StatusOK, saleReceipt)
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateSaleReturn godoc
+// @Router       /sale/{id}/returns [POST]
+// @Summary      Return products of a sale
+// @Description  Return products of a completed sale, restock the branch, withdraw staff commissions and earned points proportionally and refund the points tender share as points
+// @Tags         sale_return
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "sale id"
+// @Param        sale_return body models.CreateSaleReturn true "returned basket lines"
+// @Success      201  {object}  models.SaleReturn
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateSaleReturn(c *gin.Context) {
+	createSaleReturn := models.CreateSaleReturn{}
+
+	saleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = c.ShouldBindJSON(&createSaleReturn); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if createSaleReturn.PaymentType != "cash" && createSaleReturn.PaymentType != "card" {
+		handleResponse(c, h.log, "wrong payment type", http.StatusBadRequest, "payment type must be cash or card")
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	createSaleReturn.SaleID = saleID.String()
+	createSaleReturn.StaffID = authInfo.StaffID
+
+	id, err := h.storage.SaleReturn().Create(c.Request.Context(), createSaleReturn)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating sale return", errorStatus(err), err.Error())
+		return
+	}
+
+	saleReturn, err := h.storage.SaleReturn().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting sale return", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, saleReturn)
+}
+
+// GetSaleReturnList godoc
+// @Router       /sale/{id}/returns [GET]
+// @Summary      Get returns of a sale
+// @Description  Get returns of a sale
+// @Tags         sale_return
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "sale id"
+// @Param        page query string false "page"
+// @Param        limit query string false "limit"
+// @Success      200  {object}  models.SaleReturnsResponse
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetSaleReturnList(c *gin.Context) {
+
+	var (
+		page, limit int
+		err         error
+	)
+
+	saleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pageStr := c.DefaultQuery("page", "1")
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing page ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing limit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.storage.SaleReturn().GetList(c.Request.Context(), models.GetSaleReturnsListRequest{
+		Page:   page,
+		Limit:  limit,
+		SaleID: saleID.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting sale returns", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
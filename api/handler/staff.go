@@ -2,7 +2,10 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
+	"bazaar/pkg/logger"
+	"bazaar/pkg/role"
+	"bazaar/pkg/security"
+	"bazaar/storage"
 	"errors"
 	"net/http"
 	"strconv"
@@ -16,11 +19,13 @@ import (
 // @Summary      Create a new staff
 // @Description  Create a new staff
 // @Tags         staff
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        staff  body  models.CreateStaff  true  "staff data"
 // @Success      201  {object}  models.Staff
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateStaff(c *gin.Context) {
@@ -28,19 +33,28 @@ func (h Handler) CreateStaff(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&createStaff); err != nil {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
+		return
+	}
+
+	if !h.checkTypeStaff(c, createStaff.TypeStaff, nil) {
+		return
 	}
 
-	id, err := h.storage.Staff().Create(context.Background(), createStaff)
+	id, err := h.storage.Staff().Create(c.Request.Context(), createStaff)
 	if err != nil {
-		handleResponse(c, h.log, "error while creating staff", http.StatusInternalServerError, err)
+		if errors.Is(err, storage.ErrBranchAccessDenied) {
+			handleResponse(c, h.log, "branch access denied", http.StatusForbidden, err.Error())
+			return
+		}
+		handleResponse(c, h.log, "error while creating staff", errorStatus(err), err)
 		return
 	}
 
-	staff, err := h.storage.Staff().Get(context.Background(), models.PrimaryKey{
+	staff, err := h.storage.Staff().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get product", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get product", errorStatus(err), err)
 		return
 	}
 
@@ -53,6 +67,7 @@ func (h Handler) CreateStaff(c *gin.Context) {
 // @Summary      Get staff by id
 // @Description  Get staff by id
 // @Tags         staff
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "staff"
@@ -72,11 +87,11 @@ func (h Handler) GetStaffByID(c *gin.Context) {
 		return
 	}
 
-	staff, err := h.storage.Staff().Get(context.Background(), models.PrimaryKey{
+	staff, err := h.storage.Staff().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get staff by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get staff by id", errorStatus(err), err)
 		return
 	}
 
@@ -89,6 +104,7 @@ func (h Handler) GetStaffByID(c *gin.Context) {
 // @Summary      Get staffs list
 // @Description  Get staffs list
 // @Tags         staff
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -122,14 +138,14 @@ func (h Handler) GetStaffList(c *gin.Context) {
 
 	search = c.Query("search")
 
-	response, err := h.storage.Staff().GetList(context.Background(), models.GetListRequest{
+	response, err := h.storage.Staff().GetList(c.Request.Context(), models.GetListRequest{
 		Page:   page,
 		Limit:  limit,
 		Search: search,
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while getting staff", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting staff", errorStatus(err), err)
 		return
 	}
 
@@ -142,12 +158,14 @@ func (h Handler) GetStaffList(c *gin.Context) {
 // @Summary      Update staff by id
 // @Description  Update staff by id
 // @Tags         staff
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "staff id"
 // @Param        staff body models.UpdateStaff true "staff"
 // @Success      200  {object}  models.Staff
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateStaff(c *gin.Context) {
@@ -166,17 +184,29 @@ func (h Handler) UpdateStaff(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.Staff().Update(context.Background(), updateStaff)
+	target, err := h.storage.Staff().Get(c.Request.Context(), models.PrimaryKey{
+		ID: updateStaff.ID,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get staff by id", errorStatus(err), err.Error())
+		return
+	}
+
+	if !h.checkTypeStaff(c, updateStaff.TypeStaff, &target) {
+		return
+	}
+
+	id, err := h.storage.Staff().Update(c.Request.Context(), updateStaff)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating staff", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error while updating staff", errorStatus(err), err.Error())
 		return
 	}
 
-	staff, err := h.storage.Staff().Get(context.Background(), models.PrimaryKey{
+	staff, err := h.storage.Staff().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get staff by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get staff by id", errorStatus(err), err)
 		return
 	}
 
@@ -188,6 +218,7 @@ func (h Handler) UpdateStaff(c *gin.Context) {
 // @Summary      Delete Staff
 // @Description  Delete Staff
 // @Tags         staff
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "staff id"
@@ -204,11 +235,130 @@ func (h Handler) DeleteStaff(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Staff().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting staff", http.StatusInternalServerError, err.Error())
+	if err := h.storage.Staff().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting staff", errorStatus(err), err.Error())
 		return
 	}
 
 	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// ChangeStaffPassword godoc
+// @Router       /staff/{id}/password [PUT]
+// @Summary      Change staff password
+// @Description  Change staff password, the old password is required to change your own one, managers reset the password of other staff without it, only admins reset the password of managers and admins
+// @Tags         staff
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "staff id"
+// @Param        password body models.UpdateStaffPassword true "old and new password"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      401  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) ChangeStaffPassword(c *gin.Context) {
+	updatePassword := models.UpdateStaffPassword{}
+
+	uid := c.Param("id")
+	id, err := uuid.Parse(uid)
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if authInfo.StaffID != id.String() && !role.Has(authInfo.TypeStaff, role.BranchManager, role.Admin) {
+		h.log.Warning("access denied", logger.String("staff_id", authInfo.StaffID), logger.String("target_staff_id", id.String()))
+		handleResponse(c, h.log, "access denied", http.StatusForbidden, "you can change only your own password")
+		return
+	}
+
+	if err = c.ShouldBindJSON(&updatePassword); err != nil {
+		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if updatePassword.NewPassword == "" {
+		handleResponse(c, h.log, "new password is empty", http.StatusBadRequest, "new password is required")
+		return
+	}
+
+	// A manager resets the password of another staff without knowing it.
+	if authInfo.StaffID != id.String() {
+		target, err := h.storage.Staff().Get(c.Request.Context(), models.PrimaryKey{
+			ID: id.String(),
+		})
+		if err != nil {
+			handleResponse(c, h.log, "error while get staff by id", errorStatus(err), err.Error())
+			return
+		}
+
+		if authInfo.TypeStaff != role.Admin && role.Has(target.TypeStaff, role.BranchManager, role.Admin) {
+			h.log.Warning("access denied", logger.String("staff_id", authInfo.StaffID), logger.String("target_staff_id", id.String()))
+			handleResponse(c, h.log, "access denied", http.StatusForbidden, "only admins can reset the password of managers and admins")
+			return
+		}
+	} else {
+		oldPassword, err := h.storage.Staff().GetPassword(c.Request.Context(), id.String())
+		if err != nil {
+			handleResponse(c, h.log, "error while getting staff password", errorStatus(err), err.Error())
+			return
+		}
+
+		if err = security.CompareHashAndPassword(oldPassword, updatePassword.OldPassword); err != nil {
+			handleResponse(c, h.log, "old password is incorrect", http.StatusUnauthorized, "old password is incorrect")
+			return
+		}
+	}
+
+	if err = h.storage.Staff().UpdatePassword(c.Request.Context(), id.String(), updatePassword.NewPassword); err != nil {
+		handleResponse(c, h.log, "error while updating staff password", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, "password succesfully updated")
+}
+
+// checkTypeStaff rejects an unknown role, and unless the caller is an admin
+// any role above shop staff and any change to a target that holds one, so a
+// branch manager can't create, promote or demote managers and admins. Target
+// is the staff being changed, nil for a new one.
+func (h Handler) checkTypeStaff(c *gin.Context, typeStaff string, target *models.Staff) bool {
+	if !role.IsValid(typeStaff) {
+		handleResponse(c, h.log, "invalid type staff", http.StatusBadRequest, "type_staff is not valid")
+		return false
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return false
+	}
+
+	if authInfo.TypeStaff == role.Admin {
+		return true
+	}
+
+	if role.Has(typeStaff, role.BranchManager, role.Admin) {
+		h.log.Warning("access denied", logger.String("staff_id", authInfo.StaffID), logger.String("type_staff", typeStaff))
+		handleResponse(c, h.log, "access denied", http.StatusForbidden, "only admins can give this role")
+		return false
+	}
+
+	if target != nil && role.Has(target.TypeStaff, role.BranchManager, role.Admin) {
+		h.log.Warning("access denied", logger.String("staff_id", authInfo.StaffID), logger.String("target_staff_id", target.ID))
+		handleResponse(c, h.log, "access denied", http.StatusForbidden, "only admins can change managers and admins")
+		return false
+	}
+
+	return true
+}
@@ -2,7 +2,8 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
+	"bazaar/storage"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,6 +14,7 @@ import (
 // @Summary      sell
 // @Description  sell
 // @Tags         sell
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param 		 sell body models.CreateSale false "sell"
@@ -28,17 +30,21 @@ func (h Handler) StartSell(c *gin.Context) {
 		return
 	}
 
-	saleID, err := h.storage.Sale().Create(context.Background(), sell)
+	saleID, err := h.storage.Sale().Create(c.Request.Context(), sell)
 	if err != nil {
-		handleResponse(c, h.log, "error is while creating sale", http.StatusInternalServerError, err.Error())
+		if errors.Is(err, storage.ErrBranchAccessDenied) {
+			handleResponse(c, h.log, "branch access denied", http.StatusForbidden, err.Error())
+			return
+		}
+		handleResponse(c, h.log, "error is while creating sale", errorStatus(err), err.Error())
 		return
 	}
 
-	sale, err := h.storage.Sale().Get(context.Background(), models.PrimaryKey{
+	sale, err := h.storage.Sale().Get(c.Request.Context(), models.PrimaryKey{
 		ID: saleID,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error is while getting sale by id", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error is while getting sale by id", errorStatus(err), err.Error())
 		return
 	}
 
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -14,8 +13,9 @@ import (
 // CreateStorage godoc
 // @Router       /storage [POST]
 // @Summary      Create a new storage
-// @Description  Create a new storage
+// @Description  Open the storage of a product at a branch, an opening count is posted to the ledger as an adjustment
 // @Tags         storage
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        storage  body  models.CreateStorage  true  "storage data"
@@ -28,19 +28,28 @@ func (h Handler) CreateStorage(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&createStorage); err != nil {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
 	}
 
-	id, err := h.storage.Storage().Create(context.Background(), createStorage)
+	createStorage.StaffID = authInfo.StaffID
+
+	id, err := h.storage.Storage().Create(c.Request.Context(), createStorage)
 	if err != nil {
-		handleResponse(c, h.log, "error while creating storage", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while creating storage", errorStatus(err), err.Error())
 		return
 	}
 
-	storage, err := h.storage.Storage().Get(context.Background(), models.PrimaryKey{
+	storage, err := h.storage.Storage().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get storage", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get storage", errorStatus(err), err)
 		return
 	}
 
@@ -53,6 +62,7 @@ func (h Handler) CreateStorage(c *gin.Context) {
 // @Summary      Get storage by id
 // @Description  Get storage by id
 // @Tags         storage
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "storage"
@@ -71,11 +81,11 @@ func (h Handler) GetStorageByID(c *gin.Context) {
 		return
 	}
 
-	storage, err := h.storage.Storage().Get(context.Background(), models.PrimaryKey{
+	storage, err := h.storage.Storage().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get storage by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get storage by id", errorStatus(err), err)
 		return
 	}
 
@@ -88,6 +98,7 @@ func (h Handler) GetStorageByID(c *gin.Context) {
 // @Summary      Get storages list
 // @Description  Get storages list
 // @Tags         storage
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -121,14 +132,14 @@ func (h Handler) GetStorageList(c *gin.Context) {
 
 	search = c.Query("search")
 
-	response, err := h.storage.Storage().GetList(context.Background(), models.GetListRequest{
+	response, err := h.storage.Storage().GetList(c.Request.Context(), models.GetListRequest{
 		Page:   page,
 		Limit:  limit,
 		Search: search,
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while getting storage", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting storage", errorStatus(err), err)
 		return
 	}
 
@@ -139,8 +150,9 @@ func (h Handler) GetStorageList(c *gin.Context) {
 // UpdateStorage godoc
 // @Router       /storage/{id} [PUT]
 // @Summary      Update storage by id
-// @Description  Update storage by id
+// @Description  Set the stock thresholds, a given count is reached with an adjustment posted to the ledger
 // @Tags         storage
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "storage id"
@@ -163,19 +175,26 @@ func (h Handler) UpdateStorage(c *gin.Context) {
 		return
 	}
 
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	updateStorage.ID = uid
+	updateStorage.StaffID = authInfo.StaffID
 
-	id, err := h.storage.Storage().Update(context.Background(), updateStorage)
+	id, err := h.storage.Storage().Update(c.Request.Context(), updateStorage)
 	if err != nil {
-		handleResponse(c, h.log, "error while reading body", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error while updating storage", errorStatus(err), err.Error())
 		return
 	}
 
-	storage, err := h.storage.Storage().Get(context.Background(), models.PrimaryKey{
+	storage, err := h.storage.Storage().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while getting storage by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting storage by id", errorStatus(err), err)
 		return
 	}
 
@@ -183,17 +202,19 @@ func (h Handler) UpdateStorage(c *gin.Context) {
 
 }
 
-// DeleteStorageTransaction godoc
+// DeleteStorage godoc
 // @Router       /storage/{id} [DELETE]
 // @Summary      Delete Storage
-// @Description  Delete Storage
+// @Description  Delete a storage whose count is adjusted to zero
 // @Tags         storage
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "storage id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteStorage(c *gin.Context) {
 
@@ -204,8 +225,8 @@ func (h Handler) DeleteStorage(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Storage().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting storage by id", http.StatusInternalServerError, err.Error())
+	if err := h.storage.Storage().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting storage by id", errorStatus(err), err.Error())
 		return
 	}
 
@@ -2,8 +2,6 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -14,8 +12,9 @@ import (
 // CreateStorageTransaction godoc
 // @Router       /storage_transaction [POST]
 // @Summary      Create a new storage_transaction
-// @Description  Create a new storage_transaction
+// @Description  Adjust the branch storage by hand, recorded in the ledger as an adjustment
 // @Tags         storage_transaction
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        storage_transaction  body  models.CreateStorageTransaction  true  "storage transaction  data"
@@ -30,17 +29,17 @@ func (h Handler) CreateStorageTransaction(c *gin.Context) {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
 	}
 
-	id, err := h.storage.StorageTransaction().Create(context.Background(), createStorageTransaction)
+	id, err := h.storage.StorageTransaction().Create(c.Request.Context(), createStorageTransaction)
 	if err != nil {
-		handleResponse(c, h.log, "error while creating storage transaction", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while creating storage transaction", errorStatus(err), err.Error())
 		return
 	}
 
-	storageTransaction, err := h.storage.StorageTransaction().Get(context.Background(), models.PrimaryKey{
+	storageTransaction, err := h.storage.StorageTransaction().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get storage transaction", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get storage transaction", errorStatus(err), err)
 		return
 	}
 
@@ -53,6 +52,7 @@ func (h Handler) CreateStorageTransaction(c *gin.Context) {
 // @Summary      Get storage transaction by id
 // @Description  Get storage transaction by id
 // @Tags         storage_transaction
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "storage transaction"
@@ -71,11 +71,11 @@ func (h Handler) GetStorageTransactionByID(c *gin.Context) {
 		return
 	}
 
-	storageTransaction, err := h.storage.StorageTransaction().Get(context.Background(), models.PrimaryKey{
+	storageTransaction, err := h.storage.StorageTransaction().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get storage transaction by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get storage transaction by id", errorStatus(err), err)
 		return
 	}
 
@@ -86,13 +86,20 @@ func (h Handler) GetStorageTransactionByID(c *gin.Context) {
 // GetStorageTransactionsList godoc
 // @Router       /storage_transaction [GET]
 // @Summary      Get storage_transactions list
-// @Description  Get storage_transactions list
+// @Description  Get the stock ledger oldest first, each movement with the storage count left after it
 // @Tags         storage_transaction
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
 // @Param        limit query string false "limit"
-// @Param        search query string false "search"
+// @Param        branch_id query string false "branch_id"
+// @Param        product_id query string false "product_id"
+// @Param        storage_transaction_type query string false "plus or minus"
+// @Param        source_type query string false "sale, sale_return, income, transfer, inventory_count or adjustment"
+// @Param        source_id query string false "source document id"
+// @Param        from_date query string false "from date, 2006-01-02"
+// @Param        to_date query string false "to date inclusive, 2006-01-02"
 // @Success      200  {object}  models.StorageTransactionsResponse
 // @Failure      400  {object}  models.Response
 // @Failure      404  {object}  models.Response
@@ -101,7 +108,6 @@ func (h Handler) GetStorageTransactionList(c *gin.Context) {
 
 	var (
 		page, limit int
-		search      string
 		err         error
 	)
 
@@ -119,95 +125,29 @@ func (h Handler) GetStorageTransactionList(c *gin.Context) {
 		return
 	}
 
-	search = c.Query("search")
-
-	response, err := h.storage.StorageTransaction().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
-	})
-
+	fromDate, toDate, err := parseDateRange(c, "from_date", "to_date")
 	if err != nil {
-		handleResponse(c, h.log, "error while get storage transaction list", http.StatusInternalServerError, err)
-		return
-	}
-
-	handleResponse(c, h.log, "", http.StatusOK, response)
-
-}
-
-// UpdateStorageTransaction godoc
-// @Router       /storage_transaction/{id} [PUT]
-// @Summary      Update storage transaction by id
-// @Description  Update storage transaction by id
-// @Tags         storage_transaction
-// @Accept       json
-// @Produce      json
-// @Param        id path string true "storage transaction id"
-// @Param        storage_transaction body models.UpdateStorageTransaction true "storage_transaction"
-// @Success      200  {object}  models.StorageTransaction
-// @Failure      400  {object}  models.Response
-// @Failure      404  {object}  models.Response
-// @Failure      500  {object}  models.Response
-func (h Handler) UpdateStorageTransaction(c *gin.Context) {
-	updateStorageTransaction := models.UpdateStorageTransaction{}
-	uid := c.Param("id")
-	if uid == "" {
-		handleResponse(c, h.log, "invalid uuid", http.StatusBadRequest, errors.New("uuid is not valid"))
+		handleResponse(c, h.log, "error while parsing dates", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	updateStorageTransaction.ID = uid
-
-	if err := c.ShouldBindJSON(&updateStorageTransaction); err != nil {
-		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
-		return
-	}
-
-	id, err := h.storage.StorageTransaction().Update(context.Background(), updateStorageTransaction)
-	if err != nil {
-		handleResponse(c, h.log, "error while reading body", http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	storageTransaction, err := h.storage.StorageTransaction().Get(context.Background(), models.PrimaryKey{
-		ID: id,
+	response, err := h.storage.StorageTransaction().GetList(c.Request.Context(), models.GetStorageTransactionsListRequest{
+		Page:                   page,
+		Limit:                  limit,
+		BranchID:               c.Query("branch_id"),
+		ProductID:              c.Query("product_id"),
+		StorageTransactionType: c.Query("storage_transaction_type"),
+		SourceType:             c.Query("source_type"),
+		SourceID:               c.Query("source_id"),
+		FromDate:               fromDate,
+		ToDate:                 toDate,
 	})
-	if err != nil {
-		handleResponse(c, h.log, "error while updating storage transaction", http.StatusInternalServerError, err)
-		return
-	}
 
-	handleResponse(c, h.log, "", http.StatusOK, storageTransaction)
-
-}
-
-// DeleteStorageTransaction godoc
-// @Router       /storage_transaction/{id} [DELETE]
-// @Summary      Delete Storage Transaction
-// @Description  Delete Storage Transaction
-// @Tags         storage_transaction
-// @Accept       json
-// @Produce      json
-// @Param        id path string true "storage transaction id"
-// @Success      200  {object}  models.Response
-// @Failure      400  {object}  models.Response
-// @Failure      404  {object}  models.Response
-// @Failure      500  {object}  models.Response
-func (h Handler) DeleteStorageTransaction(c *gin.Context) {
-
-	uid := c.Param("id")
-	id, err := uuid.Parse(uid)
 	if err != nil {
-		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		handleResponse(c, h.log, "error while get storage transaction list", errorStatus(err), err)
 		return
 	}
 
-	if err := h.storage.StorageTransaction().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting storage transaction by id", http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
+	handleResponse(c, h.log, "", http.StatusOK, response)
 
 }
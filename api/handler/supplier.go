@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateSupplier godoc
+// @Router       /supplier [POST]
+// @Summary      Create a new supplier
+// @Description  Create a new supplier
+// @Tags         supplier
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        supplier  body  models.CreateSupplier  true  "supplier data"
+// @Success      201  {object}  models.Supplier
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateSupplier(c *gin.Context) {
+	createSupplier := models.CreateSupplier{}
+
+	if err := c.ShouldBindJSON(&createSupplier); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if createSupplier.Name == "" {
+		handleResponse(c, h.log, "name is required", http.StatusBadRequest, "name is required")
+		return
+	}
+
+	id, err := h.storage.Supplier().Create(c.Request.Context(), createSupplier)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating supplier", errorStatus(err), err.Error())
+		return
+	}
+
+	supplier, err := h.storage.Supplier().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get supplier", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, supplier)
+}
+
+// GetSupplierByID godoc
+// @Router       /supplier/{id} [GET]
+// @Summary      Get supplier by id
+// @Description  Get supplier by id
+// @Tags         supplier
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "supplier"
+// @Success      200  {object}  models.Supplier
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetSupplierByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	supplier, err := h.storage.Supplier().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get supplier by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, supplier)
+}
+
+// GetSupplierList godoc
+// @Router       /supplier [GET]
+// @Summary      Get suppliers list
+// @Description  Get suppliers list, search matches name or phone
+// @Tags         supplier
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit"
+// @Param        search query string false "search"
+// @Success      200  {object}  models.SuppliersResponse
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetSupplierList(c *gin.Context) {
+
+	var (
+		page, limit int
+		err         error
+	)
+
+	pageStr := c.DefaultQuery("page", "1")
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing page ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing limit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.storage.Supplier().GetList(c.Request.Context(), models.GetListRequest{
+		Page:   page,
+		Limit:  limit,
+		Search: c.Query("search"),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting suppliers", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
+
+// UpdateSupplier godoc
+// @Router       /supplier/{id} [PUT]
+// @Summary      Update supplier by id
+// @Description  Update supplier by id
+// @Tags         supplier
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "supplier id"
+// @Param        supplier body models.UpdateSupplier true "supplier"
+// @Success      200  {object}  models.Supplier
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) UpdateSupplier(c *gin.Context) {
+	updateSupplier := models.UpdateSupplier{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = c.ShouldBindJSON(&updateSupplier); err != nil {
+		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updateSupplier.ID = id.String()
+
+	if _, err = h.storage.Supplier().Update(c.Request.Context(), updateSupplier); err != nil {
+		handleResponse(c, h.log, "error while updating supplier", errorStatus(err), err.Error())
+		return
+	}
+
+	supplier, err := h.storage.Supplier().Get(c.Request.Context(), models.PrimaryKey{
+		ID: updateSupplier.ID,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get supplier by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, supplier)
+}
+
+// DeleteSupplier godoc
+// @Router       /supplier/{id} [DELETE]
+// @Summary      Delete Supplier
+// @Description  Delete Supplier
+// @Tags         supplier
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "supplier id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeleteSupplier(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = h.storage.Supplier().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting supplier by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
+}
+
+// CreateSupplierPayment godoc
+// @Router       /supplier/{id}/payment [POST]
+// @Summary      Pay a supplier
+// @Description  Record a payment to the supplier against what they are owed, admins only as payments are not kept by branch
+// @Tags         supplier
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "supplier id"
+// @Param        payment body models.CreateSupplierPayment true "payment"
+// @Success      201  {object}  models.SupplierPayment
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateSupplierPayment(c *gin.Context) {
+	createPayment := models.CreateSupplierPayment{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = c.ShouldBindJSON(&createPayment); err != nil {
+		handleResponse(c, h.log, "error while reading body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if createPayment.Amount <= 0 {
+		handleResponse(c, h.log, "amount must be positive", http.StatusBadRequest, "amount must be positive")
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	createPayment.SupplierID = id.String()
+	createPayment.StaffID = authInfo.StaffID
+
+	payment, err := h.storage.Supplier().CreatePayment(c.Request.Context(), createPayment)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating supplier payment", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, payment)
+}
+
+// GetSupplierPayable godoc
+// @Router       /supplier/{id}/payable [GET]
+// @Summary      Get supplier payable
+// @Description  Get the value received from the supplier at every branch, the amount paid and the balance still owed, admins only
+// @Tags         supplier
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "supplier id"
+// @Success      200  {object}  models.SupplierPayable
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetSupplierPayable(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payable, err := h.storage.Supplier().Payable(c.Request.Context(), id.String())
+	if err != nil {
+		handleResponse(c, h.log, "error while getting supplier payable", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, payable)
+}
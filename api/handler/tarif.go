@@ -2,7 +2,6 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -16,6 +15,7 @@ import (
 // @Summary      Create a new tarif
 // @Description  Create a new tarif
 // @Tags         tarif
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        tarif  body  models.CreateTarif  true  "tarif data"
@@ -30,17 +30,17 @@ func (h Handler) CreateTarif(c *gin.Context) {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
 	}
 
-	id, err := h.storage.Tarif().Create(context.Background(), createTarif)
+	id, err := h.storage.Tarif().Create(c.Request.Context(), createTarif)
 	if err != nil {
-		handleResponse(c, h.log, "error while creating tarif", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while creating tarif", errorStatus(err), err)
 		return
 	}
 
-	tarif, err := h.storage.Tarif().Get(context.Background(), models.PrimaryKey{
+	tarif, err := h.storage.Tarif().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get tarif", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get tarif", errorStatus(err), err)
 		return
 	}
 
@@ -53,6 +53,7 @@ func (h Handler) CreateTarif(c *gin.Context) {
 // @Summary      Get tarif by id
 // @Description  Get tarif by id
 // @Tags         tarif
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "tarif"
@@ -71,11 +72,11 @@ func (h Handler) GetTarifByID(c *gin.Context) {
 		return
 	}
 
-	tarif, err := h.storage.Tarif().Get(context.Background(), models.PrimaryKey{
+	tarif, err := h.storage.Tarif().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get tarif by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get tarif by id", errorStatus(err), err)
 		return
 	}
 
@@ -88,6 +89,7 @@ func (h Handler) GetTarifByID(c *gin.Context) {
 // @Summary      Get tarifs list
 // @Description  Get tarifs list
 // @Tags         tarif
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -121,14 +123,14 @@ func (h Handler) GetTarifList(c *gin.Context) {
 
 	search = c.Query("search")
 
-	response, err := h.storage.Tarif().GetList(context.Background(), models.GetListRequest{
+	response, err := h.storage.Tarif().GetList(c.Request.Context(), models.GetListRequest{
 		Page:   page,
 		Limit:  limit,
 		Search: search,
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while getting tarif", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while getting tarif", errorStatus(err), err)
 		return
 	}
 
@@ -141,6 +143,7 @@ func (h Handler) GetTarifList(c *gin.Context) {
 // @Summary      Update tarif by id
 // @Description  Update tarif by id
 // @Tags         tarif
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "tarif id"
@@ -165,17 +168,17 @@ func (h Handler) UpdateTarif(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.Tarif().Update(context.Background(), updateTarif)
+	id, err := h.storage.Tarif().Update(c.Request.Context(), updateTarif)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating tarif", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error while updating tarif", errorStatus(err), err.Error())
 		return
 	}
 
-	tarif, err := h.storage.Tarif().Get(context.Background(), models.PrimaryKey{
+	tarif, err := h.storage.Tarif().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get tarif by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get tarif by id", errorStatus(err), err)
 		return
 	}
 
@@ -188,6 +191,7 @@ func (h Handler) UpdateTarif(c *gin.Context) {
 // @Summary      Delete Tarif
 // @Description  Delete Tarif
 // @Tags         tarif
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "tarif id"
@@ -204,8 +208,8 @@ func (h Handler) DeleteTarif(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Tarif().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting tarif by id", http.StatusInternalServerError, err.Error())
+	if err := h.storage.Tarif().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting tarif by id", errorStatus(err), err.Error())
 		return
 	}
 
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"bazaar/api/models"
-	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -18,6 +17,7 @@ import (
 // @Summary      Create a new transaction
 // @Description  Create a new transaction
 // @Tags         transaction
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        transaction  body  models.CreateTransactions  true  "transaction data"
@@ -30,19 +30,20 @@ func (h Handler) CreateTransaction(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&createTransaction); err != nil {
 		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err)
+		return
 	}
 
-	id, err := h.storage.Transaction().Create(context.Background(), createTransaction)
+	id, err := h.storage.Transaction().Create(c.Request.Context(), createTransaction)
 	if err != nil {
-		handleResponse(c, h.log, "error while create transaction", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while create transaction", errorStatus(err), err.Error())
 		return
 	}
 
-	transaction, err := h.storage.Transaction().Get(context.Background(), models.PrimaryKey{
+	transaction, err := h.storage.Transaction().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get transaction", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get transaction", errorStatus(err), err)
 		return
 	}
 
@@ -55,6 +56,7 @@ func (h Handler) CreateTransaction(c *gin.Context) {
 // @Summary      Get transaction by id
 // @Description  Get transaction by id
 // @Tags         transaction
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "transaction"
@@ -73,11 +75,11 @@ func (h Handler) GetTransactionByID(c *gin.Context) {
 		return
 	}
 
-	transaction, err := h.storage.Transaction().Get(context.Background(), models.PrimaryKey{
+	transaction, err := h.storage.Transaction().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while get transaction by id", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get transaction by id", errorStatus(err), err)
 		return
 	}
 
@@ -90,6 +92,7 @@ func (h Handler) GetTransactionByID(c *gin.Context) {
 // @Summary      Get transactions list
 // @Description  Get transactions list
 // @Tags         transaction
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
@@ -137,7 +140,7 @@ func (h Handler) GetTransactionList(c *gin.Context) {
 		return
 	}
 
-	response, err := h.storage.Transaction().GetList(context.Background(), models.GetListTransactionsRequest{
+	response, err := h.storage.Transaction().GetList(c.Request.Context(), models.GetListTransactionsRequest{
 		Page:       page,
 		Limit:      limit,
 		FromAmount: fromAmount,
@@ -145,7 +148,7 @@ func (h Handler) GetTransactionList(c *gin.Context) {
 	})
 
 	if err != nil {
-		handleResponse(c, h.log, "error while get transaction", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while get transaction", errorStatus(err), err)
 		return
 	}
 
@@ -158,6 +161,7 @@ func (h Handler) GetTransactionList(c *gin.Context) {
 // @Summary      Update transaction by id
 // @Description  Update transaction by id
 // @Tags         transaction
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "transaction id"
@@ -182,17 +186,17 @@ func (h Handler) UpdateTransaction(c *gin.Context) {
 		return
 	}
 
-	id, err := h.storage.Transaction().Update(context.Background(), updateTransaction)
+	id, err := h.storage.Transaction().Update(c.Request.Context(), updateTransaction)
 	if err != nil {
-		handleResponse(c, h.log, "error while updating transaction", http.StatusInternalServerError, err.Error())
+		handleResponse(c, h.log, "error while updating transaction", errorStatus(err), err.Error())
 		return
 	}
 
-	transaction, err := h.storage.Transaction().Get(context.Background(), models.PrimaryKey{
+	transaction, err := h.storage.Transaction().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		handleResponse(c, h.log, "error while updating transaction", http.StatusInternalServerError, err)
+		handleResponse(c, h.log, "error while updating transaction", errorStatus(err), err)
 		return
 	}
 
@@ -205,6 +209,7 @@ func (h Handler) UpdateTransaction(c *gin.Context) {
 // @Summary      Delete Transaction
 // @Description  Delete Transaction
 // @Tags         transaction
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "transaction id"
@@ -221,8 +226,8 @@ func (h Handler) DeleteTransaction(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Transaction().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, h.log, "error while deleting by id", http.StatusInternalServerError, err.Error())
+	if err := h.storage.Transaction().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting by id", errorStatus(err), err.Error())
 		return
 	}
 
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"bazaar/api/models"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateTransfer godoc
+// @Router       /transfer [POST]
+// @Summary      Create a new transfer
+// @Description  Draft a transfer of products from one branch to another
+// @Tags         transfer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        transfer  body  models.CreateTransfer  true  "transfer data"
+// @Success      201  {object}  models.Transfer
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateTransfer(c *gin.Context) {
+	createTransfer := models.CreateTransfer{}
+
+	if err := c.ShouldBindJSON(&createTransfer); err != nil {
+		handleResponse(c, h.log, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.storage.Transfer().Create(c.Request.Context(), createTransfer)
+	if err != nil {
+		handleResponse(c, h.log, "error while creating transfer", errorStatus(err), err.Error())
+		return
+	}
+
+	transfer, err := h.storage.Transfer().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id,
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get transfer", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusCreated, transfer)
+}
+
+// GetTransferByID godoc
+// @Router       /transfer/{id} [GET]
+// @Summary      Get transfer by id
+// @Description  Get transfer by id
+// @Tags         transfer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "transfer"
+// @Success      200  {object}  models.Transfer
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetTransferByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transfer, err := h.storage.Transfer().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get transfer by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, transfer)
+}
+
+// GetTransferList godoc
+// @Router       /transfer [GET]
+// @Summary      Get transfers list
+// @Description  Get transfers list, branch_id matches both the source and the destination
+// @Tags         transfer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit"
+// @Param        status query string false "draft, in_transit or received"
+// @Param        branch_id query string false "branch_id"
+// @Success      200  {object}  models.TransfersResponse
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetTransferList(c *gin.Context) {
+
+	var (
+		page, limit int
+		err         error
+	)
+
+	pageStr := c.DefaultQuery("page", "1")
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing page ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil {
+		handleResponse(c, h.log, "error while parsing limit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.storage.Transfer().GetList(c.Request.Context(), models.GetTransfersListRequest{
+		Page:     page,
+		Limit:    limit,
+		Status:   c.Query("status"),
+		BranchID: c.Query("branch_id"),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while getting transfers", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, response)
+}
+
+// DeleteTransfer godoc
+// @Router       /transfer/{id} [DELETE]
+// @Summary      Delete Transfer
+// @Description  Delete a transfer that was not dispatched yet
+// @Tags         transfer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "transfer id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeleteTransfer(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = h.storage.Transfer().Delete(c.Request.Context(), id.String()); err != nil {
+		handleResponse(c, h.log, "error while deleting transfer by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, "data succesfully deleted")
+}
+
+// DispatchTransfer godoc
+// @Router       /transfer/{id}/dispatch [PUT]
+// @Summary      Dispatch transfer
+// @Description  Take the transfer products out of the source branch storage and put the transfer in transit
+// @Tags         transfer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "transfer id"
+// @Success      200  {object}  models.Transfer
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DispatchTransfer(c *gin.Context) {
+	h.moveTransfer(c, "dispatching", h.storage.Transfer().Dispatch)
+}
+
+// ReceiveTransfer godoc
+// @Router       /transfer/{id}/receive [PUT]
+// @Summary      Receive transfer
+// @Description  Put the transfer products on the destination branch storage
+// @Tags         transfer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "transfer id"
+// @Success      200  {object}  models.Transfer
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) ReceiveTransfer(c *gin.Context) {
+	h.moveTransfer(c, "receiving", h.storage.Transfer().Receive)
+}
+
+func (h Handler) moveTransfer(c *gin.Context, action string, move func(context.Context, models.UpdateTransferStatus) error) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, h.log, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authInfo, err := getAuthInfo(c)
+	if err != nil {
+		handleResponse(c, h.log, "auth info is missing", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err = move(c.Request.Context(), models.UpdateTransferStatus{
+		ID:      id.String(),
+		StaffID: authInfo.StaffID,
+	}); err != nil {
+		handleResponse(c, h.log, "error while "+action+" transfer", errorStatus(err), err.Error())
+		return
+	}
+
+	transfer, err := h.storage.Transfer().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleResponse(c, h.log, "error while get transfer by id", errorStatus(err), err.Error())
+		return
+	}
+
+	handleResponse(c, h.log, "", http.StatusOK, transfer)
+}
@@ -0,0 +1,22 @@
+package models
+
+type LoginRequest struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	Staff        Staff  `json:"staff"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type AuthInfo struct {
+	StaffID   string `json:"staff_id"`
+	BranchID  string `json:"branch_id"`
+	TypeStaff string `json:"type_staff"`
+}
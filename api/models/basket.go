@@ -3,14 +3,19 @@ package models
 import "time"
 
 type Basket struct {
-	ID        string    `json:"id"`
-	SaleID    string    `json:"sale_id"`
-	ProductID string    `json:"product_id"`
-	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	DeletedAt time.Time `json:"deleted_at"`
+	ID                    string            `json:"id"`
+	SaleID                string            `json:"sale_id"`
+	ProductID             string            `json:"product_id"`
+	Quantity              int               `json:"quantity"`
+	Price                 float64           `json:"price"`
+	BasePrice             float64           `json:"base_price"`
+	Discount              float64           `json:"discount"`
+	ManualDiscountPercent float64           `json:"manual_discount_percent"`
+	Cost                  float64           `json:"cost"`
+	Promotions            []BasketPromotion `json:"promotions,omitempty"`
+	CreatedAt             time.Time         `json:"created_at"`
+	UpdatedAt             time.Time         `json:"updated_at"`
+	DeletedAt             time.Time         `json:"deleted_at"`
 }
 
 type CreateBasket struct {
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+type Customer struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone"`
+	BranchID  string    `json:"branch_id"`
+	Points    float64   `json:"points"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type CreateCustomer struct {
+	Name     string `json:"name"`
+	Phone    string `json:"phone"`
+	BranchID string `json:"branch_id"`
+}
+
+type UpdateCustomer struct {
+	ID    string `json:"-"`
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+}
+
+type CustomersResponse struct {
+	Customers []Customer `json:"customers"`
+	Count     int        `json:"count"`
+}
+
+type LoyaltyTransaction struct {
+	ID              string    `json:"id"`
+	CustomerID      string    `json:"customer_id"`
+	SaleID          string    `json:"sale_id"`
+	TransactionType string    `json:"transaction_type"`
+	Points          float64   `json:"points"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type CustomerHistory struct {
+	Customer Customer             `json:"customer"`
+	Sales    []Sale               `json:"sales"`
+	Loyalty  []LoyaltyTransaction `json:"loyalty"`
+}
+
+type GetCustomerHistoryRequest struct {
+	CustomerID string `json:"customer_id"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+}
@@ -8,24 +8,34 @@ type IncomeProduct struct {
 	ProductID string    `json:"product_id"`
 	Price     float64   `json:"price"`
 	Count     int       `json:"count"`
+	ExpiresAt *time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at"`
 }
 
 type CreateIncomeProduct struct {
+	StaffID   string  `json:"-"`
 	IncomeID  string  `json:"income_id"`
 	ProductID string  `json:"product_id"`
 	Price     float64 `json:"price"`
 	Count     int       `json:"count"`
+	ExpiresAt *time.Time `json:"expires_at"`
 }
 
 type UpdateIncomeProduct struct {
-	ID        string  `json:"-"`
-	IncomeID  string  `json:"income_id"`
-	ProductID string  `json:"product_id"`
-	Price     float64 `json:"price"`
-	Count     int       `json:"count"`
+	ID        string     `json:"-"`
+	StaffID   string     `json:"-"`
+	IncomeID  string     `json:"income_id"`
+	ProductID string     `json:"product_id"`
+	Price     float64    `json:"price"`
+	Count     int        `json:"count"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type DeleteIncomeProduct struct {
+	ID      string `json:"-"`
+	StaffID string `json:"-"`
 }
 
 type IncomeProductsResponse struct {
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+type InventoryCount struct {
+	ID         string                  `json:"id"`
+	BranchID   string                  `json:"branch_id"`
+	CategoryID string                  `json:"category_id"`
+	Status     string                  `json:"status"`
+	Reason     string                  `json:"reason"`
+	OpenedBy   string                  `json:"opened_by"`
+	PostedBy   string                  `json:"posted_by"`
+	PostedAt   *time.Time              `json:"posted_at"`
+	Products   []InventoryCountProduct `json:"products"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
+
+// InventoryCountProduct compares the storage count with what was found on
+// the shelf. ExpectedCount is taken when the count is opened and again when
+// the product is counted. CountedCount is nil until the product is counted.
+type InventoryCountProduct struct {
+	ID               string `json:"id"`
+	InventoryCountID string `json:"inventory_count_id"`
+	ProductID        string `json:"product_id"`
+	ExpectedCount    int    `json:"expected_count"`
+	CountedCount     *int   `json:"counted_count"`
+	Variance         int    `json:"variance"`
+}
+
+type CreateInventoryCount struct {
+	BranchID   string `json:"branch_id"`
+	CategoryID string `json:"category_id"`
+	StaffID    string `json:"-"`
+}
+
+type SubmitInventoryCount struct {
+	ID       string                    `json:"-"`
+	Products []CountedInventoryProduct `json:"products"`
+}
+
+// CountedInventoryProduct names the product by id or by barcode.
+type CountedInventoryProduct struct {
+	ProductID    string `json:"product_id"`
+	Barcode      string `json:"barcode"`
+	CountedCount int    `json:"counted_count"`
+}
+
+type PostInventoryCount struct {
+	ID      string `json:"-"`
+	StaffID string `json:"-"`
+	Reason  string `json:"reason"`
+}
+
+type GetInventoryCountsListRequest struct {
+	Page   int    `json:"page"`
+	Limit  int    `json:"limit"`
+	Status string `json:"status"`
+}
+
+type InventoryCountsResponse struct {
+	InventoryCounts []InventoryCount `json:"inventory_counts"`
+	Count           int              `json:"count"`
+}
+
+// InventoryVariance values the difference between counted and expected
+// quantities at the last income price of each product.
+type InventoryVariance struct {
+	InventoryCountID string                  `json:"inventory_count_id"`
+	Products         []InventoryVarianceLine `json:"products"`
+	Uncounted        int                     `json:"uncounted"`
+	ShrinkageValue   float64                 `json:"shrinkage_value"`
+	SurplusValue     float64                 `json:"surplus_value"`
+	NetValue         float64                 `json:"net_value"`
+}
+
+type InventoryVarianceLine struct {
+	ProductID       string  `json:"product_id"`
+	ProductName     string  `json:"product_name"`
+	ExpectedCount   int     `json:"expected_count"`
+	CountedCount    int     `json:"counted_count"`
+	Variance        int     `json:"variance"`
+	LastIncomePrice float64 `json:"last_income_price"`
+	VarianceValue   float64 `json:"variance_value"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// PriceChange is a document that changes the prices of a list of products or
+// of every product in a category subtree at its effective time. Without a
+// branch it changes the product prices and the branch prices in effect, with
+// one the prices of that branch. Failure is why the scheduler couldn't apply
+// a failed document.
+type PriceChange struct {
+	ID          string               `json:"id"`
+	BranchID    string               `json:"branch_id"`
+	CategoryID  string               `json:"category_id"`
+	ChangeType  string               `json:"change_type"`
+	Value       float64              `json:"value"`
+	RoundTo     float64              `json:"round_to"`
+	Rounding    string               `json:"rounding"`
+	EffectiveAt time.Time            `json:"effective_at"`
+	Status      string               `json:"status"`
+	CreatedBy   string               `json:"created_by"`
+	ApprovedBy  string               `json:"approved_by"`
+	ApprovedAt  *time.Time           `json:"approved_at"`
+	AppliedAt   *time.Time           `json:"applied_at"`
+	Failure     string               `json:"failure"`
+	Products    []PriceChangeProduct `json:"products"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// PriceChangeProduct is a product the document reprices, with the prices
+// before and after once it is applied.
+type PriceChangeProduct struct {
+	ID            string   `json:"id"`
+	PriceChangeID string   `json:"price_change_id"`
+	ProductID     string   `json:"product_id"`
+	OldPrice      *float64 `json:"old_price"`
+	NewPrice      *float64 `json:"new_price"`
+}
+
+// CreatePriceChange targets either ProductIDs or CategoryID. A percent value
+// of 10 raises prices by a tenth, an absolute one adds the value, negative
+// values lower them. The new prices are rounded nearest, up or down to a
+// multiple of RoundTo, and the change is due at once without EffectiveAt.
+type CreatePriceChange struct {
+	StaffID     string     `json:"-"`
+	BranchID    string     `json:"branch_id"`
+	CategoryID  string     `json:"category_id"`
+	ProductIDs  []string   `json:"product_ids"`
+	ChangeType  string     `json:"change_type"`
+	Value       float64    `json:"value"`
+	RoundTo     float64    `json:"round_to"`
+	Rounding    string     `json:"rounding"`
+	EffectiveAt *time.Time `json:"effective_at"`
+}
+
+type ApprovePriceChange struct {
+	ID      string `json:"-"`
+	StaffID string `json:"-"`
+}
+
+type GetPriceChangesListRequest struct {
+	Page   int    `json:"page"`
+	Limit  int    `json:"limit"`
+	Status string `json:"status"`
+}
+
+type PriceChangesResponse struct {
+	PriceChanges []PriceChange `json:"price_changes"`
+	Count        int           `json:"count"`
+}
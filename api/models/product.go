@@ -14,6 +14,7 @@ type Product struct {
 }
 
 type CreateProduct struct {
+	StaffID    string `json:"-"`
 	Name       string `json:"name"`
 	Price      string `json:"price"`
 	CategoryID string `json:"category_id"`
@@ -21,6 +22,7 @@ type CreateProduct struct {
 
 type UpdateProduct struct {
 	ID         string `json:"-"`
+	StaffID    string `json:"-"`
 	Name       string `json:"name"`
 	Price      string `json:"price"`
 	CategoryID string `json:"category_id"`
@@ -37,3 +39,43 @@ type ProductGetListRequest struct {
 	Search  string `json:"Search"`
 	Barcode int    `json:"barcode"`
 }
+
+// ProductPrice is an entry of the price history. A price without a branch is
+// the product price of every branch, a branch price overrides it at that
+// branch from its effective time on.
+type ProductPrice struct {
+	ID            string    `json:"id"`
+	ProductID     string    `json:"product_id"`
+	BranchID      string    `json:"branch_id"`
+	Price         float64   `json:"price"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	StaffID       string    `json:"staff_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CreateProductPrice sets a branch price, taking effect at once when no
+// effective time is given.
+type CreateProductPrice struct {
+	ProductID     string     `json:"-"`
+	StaffID       string     `json:"-"`
+	BranchID      string     `json:"branch_id"`
+	Price         float64    `json:"price"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+}
+
+// BranchPrice is the price a branch sells the product at now, with the time
+// the override it comes from took effect.
+type BranchPrice struct {
+	BranchID      string     `json:"branch_id"`
+	BranchName    string     `json:"branch_name"`
+	Price         float64    `json:"price"`
+	Override      bool       `json:"override"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+}
+
+type ProductPrices struct {
+	ProductID string         `json:"product_id"`
+	Price     float64        `json:"price"`
+	Branches  []BranchPrice  `json:"branches"`
+	History   []ProductPrice `json:"history"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+type Promotion struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	PromotionType string     `json:"promotion_type"`
+	Value         float64    `json:"value"`
+	BuyQuantity   int        `json:"buy_quantity"`
+	GetQuantity   int        `json:"get_quantity"`
+	ProductID     string     `json:"product_id"`
+	CategoryID    string     `json:"category_id"`
+	BranchID      string     `json:"branch_id"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     time.Time  `json:"deleted_at"`
+}
+
+type CreatePromotion struct {
+	Name          string     `json:"name"`
+	PromotionType string     `json:"promotion_type"`
+	Value         float64    `json:"value"`
+	BuyQuantity   int        `json:"buy_quantity"`
+	GetQuantity   int        `json:"get_quantity"`
+	ProductID     string     `json:"product_id"`
+	CategoryID    string     `json:"category_id"`
+	BranchID      string     `json:"branch_id"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+}
+
+type UpdatePromotion struct {
+	ID            string     `json:"-"`
+	Name          string     `json:"name"`
+	PromotionType string     `json:"promotion_type"`
+	Value         float64    `json:"value"`
+	BuyQuantity   int        `json:"buy_quantity"`
+	GetQuantity   int        `json:"get_quantity"`
+	ProductID     string     `json:"product_id"`
+	CategoryID    string     `json:"category_id"`
+	BranchID      string     `json:"branch_id"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+}
+
+type PromotionsResponse struct {
+	Promotions []Promotion `json:"promotions"`
+	Count      int         `json:"count"`
+}
+
+type BasketPromotion struct {
+	ID          string    `json:"id"`
+	BasketID    string    `json:"basket_id"`
+	PromotionID string    `json:"promotion_id"`
+	StaffID     string    `json:"staff_id"`
+	Amount      float64   `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type BasketDiscount struct {
+	ID      string  `json:"-"`
+	StaffID string  `json:"-"`
+	Percent float64 `json:"percent"`
+}
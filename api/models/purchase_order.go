@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+type PurchaseOrder struct {
+	ID         string                 `json:"id"`
+	SupplierID string                 `json:"supplier_id"`
+	BranchID   string                 `json:"branch_id"`
+	Status     string                 `json:"status"`
+	Price      float64                `json:"price"`
+	IncomeID   string                 `json:"income_id"`
+	Products   []PurchaseOrderProduct `json:"products"`
+	ReceivedAt *time.Time             `json:"received_at"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+type PurchaseOrderProduct struct {
+	ID              string  `json:"id"`
+	PurchaseOrderID string  `json:"purchase_order_id"`
+	ProductID       string  `json:"product_id"`
+	OrderedCount    int     `json:"ordered_count"`
+	ReceivedCount   int     `json:"received_count"`
+	Discrepancy     int     `json:"discrepancy"`
+	Price           float64 `json:"price"`
+}
+
+type CreatePurchaseOrder struct {
+	SupplierID string                       `json:"supplier_id"`
+	BranchID   string                       `json:"branch_id"`
+	Products   []CreatePurchaseOrderProduct `json:"products"`
+}
+
+type CreatePurchaseOrderProduct struct {
+	ProductID    string  `json:"product_id"`
+	OrderedCount int     `json:"ordered_count"`
+	Price        float64 `json:"price"`
+}
+
+type ReceivePurchaseOrder struct {
+	ID       string                        `json:"-"`
+	StaffID  string                        `json:"-"`
+	Products []ReceivePurchaseOrderProduct `json:"products"`
+}
+
+// ReceivePurchaseOrderProduct is the delivered count of an ordered line. A
+// price overrides the ordered one when the supplier invoiced differently.
+type ReceivePurchaseOrderProduct struct {
+	ProductID     string     `json:"product_id"`
+	ReceivedCount int        `json:"received_count"`
+	Price         float64    `json:"price"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+type GetPurchaseOrdersListRequest struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	SupplierID string `json:"supplier_id"`
+	Status     string `json:"status"`
+}
+
+type PurchaseOrdersResponse struct {
+	PurchaseOrders []PurchaseOrder `json:"purchase_orders"`
+	Count          int             `json:"count"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type Receipt struct {
+	ReceiptNumber int           `json:"receipt_number"`
+	SaleID        string        `json:"sale_id"`
+	Status        string        `json:"status"`
+	BranchName    string        `json:"branch_name"`
+	BranchAddress string        `json:"branch_address"`
+	CashierName   string        `json:"cashier_name"`
+	ClientName    string        `json:"client_name"`
+	Lines         []ReceiptLine `json:"lines"`
+	Payments      []SalePayment `json:"payments"`
+	TotalPrice    float64       `json:"total_price"`
+	Change        float64       `json:"change"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+type ReceiptLine struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Barcode     string  `json:"barcode"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Price       float64 `json:"price"`
+}
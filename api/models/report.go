@@ -0,0 +1,166 @@
+package models
+
+import "time"
+
+type StockMovementRequest struct {
+	BranchID  string    `json:"branch_id"`
+	ProductID string    `json:"product_id"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+}
+
+// StockMovementReport walks the storage transaction ledger of one product at
+// one branch from the opening to the closing balance.
+type StockMovementReport struct {
+	BranchID       string          `json:"branch_id"`
+	ProductID      string          `json:"product_id"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	OpeningBalance int             `json:"opening_balance"`
+	Movements      []StockMovement `json:"movements"`
+	TotalIn        int             `json:"total_in"`
+	TotalOut       int             `json:"total_out"`
+	ClosingBalance int             `json:"closing_balance"`
+}
+
+type StockMovement struct {
+	StorageTransactionID string    `json:"storage_transaction_id"`
+	CreatedAt            time.Time `json:"created_at"`
+	SourceType           string    `json:"source_type"`
+	SourceID             string    `json:"source_id"`
+	StaffID              string    `json:"staff_id"`
+	Reason               string    `json:"reason"`
+	In                   int       `json:"in"`
+	Out                  int       `json:"out"`
+	Price                float64   `json:"price"`
+	Balance              int       `json:"balance"`
+}
+
+type StockOnDateRequest struct {
+	At        time.Time `json:"at"`
+	BranchID  string    `json:"branch_id"`
+	ProductID string    `json:"product_id"`
+}
+
+type StockOnDate struct {
+	At       time.Time         `json:"at"`
+	Products []StockOnDateLine `json:"products"`
+}
+
+type StockOnDateLine struct {
+	BranchID    string `json:"branch_id"`
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Count       int    `json:"count"`
+}
+
+type LowStockRequest struct {
+	BranchID string `json:"branch_id"`
+	Days     int    `json:"days"`
+}
+
+// LowStockItem is a product below its minimum with the quantity to order to
+// get back to the minimum and cover as many days of sales as were looked at.
+type LowStockItem struct {
+	StorageID      string  `json:"storage_id"`
+	BranchID       string  `json:"branch_id"`
+	ProductID      string  `json:"product_id"`
+	ProductName    string  `json:"product_name"`
+	Count          int     `json:"count"`
+	MinCount       int     `json:"min_count"`
+	ReorderCount   int     `json:"reorder_count"`
+	SoldCount      int     `json:"sold_count"`
+	DailyVelocity  float64 `json:"daily_velocity"`
+	SuggestedCount int     `json:"suggested_count"`
+}
+
+type LowStockReport struct {
+	Days  int            `json:"days"`
+	Items []LowStockItem `json:"items"`
+}
+
+type ExpiringBatchesRequest struct {
+	BranchID string `json:"branch_id"`
+	Days     int    `json:"days"`
+}
+
+// ExpiringBatch is a batch still in stock that expires within the days looked
+// at or has already expired, valued at its cost.
+type ExpiringBatch struct {
+	BatchID     string    `json:"batch_id"`
+	BranchID    string    `json:"branch_id"`
+	ProductID   string    `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	DaysLeft    int       `json:"days_left"`
+	Count       int       `json:"count"`
+	Cost        float64   `json:"cost"`
+	Value       float64   `json:"value"`
+}
+
+type ExpiringBatchesReport struct {
+	Days    int             `json:"days"`
+	Batches []ExpiringBatch `json:"batches"`
+}
+
+type MarginRequest struct {
+	GroupBy  string    `json:"group_by"`
+	BranchID string    `json:"branch_id"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+}
+
+// MarginLine is the revenue of what was sold and kept, net of returns, with
+// its cost of goods sold as snapshotted when the sales completed.
+type MarginLine struct {
+	Key           string  `json:"key"`
+	Name          string  `json:"name"`
+	Quantity      int     `json:"quantity"`
+	Revenue       float64 `json:"revenue"`
+	COGS          float64 `json:"cogs"`
+	GrossMargin   float64 `json:"gross_margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+type MarginReport struct {
+	GroupBy string       `json:"group_by"`
+	From    time.Time    `json:"from"`
+	To      time.Time    `json:"to"`
+	Lines   []MarginLine `json:"lines"`
+	Total   MarginLine   `json:"total"`
+}
+
+type SalesReportRequest struct {
+	GroupBy  string    `json:"group_by"`
+	BranchID string    `json:"branch_id"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Top      int       `json:"top"`
+}
+
+// SalesReportLine sums the completed sales of a group. A sale split across
+// groups, like a basket of several categories, counts once in each of them.
+type SalesReportLine struct {
+	Key           string  `json:"key"`
+	Name          string  `json:"name"`
+	SalesCount    int     `json:"sales_count"`
+	Quantity      int     `json:"quantity"`
+	Revenue       float64 `json:"revenue"`
+	AverageTicket float64 `json:"average_ticket"`
+}
+
+type TopProduct struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	Revenue     float64 `json:"revenue"`
+}
+
+type SalesReport struct {
+	GroupBy     string            `json:"group_by"`
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	Total       SalesReportLine   `json:"total"`
+	Lines       []SalesReportLine `json:"lines"`
+	TopProducts []TopProduct      `json:"top_products"`
+}
@@ -11,6 +11,8 @@ type Sale struct {
 	Price           string    `json:"price"`
 	Status          string    `json:"status"`
 	ClientName      string    `json:"client_name"`
+	CustomerID      string    `json:"customer_id"`
+	ReceiptNumber   int       `json:"receipt_number"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 	DeletedAt       time.Time `json:"deleted_at"`
@@ -22,8 +24,8 @@ type CreateSale struct {
 	CashierID       string `json:"cashier_id"`
 	PaymentType     string `json:"payment_type"`
 	Price           string `json:"price"`
-	Status          string `json:"status"`
 	ClientName      string `json:"client_name"`
+	CustomerID      string `json:"customer_id"`
 }
 
 type UpdateSale struct {
@@ -33,8 +35,8 @@ type UpdateSale struct {
 	CashierID       string `json:"cashier_id"`
 	PaymentType     string `json:"payment_type"`
 	Price           string `json:"price"`
-	Status          string `json:"status"`
 	ClientName      string `json:"client_name"`
+	CustomerID      string `json:"customer_id"`
 }
 
 type SalesResponse struct {
@@ -43,7 +45,28 @@ type SalesResponse struct {
 }
 
 type SaleRequest struct {
-	ID         string `json:"id"`
-	TotalPrice int    `json:"-"`
-	Status     string `json:"status"`
+	ID       string              `json:"id"`
+	Status   string              `json:"status"`
+	Payments []CreateSalePayment `json:"payments"`
+
+	LoyaltyEarnPercent float64 `json:"-"`
+	CostMethod         string  `json:"-"`
+}
+
+type CancelSale struct {
+	ID      string `json:"id"`
+	StaffID string `json:"staff_id"`
+}
+
+type UpdateSaleStatus struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type SaleReceipt struct {
+	Sale       Sale          `json:"sale"`
+	Baskets    []Basket      `json:"baskets"`
+	Payments   []SalePayment `json:"payments"`
+	TotalPrice float64       `json:"total_price"`
+	Change     float64       `json:"change"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type SalePayment struct {
+	ID          string    `json:"id"`
+	SaleID      string    `json:"sale_id"`
+	PaymentType string    `json:"payment_type"`
+	Amount      float64   `json:"amount"`
+	Tendered    float64   `json:"tendered"`
+	Change      float64   `json:"change"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CreateSalePayment struct {
+	PaymentType string  `json:"payment_type"`
+	Amount      float64 `json:"amount"`
+	Tendered    float64 `json:"tendered"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// SaleReturn refunds Amount, the value of the returned lines. Points of it go
+// back to the customer as loyalty points, the share of the points tender in
+// the sale, and the rest is paid out in PaymentType.
+type SaleReturn struct {
+	ID          string              `json:"id"`
+	SaleID      string              `json:"sale_id"`
+	StaffID     string              `json:"staff_id"`
+	PaymentType string              `json:"payment_type"`
+	Amount      float64             `json:"amount"`
+	Points      float64             `json:"points"`
+	Reason      string              `json:"reason"`
+	Products    []SaleReturnProduct `json:"products"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	DeletedAt   time.Time           `json:"deleted_at"`
+}
+
+type SaleReturnProduct struct {
+	ID           string  `json:"id"`
+	SaleReturnID string  `json:"sale_return_id"`
+	BasketID     string  `json:"basket_id"`
+	ProductID    string  `json:"product_id"`
+	Quantity     int     `json:"quantity"`
+	Price        float64 `json:"price"`
+}
+
+type CreateSaleReturn struct {
+	SaleID      string                    `json:"-"`
+	StaffID     string                    `json:"-"`
+	PaymentType string                    `json:"payment_type"`
+	Reason      string                    `json:"reason"`
+	Products    []CreateSaleReturnProduct `json:"products"`
+}
+
+type CreateSaleReturnProduct struct {
+	BasketID string `json:"basket_id"`
+	Quantity int    `json:"quantity"`
+}
+
+type GetSaleReturnsListRequest struct {
+	Page   int    `json:"page"`
+	Limit  int    `json:"limit"`
+	SaleID string `json:"sale_id"`
+}
+
+type SaleReturnsResponse struct {
+	SaleReturns []SaleReturn `json:"sale_returns"`
+	Count       int          `json:"count"`
+}
@@ -13,7 +13,7 @@ type Staff struct {
 	Age       int       `json:"age"`
 	Gender    string    `json:"gender"`
 	Login     string    `json:"login"`
-	Password  string    `json:"password"`
+	Password  string    `json:"-"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at"`
@@ -41,7 +41,6 @@ type UpdateStaff struct {
 	BirthDate string  `json:"birth_date"`
 	Gender    string  `json:"gender"`
 	Login     string  `json:"login"`
-	Password  string  `json:"password"`
 }
 
 type StaffsResponse struct {
@@ -53,3 +52,9 @@ type UpdateStaffBalance struct {
 	ID      string  `json:"id"`
 	Balance float64 `json:"balance"`
 }
+
+type UpdateStaffPassword struct {
+	ID          string `json:"-"`
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
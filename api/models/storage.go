@@ -2,27 +2,47 @@ package models
 
 import "time"
 
+// Storage is the stock of a product at a branch. Below MinCount the product
+// is low on stock and ReorderCount is the least quantity worth ordering.
+// AverageCost is the weighted average purchase cost of a unit on hand.
 type Storage struct {
-	ID        string    `json:"id"`
-	ProductID string    `json:"product_id"`
-	BranchID  string    `json:"branch_id"`
-	Count     int       `json:"count"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	DeletedAt time.Time `json:"deleted_at"`
+	ID           string    `json:"id"`
+	ProductID    string    `json:"product_id"`
+	BranchID     string    `json:"branch_id"`
+	Count        int       `json:"count"`
+	MinCount     int       `json:"min_count"`
+	ReorderCount int       `json:"reorder_count"`
+	AverageCost  float64   `json:"average_cost"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	DeletedAt    time.Time `json:"deleted_at"`
 }
 
+// CreateStorage opens the storage of a product at a branch. An opening count
+// is posted to the ledger as an adjustment, at Cost a unit or the branch
+// average cost without one.
 type CreateStorage struct {
-	ProductID string `json:"product_id"`
-	BranchID  string `json:"branch_id"`
-	Count     int    `json:"count"`
+	StaffID      string  `json:"-"`
+	ProductID    string  `json:"product_id"`
+	BranchID     string  `json:"branch_id"`
+	Count        int     `json:"count"`
+	Cost         float64 `json:"cost"`
+	MinCount     int     `json:"min_count"`
+	ReorderCount int     `json:"reorder_count"`
+	Reason       string  `json:"reason"`
 }
 
+// UpdateStorage sets the stock thresholds. A count, when given, is reached
+// with a plus or minus adjustment posted to the ledger, units added costing
+// Cost or the branch average cost without one.
 type UpdateStorage struct {
-	ID        string `json:"-"`
-	ProductID string `json:"product_id"`
-	BranchID  string `json:"branch_id"`
-	Count     int    `json:"count"`
+	ID           string  `json:"-"`
+	StaffID      string  `json:"-"`
+	Count        *int    `json:"count"`
+	Cost         float64 `json:"cost"`
+	MinCount     int     `json:"min_count"`
+	ReorderCount int     `json:"reorder_count"`
+	Reason       string  `json:"reason"`
 }
 
 type StoragesResponse struct {
@@ -30,7 +50,16 @@ type StoragesResponse struct {
 	Count    int       `json:"count"`
 }
 
-type UpdateCount struct {
-	ID    string
-	Count int
+// StockAlert is raised when a completed sale takes the count of a product at
+// a branch below its minimum.
+type StockAlert struct {
+	ID           string    `json:"id"`
+	BranchID     string    `json:"branch_id"`
+	ProductID    string    `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	SaleID       string    `json:"sale_id"`
+	Count        int       `json:"count"`
+	MinCount     int       `json:"min_count"`
+	ReorderCount int       `json:"reorder_count"`
+	At           time.Time `json:"at"`
 }
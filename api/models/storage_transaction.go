@@ -9,26 +9,39 @@ type StorageTransaction struct {
 	StorageTransactionType string    `json:"storage_transaction_type"`
 	Price                  float64   `json:"price"`
 	Quantity               float64   `json:"quantity"`
+	BranchID               string    `json:"branch_id"`
+	SourceType             string    `json:"source_type"`
+	SourceID               string    `json:"source_id"`
+	Reason                 string    `json:"reason"`
+	BalanceAfter           *int      `json:"balance_after"`
 	CreatedAt              time.Time `json:"created_at"`
 	UpdatedAt              time.Time `json:"updated_at"`
 	DeletedAt              time.Time `json:"deleted_at"`
 }
 
+// CreateStorageTransaction is a manual adjustment of the branch storage.
+// Price is the value of a plus adjustment, taken into the average cost; an
+// adjustment without one is valued at the branch average cost.
 type CreateStorageTransaction struct {
 	StaffID                string    `json:"staff_id"`
+	BranchID               string    `json:"branch_id"`
 	ProductID              string    `json:"product_id"`
 	StorageTransactionType string    `json:"storage_transaction_type"`
 	Price                  float64   `json:"price"`
 	Quantity               float64   `json:"quantity"`
+	Reason                 string    `json:"reason"`
 }
 
-type UpdateStorageTransaction struct {
-	ID                     string    `json:"-"`
-	StaffID                string    `json:"staff_id"`
+type GetStorageTransactionsListRequest struct {
+	Page                   int       `json:"page"`
+	Limit                  int       `json:"limit"`
+	BranchID               string    `json:"branch_id"`
 	ProductID              string    `json:"product_id"`
 	StorageTransactionType string    `json:"storage_transaction_type"`
-	Price                  float64   `json:"price"`
-	Quantity               float64   `json:"quantity"`
+	SourceType             string    `json:"source_type"`
+	SourceID               string    `json:"source_id"`
+	FromDate               time.Time `json:"from_date"`
+	ToDate                 time.Time `json:"to_date"`
 }
 
 type StorageTransactionsResponse struct {
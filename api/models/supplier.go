@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+type Supplier struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type CreateSupplier struct {
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+}
+
+type UpdateSupplier struct {
+	ID      string `json:"-"`
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+}
+
+type SuppliersResponse struct {
+	Suppliers []Supplier `json:"suppliers"`
+	Count     int        `json:"count"`
+}
+
+type SupplierPayment struct {
+	ID          string    `json:"id"`
+	SupplierID  string    `json:"supplier_id"`
+	StaffID     string    `json:"staff_id"`
+	Amount      float64   `json:"amount"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CreateSupplierPayment struct {
+	SupplierID  string  `json:"-"`
+	StaffID     string  `json:"-"`
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description"`
+}
+
+// SupplierPayable is what the branches owe the supplier: the value of the
+// goods received from them less what was paid.
+type SupplierPayable struct {
+	SupplierID string  `json:"supplier_id"`
+	Received   float64 `json:"received"`
+	Paid       float64 `json:"paid"`
+	Balance    float64 `json:"balance"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+type Transfer struct {
+	ID           string            `json:"id"`
+	FromBranchID string            `json:"from_branch_id"`
+	ToBranchID   string            `json:"to_branch_id"`
+	Status       string            `json:"status"`
+	DispatchedBy string            `json:"dispatched_by"`
+	DispatchedAt *time.Time        `json:"dispatched_at"`
+	ReceivedBy   string            `json:"received_by"`
+	ReceivedAt   *time.Time        `json:"received_at"`
+	Products     []TransferProduct `json:"products"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+type TransferProduct struct {
+	ID         string `json:"id"`
+	TransferID string `json:"transfer_id"`
+	ProductID  string `json:"product_id"`
+	Count      int    `json:"count"`
+}
+
+type CreateTransfer struct {
+	FromBranchID string                  `json:"from_branch_id"`
+	ToBranchID   string                  `json:"to_branch_id"`
+	Products     []CreateTransferProduct `json:"products"`
+}
+
+type CreateTransferProduct struct {
+	ProductID string `json:"product_id"`
+	Count     int    `json:"count"`
+}
+
+// UpdateTransferStatus moves a transfer on: a draft is dispatched from the
+// source branch and a transfer in transit is received at the destination.
+type UpdateTransferStatus struct {
+	ID      string `json:"-"`
+	StaffID string `json:"-"`
+}
+
+type GetTransfersListRequest struct {
+	Page     int    `json:"page"`
+	Limit    int    `json:"limit"`
+	Status   string `json:"status"`
+	BranchID string `json:"branch_id"`
+}
+
+type TransfersResponse struct {
+	Transfers []Transfer `json:"transfers"`
+	Count     int        `json:"count"`
+}
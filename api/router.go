@@ -3,7 +3,9 @@ package api
 import (
 	_ "bazaar/api/docs"
 	"bazaar/api/handler"
+	"bazaar/config"
 	"bazaar/pkg/logger"
+	"bazaar/pkg/role"
 	"bazaar/storage"
 	"fmt"
 	"log"
@@ -17,135 +19,224 @@ import (
 // @title           BAZAAR
 // @version         1.0
 // @description     An API for a store called BAZAAR
-func New(store storage.IStorage, log logger.ILogger) *gin.Engine {
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+func New(store storage.IStorage, cfg config.Config, log logger.ILogger) *gin.Engine {
 
-	h := handler.New(store, log)
+	h := handler.New(store, cfg, log)
 
 	r := gin.New()
 
 	//r.Use(gin.Logger()) default middleware
 
-	//r.Use(authenticateMiddleware)
-	r.Use(traceRequest)
+	r.Use(gin.Recovery(), traceRequest)
+
+	// AUTH
+
+	r.POST("auth/login", h.Login)
+	r.POST("auth/refresh", h.RefreshToken)
+
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	authorized := r.Group("/", h.AuthenticateMiddleware)
+
+	var (
+		everyone = authorized.Group("/", h.Permit(role.Cashier, role.ShopAssistant, role.BranchManager, role.Admin))
+		cashiers = authorized.Group("/", h.Permit(role.Cashier))
+		tills    = authorized.Group("/", h.Permit(role.Cashier, role.BranchManager, role.Admin))
+		managers = authorized.Group("/", h.Permit(role.BranchManager, role.Admin))
+		admins   = authorized.Group("/", h.Permit(role.Admin))
+	)
 
 	//BARCODE
 
-	r.POST("barcode", h.Barcode)
+	everyone.POST("barcode", h.Barcode)
 
 	// BASKET
 
-	r.POST("basket", h.CreateBasket)
-	r.GET("basket/:id", h.GetBasketByID)
-	r.GET("basket", h.GetBasketList)
-	r.PUT("basket/:id", h.UpdateBasket)
-	r.DELETE("basket/:id", h.DeleteBasket)
+	everyone.POST("basket", h.CreateBasket)
+	everyone.GET("basket/:id", h.GetBasketByID)
+	everyone.GET("basket", h.GetBasketList)
+	everyone.PUT("basket/:id", h.UpdateBasket)
+	everyone.DELETE("basket/:id", h.DeleteBasket)
+	tills.PUT("basket/:id/discount", h.SetBasketDiscount)
 
 	// BRANCH
 
-	r.POST("branch", h.CreateBranch)
-	r.GET("branch/:id", h.GetBranchByID)
-	r.GET("branch", h.GetBranchList)
-	r.PUT("branch/:id", h.UpdateBranch)
-	r.DELETE("branch/:id", h.DeleteBranch)
+	admins.POST("branch", h.CreateBranch)
+	everyone.GET("branch/:id", h.GetBranchByID)
+	everyone.GET("branch", h.GetBranchList)
+	admins.PUT("branch/:id", h.UpdateBranch)
+	admins.DELETE("branch/:id", h.DeleteBranch)
 
 	// CATEGORY
 
-	r.POST("category", h.CreateCategory)
-	r.GET("category/:id", h.GetCategoryByID)
-	r.GET("category", h.GetCategoryList)
-	r.PUT("category/:id", h.UpdateCategory)
-	r.DELETE("category/:id", h.DeleteCategory)
+	managers.POST("category", h.CreateCategory)
+	everyone.GET("category/:id", h.GetCategoryByID)
+	everyone.GET("category", h.GetCategoryList)
+	managers.PUT("category/:id", h.UpdateCategory)
+	managers.DELETE("category/:id", h.DeleteCategory)
+
+	// CUSTOMER
+
+	everyone.POST("customer", h.CreateCustomer)
+	everyone.GET("customer/:id", h.GetCustomerByID)
+	everyone.GET("customer", h.GetCustomerList)
+	everyone.PUT("customer/:id", h.UpdateCustomer)
+	managers.DELETE("customer/:id", h.DeleteCustomer)
+	everyone.GET("customer/:id/history", h.GetCustomerHistory)
+
+	// INVENTORY COUNT
+
+	managers.POST("inventory_count", h.CreateInventoryCount)
+	managers.GET("inventory_count/:id", h.GetInventoryCountByID)
+	managers.GET("inventory_count", h.GetInventoryCountList)
+	managers.DELETE("inventory_count/:id", h.DeleteInventoryCount)
+	tills.PUT("inventory_count/:id/products", h.SubmitInventoryCount)
+	managers.PUT("inventory_count/:id/post", h.PostInventoryCount)
+	managers.GET("inventory_count/:id/variance", h.GetInventoryVariance)
+
+	// PRICE CHANGE
+
+	managers.POST("price_change", h.CreatePriceChange)
+	managers.GET("price_change/:id", h.GetPriceChangeByID)
+	managers.GET("price_change", h.GetPriceChangeList)
+	managers.DELETE("price_change/:id", h.DeletePriceChange)
+	managers.PUT("price_change/:id/approve", h.ApprovePriceChange)
 
 	// PRODUCT
 
-	r.POST("product", h.CreateProduct)
-	r.GET("product/:id", h.GetProductByID)
-	r.GET("product", h.GetProductList)
-	r.PUT("product/:id", h.UpdateProduct)
-	r.DELETE("product/:id", h.DeleteProduct)
+	managers.POST("product", h.CreateProduct)
+	everyone.GET("product/:id", h.GetProductByID)
+	everyone.GET("product", h.GetProductList)
+	admins.PUT("product/:id", h.UpdateProduct)
+	managers.DELETE("product/:id", h.DeleteProduct)
+	managers.POST("product/:id/prices", h.CreateProductPrice)
+	everyone.GET("product/:id/prices", h.GetProductPrices)
+
+	// PROMOTION
+
+	managers.POST("promotion", h.CreatePromotion)
+	everyone.GET("promotion/:id", h.GetPromotionByID)
+	everyone.GET("promotion", h.GetPromotionList)
+	managers.PUT("promotion/:id", h.UpdatePromotion)
+	managers.DELETE("promotion/:id", h.DeletePromotion)
+
+	// PURCHASE ORDER
+
+	managers.POST("purchase_order", h.CreatePurchaseOrder)
+	managers.GET("purchase_order/:id", h.GetPurchaseOrderByID)
+	managers.GET("purchase_order", h.GetPurchaseOrderList)
+	managers.PUT("purchase_order/:id/receive", h.ReceivePurchaseOrder)
+	managers.PUT("purchase_order/:id/cancel", h.CancelPurchaseOrder)
+
+	// REPORT
+
+	managers.GET("reports/stock-movement", h.GetStockMovementReport)
+	managers.GET("reports/stock-on-date", h.GetStockOnDateReport)
+	managers.GET("reports/low-stock", h.GetLowStockReport)
+	managers.GET("reports/expiring-batches", h.GetExpiringBatchesReport)
+	managers.GET("reports/margin", h.GetMarginReport)
+	managers.GET("reports/sales", h.GetSalesReport)
 
 	// SALE
 
-	r.POST("sale", h.CreateSale)
-	r.GET("sale/:id", h.GetSaleByID)
-	r.GET("sale", h.GetSaleList)
-	r.PUT("sale/:id", h.UpdateSale)
-	r.DELETE("sale/:id", h.DeleteSale)
+	everyone.POST("sale", h.CreateSale)
+	everyone.GET("sale/:id", h.GetSaleByID)
+	everyone.GET("sale", h.GetSaleList)
+	everyone.PUT("sale/:id", h.UpdateSale)
+	managers.DELETE("sale/:id", h.DeleteSale)
+	everyone.GET("sale/:id/payments", h.GetSalePayments)
+	everyone.GET("sale/:id/receipt", h.GetSaleReceipt)
+
+	// SALE RETURN
+
+	tills.POST("sale/:id/returns", h.CreateSaleReturn)
+	everyone.GET("sale/:id/returns", h.GetSaleReturnList)
 
 	// STAFF
 
-	r.POST("staff", h.CreateStaff)
-	r.GET("staff/:id", h.GetStaffByID)
-	r.GET("staff", h.GetStaffList)
-	r.PUT("staff/:id", h.UpdateStaff)
-	r.DELETE("staff/:id", h.DeleteStaff)
+	managers.POST("staff", h.CreateStaff)
+	managers.GET("staff/:id", h.GetStaffByID)
+	managers.GET("staff", h.GetStaffList)
+	managers.PUT("staff/:id", h.UpdateStaff)
+	managers.DELETE("staff/:id", h.DeleteStaff)
+	everyone.PUT("staff/:id/password", h.ChangeStaffPassword)
+
+	// SUPPLIER
+
+	managers.POST("supplier", h.CreateSupplier)
+	everyone.GET("supplier/:id", h.GetSupplierByID)
+	everyone.GET("supplier", h.GetSupplierList)
+	managers.PUT("supplier/:id", h.UpdateSupplier)
+	managers.DELETE("supplier/:id", h.DeleteSupplier)
+	admins.POST("supplier/:id/payment", h.CreateSupplierPayment)
+	admins.GET("supplier/:id/payable", h.GetSupplierPayable)
 
 	// STORAGE-TRANSACTION
 
-	r.POST("storage_transaction", h.CreateStorageTransaction)
-	r.GET("storage_transaction/:id", h.GetStorageTransactionByID)
-	r.GET("storage_transaction", h.GetStorageTransactionList)
-	r.PUT("storage_transaction/:id", h.UpdateStorageTransaction)
-	r.DELETE("storage_transaction/:id", h.DeleteStorageTransaction)
+	managers.POST("storage_transaction", h.CreateStorageTransaction)
+	everyone.GET("storage_transaction/:id", h.GetStorageTransactionByID)
+	everyone.GET("storage_transaction", h.GetStorageTransactionList)
 
 	// STORAGE
 
-	r.POST("storage", h.CreateStorage)
-	r.GET("storage/:id", h.GetStorageByID)
-	r.GET("storage", h.GetStorageList)
-	r.PUT("storage/:id", h.UpdateStorage)
-	r.DELETE("storage/:id", h.DeleteStorage)
+	managers.POST("storage", h.CreateStorage)
+	everyone.GET("storage/:id", h.GetStorageByID)
+	everyone.GET("storage", h.GetStorageList)
+	managers.PUT("storage/:id", h.UpdateStorage)
+	managers.DELETE("storage/:id", h.DeleteStorage)
 
 	// TARIF
 
-	r.POST("tarif", h.CreateTarif)
-	r.GET("tarif/:id", h.GetTarifByID)
-	r.GET("tarif", h.GetTarifList)
-	r.PUT("tarif/:id", h.UpdateTarif)
-	r.DELETE("tarif/:id", h.DeleteTarif)
+	managers.POST("tarif", h.CreateTarif)
+	managers.GET("tarif/:id", h.GetTarifByID)
+	managers.GET("tarif", h.GetTarifList)
+	managers.PUT("tarif/:id", h.UpdateTarif)
+	managers.DELETE("tarif/:id", h.DeleteTarif)
 
 	// TRANSACTION
 
-	r.POST("transaction", h.CreateTransaction)
-	r.GET("transaction/:id", h.GetTransactionByID)
-	r.GET("transaction", h.GetTransactionList)
-	r.PUT("transaction/:id", h.UpdateTransaction)
-	r.DELETE("transaction/:id", h.DeleteTransaction)
+	managers.POST("transaction", h.CreateTransaction)
+	managers.GET("transaction/:id", h.GetTransactionByID)
+	managers.GET("transaction", h.GetTransactionList)
+	managers.PUT("transaction/:id", h.UpdateTransaction)
+	managers.DELETE("transaction/:id", h.DeleteTransaction)
+
+	// TRANSFER
+
+	managers.POST("transfer", h.CreateTransfer)
+	managers.GET("transfer/:id", h.GetTransferByID)
+	managers.GET("transfer", h.GetTransferList)
+	managers.DELETE("transfer/:id", h.DeleteTransfer)
+	managers.PUT("transfer/:id/dispatch", h.DispatchTransfer)
+	managers.PUT("transfer/:id/receive", h.ReceiveTransfer)
 
 	// SELL
 
-	r.POST("sell/", h.StartSell)
-	r.PUT("end_sell/:id", h.EndSale)
+	everyone.POST("sell/", h.StartSell)
+	cashiers.PUT("end_sell/:id", h.EndSale)
 
 	// INCOME
 
-	r.POST("income", h.CreateIncome)
-	r.GET("income/:id", h.GetIncomeByID)
-	r.GET("incomes", h.GetIncomesList)
-	r.PUT("income/:id", h.UpdateIncome)
-	r.DELETE("income/:id", h.DeleteIncome)
+	managers.POST("income", h.CreateIncome)
+	managers.GET("income/:id", h.GetIncomeByID)
+	managers.GET("incomes", h.GetIncomesList)
+	managers.PUT("income/:id", h.UpdateIncome)
+	managers.DELETE("income/:id", h.DeleteIncome)
 
 	// INCOME PRODUCTS
 
-	r.POST("income_product", h.CreateIncomeProduct)
-	r.GET("income_product/:id", h.GetIncomeProductByID)
-	r.GET("income_products", h.GetIncomeProductsList)
-	r.PUT("income_product/:id", h.UpdateIncomeProduct)
-	r.DELETE("income_product/:id", h.DeleteIncomeProduct)
+	managers.POST("income_product", h.CreateIncomeProduct)
+	managers.GET("income_product/:id", h.GetIncomeProductByID)
+	managers.GET("income_products", h.GetIncomeProductsList)
+	managers.PUT("income_product/:id", h.UpdateIncomeProduct)
+	managers.DELETE("income_product/:id", h.DeleteIncomeProduct)
 
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	return r
 }
 
-// func authenticateMiddleware(c *gin.Context) {
-// 	auth := c.GetHeader("Authorization")
-// 	if auth == "" {
-// 		c.AbortWithStatus(http.StatusUnauthorized)
-// 	} else {
-// 		c.Next()
-// 	}
-// }
-
 func traceRequest(c *gin.Context) {
 
 	beforeRequest(c)
@@ -4,6 +4,8 @@ import (
 	"bazaar/api"
 	"bazaar/config"
 	"bazaar/pkg/logger"
+	"bazaar/pkg/pricechange"
+	"bazaar/pkg/stockalert"
 	"bazaar/storage/postgres"
 	"context"
 )
@@ -14,6 +16,11 @@ func main() {
 
 	log := logger.New(cfg.ServiceName)
 
+	if cfg.SecretKey == "" {
+		log.Error("SECRET_KEY is not set, can't sign access tokens")
+		return
+	}
+
 	pgStore, err := postgres.New(context.Background(), cfg, log)
 	if err != nil {
 		log.Error("error while connecting to db", logger.Error(err))
@@ -21,7 +28,13 @@ func main() {
 	}
 	defer pgStore.CloseDB()
 
-	server := api.New(pgStore, log)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go stockalert.NewChecker(pgStore, stockalert.LogNotifier{Log: log}, log, cfg.StockAlertInterval).Run(ctx)
+	go pricechange.NewScheduler(pgStore, log, cfg.PriceChangeInterval).Run(ctx)
+
+	server := api.New(pgStore, cfg, log)
 
 	log.Info("Server is running on", logger.Int("port", 8080))
 	if err = server.Run("localhost:8080"); err != nil {
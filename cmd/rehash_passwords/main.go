@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bazaar/config"
+	"bazaar/pkg/logger"
+	"bazaar/storage/postgres"
+	"context"
+)
+
+// rehash_passwords is a one-shot command that replaces plaintext staff
+// passwords left from before hashing with bcrypt hashes.
+func main() {
+
+	cfg := config.Load()
+
+	log := logger.New(cfg.ServiceName)
+
+	pgStore, err := postgres.New(context.Background(), cfg, log)
+	if err != nil {
+		log.Error("error while connecting to db", logger.Error(err))
+		return
+	}
+	defer pgStore.CloseDB()
+
+	rehashed, err := pgStore.Staff().RehashPasswords(context.Background())
+	if err != nil {
+		log.Error("error while rehashing staff passwords", logger.Error(err))
+		return
+	}
+
+	log.Info("staff passwords rehashed", logger.Int("count", rehashed))
+}
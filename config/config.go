@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cast"
@@ -17,6 +18,18 @@ type Config struct {
 
 	ServiceName string
 	LoggerLevel string
+
+	SecretKey       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	LoyaltyEarnPercent float64
+
+	StockAlertInterval time.Duration
+
+	CostMethod string
+
+	PriceChangeInterval time.Duration
 }
 
 func Load() Config {
@@ -36,6 +49,19 @@ func Load() Config {
 	cfg.ServiceName = cast.ToString(getOrReturnDefault("SERVICE_NAME", "store"))
 	cfg.LoggerLevel = cast.ToString(getOrReturnDefault("LOGGER_LEVEL", "debug"))
 
+	// no default, anyone who knows it could sign tokens
+	cfg.SecretKey = cast.ToString(getOrReturnDefault("SECRET_KEY", ""))
+	cfg.AccessTokenTTL = cast.ToDuration(getOrReturnDefault("ACCESS_TOKEN_TTL", "1h"))
+	cfg.RefreshTokenTTL = cast.ToDuration(getOrReturnDefault("REFRESH_TOKEN_TTL", "168h"))
+
+	cfg.LoyaltyEarnPercent = cast.ToFloat64(getOrReturnDefault("LOYALTY_EARN_PERCENT", 1))
+
+	cfg.StockAlertInterval = cast.ToDuration(getOrReturnDefault("STOCK_ALERT_INTERVAL", "1m"))
+
+	cfg.CostMethod = cast.ToString(getOrReturnDefault("COST_METHOD", "average"))
+
+	cfg.PriceChangeInterval = cast.ToDuration(getOrReturnDefault("PRICE_CHANGE_INTERVAL", "1m"))
+
 	return cfg
 }
 
@@ -0,0 +1 @@
+drop index if exists staff_login_unique_idx;
@@ -0,0 +1 @@
+CREATE UNIQUE INDEX IF NOT EXISTS staff_login_unique_idx ON staff (login) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+alter table staff drop constraint if exists staff_type_staff_check;
+
+alter table staff add constraint staff_type_staff_check
+    check (type_staff in ('shop_assistant', 'chashier'));
@@ -0,0 +1,4 @@
+ALTER TABLE staff DROP CONSTRAINT IF EXISTS staff_type_staff_check;
+
+ALTER TABLE staff ADD CONSTRAINT staff_type_staff_check
+    CHECK (type_staff IN ('shop_assistant', 'chashier', 'branch_manager', 'admin'));
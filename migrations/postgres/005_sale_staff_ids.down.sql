@@ -0,0 +1,3 @@
+alter table sale alter column shop_assistent_id type varchar(10);
+
+alter table sale alter column cashier_id type varchar(10);
@@ -0,0 +1,3 @@
+ALTER TABLE sale ALTER COLUMN shop_assistent_id TYPE VARCHAR(50);
+
+ALTER TABLE sale ALTER COLUMN cashier_id TYPE VARCHAR(50);
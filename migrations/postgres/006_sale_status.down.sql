@@ -0,0 +1,10 @@
+alter table sale drop constraint if exists sale_status_check;
+
+update sale set status = 'in_procces' where status in ('draft', 'open', 'paid');
+update sale set status = 'succes' where status in ('completed', 'refunded');
+update sale set status = 'cancel' where status = 'cancelled';
+
+alter table sale alter column status drop default;
+
+alter table sale add constraint sale_status_check
+    check (status in ('in_procces', 'succes', 'cancel'));
@@ -0,0 +1,10 @@
+ALTER TABLE sale DROP CONSTRAINT IF EXISTS sale_status_check;
+
+UPDATE sale SET status = 'open' WHERE status = 'in_procces';
+UPDATE sale SET status = 'completed' WHERE status = 'succes';
+UPDATE sale SET status = 'cancelled' WHERE status = 'cancel';
+
+ALTER TABLE sale ALTER COLUMN status SET DEFAULT 'draft';
+
+ALTER TABLE sale ADD CONSTRAINT sale_status_check
+    CHECK (status IN ('draft', 'open', 'paid', 'completed', 'cancelled', 'refunded'));
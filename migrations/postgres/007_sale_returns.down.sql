@@ -0,0 +1,3 @@
+drop table if exists sale_return_product;
+
+drop table if exists sale_return;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS sale_return (
+    id UUID PRIMARY KEY,
+    sale_id UUID REFERENCES sale(id) NOT NULL,
+    staff_id VARCHAR(50) REFERENCES staff(id),
+    payment_type VARCHAR(20) CHECK (payment_type IN ('card', 'cash')),
+    amount numeric(75,4) NOT NULL,
+    reason text,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS sale_return_product (
+    id UUID PRIMARY KEY,
+    sale_return_id UUID REFERENCES sale_return(id) NOT NULL,
+    basket_id UUID REFERENCES basket(id) NOT NULL,
+    product_id UUID REFERENCES product(id),
+    quantity INT NOT NULL,
+    price numeric(75,4) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
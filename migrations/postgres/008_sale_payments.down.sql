@@ -0,0 +1,8 @@
+alter table sale drop constraint if exists sale_payment_type_check;
+
+update sale set payment_type = 'card' where payment_type = 'mixed';
+
+alter table sale add constraint sale_payment_type_check
+    check (payment_type in ('card', 'cash'));
+
+drop table if exists sale_payment;
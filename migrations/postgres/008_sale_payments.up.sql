@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS sale_payment (
+    id UUID PRIMARY KEY,
+    sale_id UUID REFERENCES sale(id) NOT NULL,
+    payment_type VARCHAR(20) NOT NULL CHECK (payment_type IN ('card', 'cash')),
+    amount numeric(75,4) NOT NULL CHECK (amount > 0),
+    tendered numeric(75,4) NOT NULL,
+    change numeric(75,4) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+ALTER TABLE sale DROP CONSTRAINT IF EXISTS sale_payment_type_check;
+
+ALTER TABLE sale ADD CONSTRAINT sale_payment_type_check
+    CHECK (payment_type IN ('card', 'cash', 'mixed'));
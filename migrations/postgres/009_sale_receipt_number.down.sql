@@ -0,0 +1,3 @@
+drop index if exists sale_branch_receipt_number_idx;
+
+alter table sale drop column if exists receipt_number;
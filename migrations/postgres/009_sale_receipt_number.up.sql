@@ -0,0 +1,12 @@
+ALTER TABLE sale ADD COLUMN IF NOT EXISTS receipt_number INT;
+
+UPDATE sale SET receipt_number = numbered.receipt_number
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY branch_id ORDER BY created_at, id) AS receipt_number
+    FROM sale
+    WHERE status IN ('completed', 'refunded')
+) AS numbered
+WHERE sale.id = numbered.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS sale_branch_receipt_number_idx
+    ON sale (branch_id, receipt_number) WHERE receipt_number IS NOT NULL;
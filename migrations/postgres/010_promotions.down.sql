@@ -0,0 +1,8 @@
+drop table if exists basket_promotion;
+
+alter table basket drop column if exists manual_discount_staff_id;
+alter table basket drop column if exists manual_discount_percent;
+alter table basket drop column if exists discount;
+alter table basket drop column if exists base_price;
+
+drop table if exists promotion;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS promotion (
+    id UUID PRIMARY KEY,
+    name VARCHAR(75) NOT NULL,
+    promotion_type VARCHAR(20) NOT NULL CHECK (promotion_type IN ('percent', 'fixed', 'buy_get')),
+    value numeric(75,4) NOT NULL DEFAULT 0,
+    buy_quantity INT NOT NULL DEFAULT 0,
+    get_quantity INT NOT NULL DEFAULT 0,
+    product_id UUID REFERENCES product(id),
+    category_id UUID REFERENCES category(id),
+    branch_id UUID REFERENCES branch(id),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+ALTER TABLE basket ADD COLUMN IF NOT EXISTS base_price numeric(75,4);
+ALTER TABLE basket ADD COLUMN IF NOT EXISTS discount numeric(75,4) NOT NULL DEFAULT 0;
+ALTER TABLE basket ADD COLUMN IF NOT EXISTS manual_discount_percent numeric(75,4) NOT NULL DEFAULT 0;
+ALTER TABLE basket ADD COLUMN IF NOT EXISTS manual_discount_staff_id VARCHAR(50) REFERENCES staff(id);
+
+UPDATE basket SET base_price = price WHERE base_price IS NULL;
+
+CREATE TABLE IF NOT EXISTS basket_promotion (
+    id UUID PRIMARY KEY,
+    basket_id UUID REFERENCES basket(id) NOT NULL,
+    promotion_id UUID REFERENCES promotion(id),
+    staff_id VARCHAR(50) REFERENCES staff(id),
+    amount numeric(75,4) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
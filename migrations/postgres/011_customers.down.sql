@@ -0,0 +1,19 @@
+alter table sale drop constraint if exists sale_payment_type_check;
+
+update sale set payment_type = 'mixed' where payment_type = 'points';
+
+alter table sale add constraint sale_payment_type_check
+    check (payment_type in ('card', 'cash', 'mixed'));
+
+delete from sale_payment where payment_type = 'points';
+
+alter table sale_payment drop constraint if exists sale_payment_payment_type_check;
+
+alter table sale_payment add constraint sale_payment_payment_type_check
+    check (payment_type in ('card', 'cash'));
+
+drop table if exists loyalty_transaction;
+
+alter table sale drop column if exists customer_id;
+
+drop table if exists customer;
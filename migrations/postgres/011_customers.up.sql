@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS customer (
+    id UUID PRIMARY KEY,
+    name VARCHAR(75) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    branch_id UUID REFERENCES branch(id),
+    points numeric(75,4) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS customer_phone_idx ON customer (phone) WHERE deleted_at IS NULL;
+
+ALTER TABLE sale ADD COLUMN IF NOT EXISTS customer_id UUID REFERENCES customer(id);
+
+CREATE TABLE IF NOT EXISTS loyalty_transaction (
+    id UUID PRIMARY KEY,
+    customer_id UUID REFERENCES customer(id) NOT NULL,
+    sale_id UUID REFERENCES sale(id),
+    transaction_type VARCHAR(20) NOT NULL CHECK (transaction_type IN ('earn', 'redeem', 'reverse')),
+    points numeric(75,4) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+ALTER TABLE sale_payment DROP CONSTRAINT IF EXISTS sale_payment_payment_type_check;
+
+ALTER TABLE sale_payment ADD CONSTRAINT sale_payment_payment_type_check
+    CHECK (payment_type IN ('card', 'cash', 'points'));
+
+ALTER TABLE sale DROP CONSTRAINT IF EXISTS sale_payment_type_check;
+
+ALTER TABLE sale ADD CONSTRAINT sale_payment_type_check
+    CHECK (payment_type IN ('card', 'cash', 'points', 'mixed'));
@@ -0,0 +1,9 @@
+drop table if exists supplier_payment;
+
+alter table income drop column if exists supplier_id;
+
+drop table if exists purchase_order_product;
+
+drop table if exists purchase_order;
+
+drop table if exists supplier;
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS supplier (
+    id UUID PRIMARY KEY,
+    name VARCHAR(75) NOT NULL,
+    phone VARCHAR(20),
+    address VARCHAR(75),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order (
+    id UUID PRIMARY KEY,
+    supplier_id UUID REFERENCES supplier(id) NOT NULL,
+    branch_id UUID REFERENCES branch(id) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ordered' CHECK (status IN ('ordered', 'received', 'cancelled')),
+    price numeric(75,4) NOT NULL DEFAULT 0,
+    income_id UUID REFERENCES income(id),
+    received_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_product (
+    id UUID PRIMARY KEY,
+    purchase_order_id UUID REFERENCES purchase_order(id) NOT NULL,
+    product_id UUID REFERENCES product(id) NOT NULL,
+    ordered_count INT NOT NULL CHECK (ordered_count > 0),
+    received_count INT NOT NULL DEFAULT 0,
+    price numeric(75,4) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+ALTER TABLE income ADD COLUMN IF NOT EXISTS supplier_id UUID REFERENCES supplier(id);
+
+CREATE TABLE IF NOT EXISTS supplier_payment (
+    id UUID PRIMARY KEY,
+    supplier_id UUID REFERENCES supplier(id) NOT NULL,
+    staff_id VARCHAR(50) REFERENCES staff(id),
+    amount numeric(75,4) NOT NULL CHECK (amount > 0),
+    description text,
+    created_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
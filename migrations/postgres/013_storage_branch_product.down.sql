@@ -0,0 +1 @@
+drop index if exists storage_branch_product_idx;
@@ -0,0 +1,20 @@
+UPDATE storage SET count = merged.count, updated_at = NOW()
+FROM (
+    SELECT (ARRAY_AGG(id ORDER BY created_at, id))[1] AS id, SUM(count) AS count
+    FROM storage
+    WHERE deleted_at IS NULL
+    GROUP BY branch_id, product_id
+    HAVING COUNT(1) > 1
+) AS merged
+WHERE storage.id = merged.id;
+
+UPDATE storage SET deleted_at = NOW()
+WHERE deleted_at IS NULL AND id NOT IN (
+    SELECT (ARRAY_AGG(id ORDER BY created_at, id))[1]
+    FROM storage
+    WHERE deleted_at IS NULL
+    GROUP BY branch_id, product_id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS storage_branch_product_idx
+    ON storage (branch_id, product_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+drop table if exists transfer_product;
+
+drop table if exists transfer;
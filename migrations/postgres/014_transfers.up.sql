@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS transfer (
+    id UUID PRIMARY KEY,
+    from_branch_id UUID REFERENCES branch(id) NOT NULL,
+    to_branch_id UUID REFERENCES branch(id) NOT NULL CHECK (to_branch_id <> from_branch_id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'in_transit', 'received')),
+    dispatched_by VARCHAR(50) REFERENCES staff(id),
+    dispatched_at TIMESTAMP,
+    received_by VARCHAR(50) REFERENCES staff(id),
+    received_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS transfer_product (
+    id UUID PRIMARY KEY,
+    transfer_id UUID REFERENCES transfer(id) NOT NULL,
+    product_id UUID REFERENCES product(id) NOT NULL,
+    count INT NOT NULL CHECK (count > 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
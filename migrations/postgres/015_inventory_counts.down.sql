@@ -0,0 +1,3 @@
+drop table if exists inventory_count_product;
+
+drop table if exists inventory_count;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS inventory_count (
+    id UUID PRIMARY KEY,
+    branch_id UUID REFERENCES branch(id) NOT NULL,
+    category_id UUID REFERENCES category(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'posted')),
+    reason TEXT,
+    opened_by VARCHAR(50) REFERENCES staff(id),
+    posted_by VARCHAR(50) REFERENCES staff(id),
+    posted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS inventory_count_product (
+    id UUID PRIMARY KEY,
+    inventory_count_id UUID REFERENCES inventory_count(id) NOT NULL,
+    product_id UUID REFERENCES product(id) NOT NULL,
+    expected_count INT NOT NULL,
+    counted_count INT CHECK (counted_count >= 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    UNIQUE (inventory_count_id, product_id)
+);
@@ -0,0 +1,9 @@
+drop index if exists storage_transaction_source_idx;
+
+drop index if exists storage_transaction_branch_product_idx;
+
+alter table storage_transaction drop column if exists reason;
+alter table storage_transaction drop column if exists balance_after;
+alter table storage_transaction drop column if exists source_id;
+alter table storage_transaction drop column if exists source_type;
+alter table storage_transaction drop column if exists branch_id;
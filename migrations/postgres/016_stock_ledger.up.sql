@@ -0,0 +1,11 @@
+ALTER TABLE storage_transaction ADD COLUMN IF NOT EXISTS branch_id UUID REFERENCES branch(id);
+ALTER TABLE storage_transaction ADD COLUMN IF NOT EXISTS source_type VARCHAR(20)
+    CHECK (source_type IN ('sale', 'sale_return', 'income', 'transfer', 'inventory_count', 'adjustment'));
+ALTER TABLE storage_transaction ADD COLUMN IF NOT EXISTS source_id UUID;
+ALTER TABLE storage_transaction ADD COLUMN IF NOT EXISTS balance_after INT;
+ALTER TABLE storage_transaction ADD COLUMN IF NOT EXISTS reason TEXT;
+
+CREATE INDEX IF NOT EXISTS storage_transaction_branch_product_idx
+    ON storage_transaction (branch_id, product_id, created_at);
+CREATE INDEX IF NOT EXISTS storage_transaction_source_idx
+    ON storage_transaction (source_type, source_id);
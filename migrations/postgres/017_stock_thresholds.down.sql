@@ -0,0 +1,3 @@
+alter table storage drop column if exists reorder_count;
+
+alter table storage drop column if exists min_count;
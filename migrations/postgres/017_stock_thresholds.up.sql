@@ -0,0 +1,2 @@
+ALTER TABLE storage ADD COLUMN IF NOT EXISTS min_count INT NOT NULL DEFAULT 0 CHECK (min_count >= 0);
+ALTER TABLE storage ADD COLUMN IF NOT EXISTS reorder_count INT NOT NULL DEFAULT 0 CHECK (reorder_count >= 0);
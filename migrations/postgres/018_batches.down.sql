@@ -0,0 +1,5 @@
+alter table income_products drop column if exists expires_at;
+
+drop table if exists stock_batch_movement;
+
+drop table if exists batch;
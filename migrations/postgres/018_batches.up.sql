@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS batch (
+    id UUID PRIMARY KEY,
+    branch_id UUID REFERENCES branch(id) NOT NULL,
+    product_id UUID REFERENCES product(id) NOT NULL,
+    expires_at TIMESTAMP,
+    cost numeric(75,4) NOT NULL DEFAULT 0,
+    received_count INT NOT NULL,
+    count INT NOT NULL CHECK (count >= 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS batch_branch_product_idx
+    ON batch (branch_id, product_id, expires_at) WHERE deleted_at IS NULL AND count > 0;
+
+CREATE TABLE IF NOT EXISTS stock_batch_movement (
+    id UUID PRIMARY KEY,
+    storage_transaction_id UUID REFERENCES storage_transaction(id) NOT NULL,
+    batch_id UUID REFERENCES batch(id) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    restored INT NOT NULL DEFAULT 0 CHECK (restored >= 0 AND restored <= quantity),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS stock_batch_movement_transaction_idx
+    ON stock_batch_movement (storage_transaction_id);
+
+ALTER TABLE income_products ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+INSERT INTO batch (id, branch_id, product_id, cost, received_count, count)
+SELECT md5(s.id::text || 'batch')::uuid, s.branch_id, s.product_id,
+    COALESCE((SELECT ip.price FROM income_products ip
+        WHERE ip.deleted_at IS NULL AND ip.product_id = s.product_id
+        ORDER BY ip.created_at DESC LIMIT 1), 0),
+    s.count, s.count
+FROM storage s
+WHERE s.deleted_at IS NULL AND s.count > 0 AND s.branch_id IS NOT NULL AND s.product_id IS NOT NULL
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,3 @@
+alter table basket drop column if exists cost;
+
+alter table storage drop column if exists average_cost;
@@ -0,0 +1,14 @@
+ALTER TABLE storage ADD COLUMN IF NOT EXISTS average_cost numeric(75,4) NOT NULL DEFAULT 0;
+
+ALTER TABLE basket ADD COLUMN IF NOT EXISTS cost numeric(75,4);
+
+UPDATE storage s SET average_cost = COALESCE((SELECT ip.price FROM income_products ip
+    WHERE ip.deleted_at IS NULL AND ip.product_id = s.product_id
+    ORDER BY ip.created_at DESC LIMIT 1), 0)
+WHERE s.deleted_at IS NULL;
+
+UPDATE basket b SET cost = b.quantity * COALESCE((SELECT ip.price FROM income_products ip
+    WHERE ip.deleted_at IS NULL AND ip.product_id = b.product_id AND ip.created_at <= b.created_at
+    ORDER BY ip.created_at DESC LIMIT 1), 0)
+FROM sale s
+WHERE s.id = b.sale_id AND s.status IN ('completed', 'refunded') AND b.cost IS NULL;
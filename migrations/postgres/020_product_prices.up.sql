@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS product_price (
+    id UUID PRIMARY KEY,
+    product_id UUID REFERENCES product(id) NOT NULL,
+    branch_id UUID REFERENCES branch(id),
+    price numeric(75,4) NOT NULL CHECK (price >= 0),
+    effective_from TIMESTAMP NOT NULL,
+    staff_id VARCHAR(50) REFERENCES staff(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS product_price_product_branch_idx
+    ON product_price (product_id, branch_id, effective_from) WHERE deleted_at IS NULL;
+
+INSERT INTO product_price (id, product_id, price, effective_from)
+SELECT md5(p.id::text || 'price')::uuid, p.id, p.price, COALESCE(p.created_at, NOW())
+FROM product p
+WHERE p.deleted_at IS NULL
+ON CONFLICT (id) DO NOTHING;
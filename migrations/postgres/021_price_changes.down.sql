@@ -0,0 +1,3 @@
+drop table if exists price_change_product;
+
+drop table if exists price_change;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS price_change (
+    id UUID PRIMARY KEY,
+    branch_id UUID REFERENCES branch(id),
+    category_id UUID REFERENCES category(id),
+    change_type VARCHAR(20) NOT NULL CHECK (change_type IN ('percent', 'absolute')),
+    value numeric(75,4) NOT NULL,
+    round_to numeric(75,4) NOT NULL DEFAULT 0 CHECK (round_to >= 0),
+    rounding VARCHAR(20) NOT NULL DEFAULT 'nearest' CHECK (rounding IN ('nearest', 'up', 'down')),
+    effective_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'approved', 'applied')),
+    created_by VARCHAR(50) REFERENCES staff(id),
+    approved_by VARCHAR(50) REFERENCES staff(id),
+    approved_at TIMESTAMP,
+    applied_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS price_change_due_idx
+    ON price_change (effective_at) WHERE deleted_at IS NULL AND status = 'approved';
+
+CREATE TABLE IF NOT EXISTS price_change_product (
+    id UUID PRIMARY KEY,
+    price_change_id UUID REFERENCES price_change(id) NOT NULL,
+    product_id UUID REFERENCES product(id) NOT NULL,
+    old_price numeric(75,4),
+    new_price numeric(75,4),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    UNIQUE (price_change_id, product_id)
+);
@@ -0,0 +1 @@
+alter table sale_return drop column if exists points;
@@ -0,0 +1 @@
+ALTER TABLE sale_return ADD COLUMN IF NOT EXISTS points numeric(75,4) NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+update price_change set status = 'approved' where status = 'failed';
+
+alter table price_change drop constraint if exists price_change_status_check;
+
+alter table price_change add constraint price_change_status_check
+    check (status in ('draft', 'approved', 'applied'));
+
+alter table price_change drop column if exists failure;
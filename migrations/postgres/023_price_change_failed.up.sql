@@ -0,0 +1,6 @@
+ALTER TABLE price_change ADD COLUMN IF NOT EXISTS failure TEXT;
+
+ALTER TABLE price_change DROP CONSTRAINT IF EXISTS price_change_status_check;
+
+ALTER TABLE price_change ADD CONSTRAINT price_change_status_check
+    CHECK (status IN ('draft', 'approved', 'applied', 'failed'));
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS stock_alert (
+    id UUID PRIMARY KEY,
+    storage_transaction_id UUID REFERENCES storage_transaction(id) NOT NULL UNIQUE,
+    branch_id UUID REFERENCES branch(id) NOT NULL,
+    product_id UUID REFERENCES product(id) NOT NULL,
+    sale_id UUID REFERENCES sale(id),
+    count INT NOT NULL,
+    min_count INT NOT NULL,
+    reorder_count INT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS stock_alert_undelivered_idx
+    ON stock_alert (created_at) WHERE delivered_at IS NULL;
@@ -0,0 +1 @@
+alter table sale drop column if exists completed_at;
@@ -0,0 +1,8 @@
+ALTER TABLE sale ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+
+-- A sale's payments are written in the transaction that completes it. Sales
+-- paid before split tenders keep the time they were opened.
+UPDATE sale s SET completed_at = COALESCE(
+    (SELECT MAX(sp.created_at) FROM sale_payment sp WHERE sp.sale_id = s.id),
+    s.created_at)
+WHERE s.status IN ('completed', 'refunded');
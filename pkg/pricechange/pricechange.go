@@ -0,0 +1,86 @@
+// Package pricechange holds the rules of price-change documents: how a
+// change and its rounding turn an old price into a new one.
+package pricechange
+
+import "math"
+
+// Kinds of change.
+const (
+	Percent  = "percent"
+	Absolute = "absolute"
+)
+
+// Rounding directions.
+const (
+	Nearest = "nearest"
+	Up      = "up"
+	Down    = "down"
+)
+
+// Document statuses. A draft is approved before the scheduler applies it. A
+// document the scheduler could not apply is failed and left as it was.
+const (
+	Draft    = "draft"
+	Approved = "approved"
+	Applied  = "applied"
+	Failed   = "failed"
+)
+
+// Rule is the change a document makes to every price it targets. A percent
+// value of 10 raises a price by a tenth, an absolute one adds the value.
+// RoundTo is the step the new price is rounded to, none when zero.
+type Rule struct {
+	ChangeType string
+	Value      float64
+	RoundTo    float64
+	Rounding   string
+}
+
+// IsValid reports whether the rule can be applied: a known change that can't
+// take every price below zero, a known rounding and a non-negative step.
+func (r Rule) IsValid() bool {
+	switch r.ChangeType {
+	case Percent:
+		if r.Value <= -100 {
+			return false
+		}
+	case Absolute:
+	default:
+		return false
+	}
+
+	switch r.Rounding {
+	case Nearest, Up, Down:
+	default:
+		return false
+	}
+
+	return r.RoundTo >= 0
+}
+
+// NewPrice applies the rule to price. The result is never negative.
+func (r Rule) NewPrice(price float64) float64 {
+
+	if r.ChangeType == Percent {
+		price += price * r.Value / 100
+	} else {
+		price += r.Value
+	}
+
+	if r.RoundTo > 0 {
+		steps := price / r.RoundTo
+
+		switch r.Rounding {
+		case Up:
+			steps = math.Ceil(steps)
+		case Down:
+			steps = math.Floor(steps)
+		default:
+			steps = math.Round(steps)
+		}
+
+		price = steps * r.RoundTo
+	}
+
+	return math.Max(price, 0)
+}
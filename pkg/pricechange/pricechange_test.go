@@ -0,0 +1,52 @@
+package pricechange
+
+import "testing"
+
+func TestRuleIsValid(t *testing.T) {
+
+	tests := []struct {
+		name string
+		rule Rule
+		want bool
+	}{
+		{"percent up", Rule{ChangeType: Percent, Value: 10, Rounding: Nearest}, true},
+		{"percent down", Rule{ChangeType: Percent, Value: -99.5, Rounding: Down}, true},
+		{"percent to zero", Rule{ChangeType: Percent, Value: -100, Rounding: Nearest}, false},
+		{"absolute down", Rule{ChangeType: Absolute, Value: -5000, RoundTo: 100, Rounding: Up}, true},
+		{"unknown change", Rule{ChangeType: "multiply", Value: 2, Rounding: Nearest}, false},
+		{"unknown rounding", Rule{ChangeType: Percent, Value: 10, Rounding: "bankers"}, false},
+		{"no rounding", Rule{ChangeType: Percent, Value: 10}, false},
+		{"negative step", Rule{ChangeType: Absolute, Value: 10, RoundTo: -100, Rounding: Nearest}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rule.IsValid(); got != tt.want {
+			t.Errorf("%s: IsValid = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRuleNewPrice(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		price float64
+		want  float64
+	}{
+		{"percent", Rule{ChangeType: Percent, Value: 10, Rounding: Nearest}, 12000, 13200},
+		{"percent down", Rule{ChangeType: Percent, Value: -25, Rounding: Nearest}, 12000, 9000},
+		{"absolute", Rule{ChangeType: Absolute, Value: 750, Rounding: Nearest}, 12000, 12750},
+		{"nearest", Rule{ChangeType: Percent, Value: 7, RoundTo: 100, Rounding: Nearest}, 12000, 12800},
+		{"up", Rule{ChangeType: Percent, Value: 7, RoundTo: 500, Rounding: Up}, 12000, 13000},
+		{"down", Rule{ChangeType: Percent, Value: 7, RoundTo: 500, Rounding: Down}, 12000, 12500},
+		{"already on the step", Rule{ChangeType: Absolute, Value: 1000, RoundTo: 500, Rounding: Up}, 12000, 13000},
+		{"never below zero", Rule{ChangeType: Absolute, Value: -20000, Rounding: Nearest}, 12000, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rule.NewPrice(tt.price); got != tt.want {
+			t.Errorf("%s: NewPrice(%v) = %v, want %v", tt.name, tt.price, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package pricechange
+
+import (
+	"bazaar/pkg/logger"
+	"bazaar/storage"
+	"context"
+	"time"
+)
+
+// Scheduler applies the approved price changes that came due every interval.
+type Scheduler struct {
+	storage  storage.IStorage
+	log      logger.ILogger
+	interval time.Duration
+}
+
+func NewScheduler(storage storage.IStorage, log logger.ILogger, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		storage:  storage,
+		log:      log,
+		interval: interval,
+	}
+}
+
+// Run applies due price changes until ctx is done, first right away for the
+// ones that came due while the service was down. A zero interval turns the
+// scheduler off.
+func (s *Scheduler) Run(ctx context.Context) {
+
+	if s.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.apply(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) apply(ctx context.Context) {
+
+	ids, err := s.storage.PriceChange().ApplyDue(ctx, time.Now())
+	for _, id := range ids {
+		s.log.Info("price change applied", logger.String("price_change_id", id))
+	}
+
+	if err != nil {
+		s.log.Error("error while applying price changes", logger.Error(err))
+	}
+}
@@ -0,0 +1,116 @@
+package pricechange
+
+import (
+	"bazaar/pkg/logger"
+	"bazaar/storage"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testStorage struct {
+	storage.IStorage
+	priceChanges *testPriceChangeRepo
+}
+
+func (s testStorage) PriceChange() storage.IPriceChangeRepo {
+	return s.priceChanges
+}
+
+// testPriceChangeRepo returns the applied ids and the error it is given and
+// records when it was asked for the due changes.
+type testPriceChangeRepo struct {
+	storage.IPriceChangeRepo
+	applied []string
+	err     error
+	calls   []time.Time
+}
+
+func (r *testPriceChangeRepo) ApplyDue(ctx context.Context, at time.Time) ([]string, error) {
+	r.calls = append(r.calls, at)
+	return r.applied, r.err
+}
+
+type testLogger struct {
+	infos, errors []string
+}
+
+func (l *testLogger) Info(msg string, fields ...logger.Field) {
+	for _, field := range fields {
+		l.infos = append(l.infos, msg+" "+field.String)
+	}
+}
+
+func (l *testLogger) Error(msg string, fields ...logger.Field) {
+	l.errors = append(l.errors, msg)
+}
+
+func (l *testLogger) Warning(msg string, fields ...logger.Field) {}
+
+func TestSchedulerApply(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		applied    []string
+		err        error
+		wantInfos  []string
+		wantErrors int
+	}{
+		{"nothing due", []string{}, nil, nil, 0},
+		{"applied", []string{"a", "b"}, nil, []string{"price change applied a", "price change applied b"}, 0},
+		{"applied with one failed", []string{"a"}, errors.New("mark failed"), []string{"price change applied a"}, 1},
+		{"due changes not read", nil, errors.New("select due"), nil, 1},
+	}
+
+	for _, tt := range tests {
+		repo := &testPriceChangeRepo{applied: tt.applied, err: tt.err}
+		log := &testLogger{}
+
+		before := time.Now()
+		NewScheduler(testStorage{priceChanges: repo}, log, time.Minute).apply(context.Background())
+
+		if len(repo.calls) != 1 {
+			t.Fatalf("%s: ApplyDue called %d times, want 1", tt.name, len(repo.calls))
+		}
+
+		if at := repo.calls[0]; at.Before(before) || at.After(time.Now()) {
+			t.Errorf("%s: ApplyDue called at %v, want now", tt.name, at)
+		}
+
+		if len(log.infos) != len(tt.wantInfos) {
+			t.Fatalf("%s: logged %q, want %q", tt.name, log.infos, tt.wantInfos)
+		}
+
+		for i := range tt.wantInfos {
+			if log.infos[i] != tt.wantInfos[i] {
+				t.Errorf("%s: logged %q, want %q", tt.name, log.infos[i], tt.wantInfos[i])
+			}
+		}
+
+		if len(log.errors) != tt.wantErrors {
+			t.Errorf("%s: logged %d errors, want %d", tt.name, len(log.errors), tt.wantErrors)
+		}
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+
+	repo := &testPriceChangeRepo{}
+
+	NewScheduler(testStorage{priceChanges: repo}, &testLogger{}, 0).Run(context.Background())
+	if len(repo.calls) != 0 {
+		t.Errorf("scheduler without an interval applied changes %d times", len(repo.calls))
+	}
+
+	// a failed run doesn't stop the scheduler
+	repo = &testPriceChangeRepo{err: errors.New("select due")}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	NewScheduler(testStorage{priceChanges: repo}, &testLogger{}, 5*time.Millisecond).Run(ctx)
+	if len(repo.calls) < 2 {
+		t.Errorf("scheduler applied changes %d times, want it to keep going after an error", len(repo.calls))
+	}
+}
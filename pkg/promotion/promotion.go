@@ -0,0 +1,67 @@
+package promotion
+
+import (
+	"bazaar/api/models"
+	"math"
+)
+
+// Promotion types as they are stored in promotion.promotion_type.
+const (
+	Percent = "percent"
+	Fixed   = "fixed"
+	BuyGet  = "buy_get"
+)
+
+func IsValid(p models.Promotion) bool {
+	switch p.PromotionType {
+	case Percent:
+		return p.Value > 0 && p.Value <= 100
+	case Fixed:
+		return p.Value > 0
+	case BuyGet:
+		return p.BuyQuantity > 0 && p.GetQuantity > 0
+	}
+	return false
+}
+
+// Discount is what the promotion takes off a basket line. Fixed promotions
+// take their value off every unit, buy N get M gives M units free for every
+// N+M in the line.
+func Discount(p models.Promotion, unitPrice float64, quantity int) float64 {
+
+	base := unitPrice * float64(quantity)
+
+	var discount float64
+	switch p.PromotionType {
+	case Percent:
+		discount = base * p.Value / 100
+	case Fixed:
+		discount = p.Value * float64(quantity)
+	case BuyGet:
+		if p.BuyQuantity > 0 && p.GetQuantity > 0 {
+			free := quantity / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+			discount = unitPrice * float64(free)
+		}
+	}
+
+	return math.Min(math.Max(discount, 0), base)
+}
+
+// Best picks the promotion with the largest discount for the line. Promotions
+// do not stack, so only the best one is applied.
+func Best(promotions []models.Promotion, unitPrice float64, quantity int) (models.Promotion, float64, bool) {
+
+	var (
+		best     models.Promotion
+		discount float64
+		found    bool
+	)
+
+	for _, p := range promotions {
+		if d := Discount(p, unitPrice, quantity); d > discount {
+			best, discount, found = p, d, true
+		}
+	}
+
+	return best, discount, found
+}
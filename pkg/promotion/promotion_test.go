@@ -0,0 +1,93 @@
+package promotion
+
+import (
+	"bazaar/api/models"
+	"testing"
+)
+
+func TestIsValid(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		promotion models.Promotion
+		want      bool
+	}{
+		{"percent", models.Promotion{PromotionType: Percent, Value: 10}, true},
+		{"percent of all", models.Promotion{PromotionType: Percent, Value: 100}, true},
+		{"percent over 100", models.Promotion{PromotionType: Percent, Value: 101}, false},
+		{"percent zero", models.Promotion{PromotionType: Percent}, false},
+		{"fixed", models.Promotion{PromotionType: Fixed, Value: 500}, true},
+		{"fixed negative", models.Promotion{PromotionType: Fixed, Value: -1}, false},
+		{"buy get", models.Promotion{PromotionType: BuyGet, BuyQuantity: 2, GetQuantity: 1}, true},
+		{"buy get nothing", models.Promotion{PromotionType: BuyGet, BuyQuantity: 2}, false},
+		{"unknown type", models.Promotion{PromotionType: "coupon", Value: 10}, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValid(tt.promotion); got != tt.want {
+			t.Errorf("%s: IsValid = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiscount(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		promotion models.Promotion
+		unitPrice float64
+		quantity  int
+		want      float64
+	}{
+		{"percent", models.Promotion{PromotionType: Percent, Value: 10}, 1000, 3, 300},
+		{"fixed per unit", models.Promotion{PromotionType: Fixed, Value: 150}, 1000, 3, 450},
+		{"fixed over price", models.Promotion{PromotionType: Fixed, Value: 1500}, 1000, 2, 2000},
+		{"buy 2 get 1", models.Promotion{PromotionType: BuyGet, BuyQuantity: 2, GetQuantity: 1}, 1000, 7, 2000},
+		{"buy 2 get 1 short", models.Promotion{PromotionType: BuyGet, BuyQuantity: 2, GetQuantity: 1}, 1000, 2, 0},
+		{"buy get without quantities", models.Promotion{PromotionType: BuyGet}, 1000, 5, 0},
+		{"negative value", models.Promotion{PromotionType: Fixed, Value: -100}, 1000, 1, 0},
+		{"unknown type", models.Promotion{PromotionType: "coupon", Value: 10}, 1000, 1, 0},
+	}
+
+	for _, tt := range tests {
+		if got := Discount(tt.promotion, tt.unitPrice, tt.quantity); got != tt.want {
+			t.Errorf("%s: Discount = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBest(t *testing.T) {
+
+	var (
+		tenPercent = models.Promotion{ID: "ten", PromotionType: Percent, Value: 10}
+		fixed      = models.Promotion{ID: "fixed", PromotionType: Fixed, Value: 200}
+		buyGet     = models.Promotion{ID: "buy_get", PromotionType: BuyGet, BuyQuantity: 1, GetQuantity: 1}
+		sameFixed  = models.Promotion{ID: "same_fixed", PromotionType: Fixed, Value: 200}
+		invalid    = models.Promotion{ID: "invalid", PromotionType: "coupon", Value: 90}
+	)
+
+	tests := []struct {
+		name       string
+		promotions []models.Promotion
+		quantity   int
+		wantID     string
+		want       float64
+		wantFound  bool
+	}{
+		{"none", nil, 2, "", 0, false},
+		{"only one without discount", []models.Promotion{invalid}, 2, "", 0, false},
+		{"largest discount wins", []models.Promotion{tenPercent, fixed}, 2, "fixed", 400, true},
+		{"promotions don't stack", []models.Promotion{tenPercent, fixed, buyGet}, 2, "buy_get", 1000, true},
+		{"buy get needs the quantity", []models.Promotion{tenPercent, buyGet}, 1, "ten", 100, true},
+		{"first of equal discounts", []models.Promotion{fixed, sameFixed}, 2, "fixed", 400, true},
+	}
+
+	for _, tt := range tests {
+		best, discount, found := Best(tt.promotions, 1000, tt.quantity)
+
+		if found != tt.wantFound || best.ID != tt.wantID || discount != tt.want {
+			t.Errorf("%s: Best = %q, %v, %v, want %q, %v, %v",
+				tt.name, best.ID, discount, found, tt.wantID, tt.want, tt.wantFound)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package receipt
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/salestatus"
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-fonts/dejavu/dejavusansmono"
+	"github.com/go-pdf/fpdf"
+)
+
+// Width is the number of characters that fit on a line of 80mm thermal paper.
+const Width = 48
+
+const (
+	pageWidth  = 226.77 // 80mm in points
+	margin     = 5.4
+	fontSize   = 7.5
+	lineHeight = 9
+	fontFamily = "DejaVuSansMono"
+)
+
+// Text renders the receipt in the plain-text layout of an 80mm thermal printer.
+func Text(r models.Receipt) string {
+	return strings.Join(lines(r), "\n") + "\n"
+}
+
+// PDF renders the receipt as a single 80mm wide page set in DejaVu Sans Mono,
+// so names in any of its scripts print as they are. Only the glyphs the
+// receipt uses are embedded.
+func PDF(r models.Receipt) ([]byte, error) {
+
+	var (
+		text   = lines(r)
+		height = float64(len(text))*lineHeight + 2*margin
+		pdf    = fpdf.NewCustom(&fpdf.InitType{
+			UnitStr: "pt",
+			Size:    fpdf.SizeType{Wd: pageWidth, Ht: height},
+		})
+	)
+
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetCreationDate(r.CreatedAt)
+	pdf.SetCatalogSort(true)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", dejavusansmono.TTF)
+	pdf.SetFont(fontFamily, "", fontSize)
+	pdf.AddPage()
+
+	left := (pageWidth - pdf.GetStringWidth(strings.Repeat(" ", Width))) / 2
+	for i, line := range text {
+		pdf.Text(left, margin+fontSize+float64(i)*lineHeight, line)
+	}
+
+	out := bytes.Buffer{}
+	if err := pdf.Output(&out); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func lines(r models.Receipt) []string {
+
+	var (
+		rule = strings.Repeat("-", Width)
+		out  = []string{}
+	)
+
+	out = append(out, center(r.BranchName), center(r.BranchAddress), rule)
+	out = append(out, spread(fmt.Sprintf("Receipt #%d", r.ReceiptNumber), r.CreatedAt.Format("2006-01-02 15:04")))
+
+	// a sale cancelled or refunded since keeps its receipt, marked as such
+	if r.Status != "" && r.Status != salestatus.Completed {
+		out = append(out, center("*** "+strings.ToUpper(r.Status)+" ***"))
+	}
+
+	if r.CashierName != "" {
+		out = append(out, cut("Cashier: "+r.CashierName))
+	}
+	if r.ClientName != "" {
+		out = append(out, cut("Client: "+r.ClientName))
+	}
+
+	out = append(out, rule)
+
+	for _, line := range r.Lines {
+		out = append(out,
+			cut(line.ProductName),
+			spread(fmt.Sprintf("  %d x %.2f", line.Quantity, line.UnitPrice), fmt.Sprintf("%.2f", line.Price)),
+			cut("  "+line.Barcode),
+		)
+	}
+
+	out = append(out, rule, spread("TOTAL", fmt.Sprintf("%.2f", r.TotalPrice)))
+
+	for _, payment := range r.Payments {
+		out = append(out, spread(payment.PaymentType, fmt.Sprintf("%.2f", payment.Tendered)))
+	}
+
+	if r.Change > 0 {
+		out = append(out, spread("CHANGE", fmt.Sprintf("%.2f", r.Change)))
+	}
+
+	return append(out, rule, center("Thank you for your purchase!"))
+}
+
+func cut(s string) string {
+	if utf8.RuneCountInString(s) <= Width {
+		return s
+	}
+	return string([]rune(s)[:Width])
+}
+
+func center(s string) string {
+	s = cut(s)
+	return strings.Repeat(" ", (Width-utf8.RuneCountInString(s))/2) + s
+}
+
+// spread puts left and right on one line, pushing right to the edge. Left is
+// cut to make room for right, and right to fit the line on its own.
+func spread(left, right string) string {
+	right = cut(right)
+	gap := Width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap < 1 {
+		keep := utf8.RuneCountInString(left) + gap - 1
+		if keep < 0 {
+			keep = 0
+		}
+		left = string([]rune(left)[:keep])
+		gap = Width - keep - utf8.RuneCountInString(right)
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
@@ -0,0 +1,202 @@
+package receipt
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/salestatus"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func testReceipt() models.Receipt {
+	return models.Receipt{
+		ReceiptNumber: 42,
+		Status:        salestatus.Completed,
+		BranchName:    "Chilonzor",
+		BranchAddress: "Bunyodkor 1, Toshkent",
+		CashierName:   "Ғайрат Қодиров",
+		ClientName:    "Oʻtkir",
+		Lines: []models.ReceiptLine{
+			{ProductName: "Non", Barcode: "4780000000017", Quantity: 2, UnitPrice: 3000, Price: 6000},
+			{ProductName: "Молоко 1л", Barcode: "4780000000024", Quantity: 1, UnitPrice: 12500.5, Price: 12500.5},
+		},
+		Payments: []models.SalePayment{
+			{PaymentType: "cash", Amount: 18500.5, Tendered: 20000, Change: 1499.5},
+		},
+		TotalPrice: 18500.5,
+		Change:     1499.5,
+		CreatedAt:  time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestText(t *testing.T) {
+
+	text := Text(testReceipt())
+
+	if !strings.HasSuffix(text, "\n") {
+		t.Fatalf("text does not end with a new line")
+	}
+
+	for i, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if n := utf8.RuneCountInString(line); n > Width {
+			t.Errorf("line %d is %d characters wide, more than %d: %q", i, n, Width, line)
+		}
+	}
+
+	for _, want := range []string{
+		"Receipt #42",
+		"2026-10-18 09:30",
+		"Cashier: Ғайрат Қодиров",
+		"Client: Oʻtkir",
+		"Молоко 1л",
+		"  1 x 12500.50",
+		"TOTAL",
+		"18500.50",
+		"CHANGE",
+		"Thank you for your purchase!",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text misses %q:\n%s", want, text)
+		}
+	}
+
+	if strings.Contains(text, "***") {
+		t.Errorf("completed sale is marked:\n%s", text)
+	}
+}
+
+func TestTextStatus(t *testing.T) {
+
+	tests := []struct {
+		status string
+		mark   string
+	}{
+		{salestatus.Completed, ""},
+		{salestatus.Cancelled, "*** CANCELLED ***"},
+		{salestatus.Refunded, "*** REFUNDED ***"},
+	}
+
+	for _, tt := range tests {
+		r := testReceipt()
+		r.Status = tt.status
+
+		text := Text(r)
+		if tt.mark == "" {
+			if strings.Contains(text, "***") {
+				t.Errorf("%s: receipt is marked:\n%s", tt.status, text)
+			}
+			continue
+		}
+
+		if !strings.Contains(text, tt.mark) {
+			t.Errorf("%s: receipt misses %q:\n%s", tt.status, tt.mark, text)
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		left, right string
+		want        string
+	}{
+		{"fits", "TOTAL", "10.00", "TOTAL" + strings.Repeat(" ", Width-10) + "10.00"},
+		{"long left", strings.Repeat("a", Width), "10.00", strings.Repeat("a", Width-6) + " 10.00"},
+		{"wide right", "TOTAL", strings.Repeat("9", Width+5), strings.Repeat("9", Width)},
+		{"right fills line", "TOTAL", strings.Repeat("9", Width-1), " " + strings.Repeat("9", Width-1)},
+	}
+
+	for _, tt := range tests {
+		got := spread(tt.left, tt.right)
+		if got != tt.want {
+			t.Errorf("%s: spread(%q, %q) = %q, want %q", tt.name, tt.left, tt.right, got, tt.want)
+		}
+		if n := utf8.RuneCountInString(got); n != Width {
+			t.Errorf("%s: line is %d characters wide, want %d", tt.name, n, Width)
+		}
+	}
+}
+
+func TestCenterAndCut(t *testing.T) {
+
+	if got := center("ab"); got != strings.Repeat(" ", (Width-2)/2)+"ab" {
+		t.Errorf("center(%q) = %q", "ab", got)
+	}
+
+	long := strings.Repeat("ж", Width+3)
+	if got := cut(long); utf8.RuneCountInString(got) != Width {
+		t.Errorf("cut kept %d characters, want %d", utf8.RuneCountInString(got), Width)
+	}
+
+	if got := center(long); got != strings.Repeat("ж", Width) {
+		t.Errorf("center of a long line = %q", got)
+	}
+}
+
+func TestPDF(t *testing.T) {
+
+	pdf, err := PDF(testReceipt())
+	if err != nil {
+		t.Fatalf("PDF: %v", err)
+	}
+
+	if len(pdf) == 0 {
+		t.Fatalf("PDF is empty")
+	}
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Fatalf("PDF does not start with a header: %q", pdf[:10])
+	}
+
+	if !bytes.HasSuffix(bytes.TrimRight(pdf, "\n"), []byte("%%EOF")) {
+		t.Fatalf("PDF does not end with %%%%EOF")
+	}
+
+	// the cross-reference table has to point at every object it lists
+	startxref := regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF\s*$`).FindSubmatch(pdf)
+	if startxref == nil {
+		t.Fatalf("PDF has no startxref")
+	}
+
+	offset, _ := strconv.Atoi(string(startxref[1]))
+	if offset >= len(pdf) || !bytes.HasPrefix(pdf[offset:], []byte("xref")) {
+		t.Fatalf("startxref %d does not point at the xref table", offset)
+	}
+
+	section := regexp.MustCompile(`^xref\s+(\d+) (\d+)\s+`).FindSubmatch(pdf[offset:])
+	if section == nil {
+		t.Fatalf("xref table has no section")
+	}
+
+	first, _ := strconv.Atoi(string(section[1]))
+	count, _ := strconv.Atoi(string(section[2]))
+	entries := regexp.MustCompile(`(\d{10}) (\d{5}) ([nf])`).FindAllSubmatch(pdf[offset:], count)
+	if len(entries) != count {
+		t.Fatalf("xref table lists %d entries, want %d", len(entries), count)
+	}
+
+	for i, entry := range entries {
+		if string(entry[3]) != "n" {
+			continue
+		}
+
+		at, _ := strconv.Atoi(string(entry[1]))
+		object := []byte(strconv.Itoa(first+i) + " 0 obj")
+		if at >= len(pdf) || !bytes.HasPrefix(pdf[at:], object) {
+			t.Errorf("xref entry %d does not point at %q", first+i, object)
+		}
+	}
+
+	if !bytes.Contains(pdf, []byte("/MediaBox [0 0 226.77")) {
+		t.Errorf("PDF page is not 80mm wide")
+	}
+
+	if !bytes.Contains(pdf, []byte("/ToUnicode")) {
+		t.Errorf("PDF font has no ToUnicode map")
+	}
+}
@@ -0,0 +1,42 @@
+package role
+
+// Staff roles as they are stored in staff.type_staff.
+const (
+	Cashier       = "chashier"
+	ShopAssistant = "shop_assistant"
+	BranchManager = "branch_manager"
+	Admin         = "admin"
+)
+
+// CrossBranch lists roles that are not limited to their own branch.
+var CrossBranch = []string{Admin}
+
+func IsValid(typeStaff string) bool {
+	switch typeStaff {
+	case Cashier, ShopAssistant, BranchManager, Admin:
+		return true
+	}
+	return false
+}
+
+func Has(typeStaff string, roles ...string) bool {
+	for _, r := range roles {
+		if r == typeStaff {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxDiscountPercent caps the manual discount a role may give on a basket line.
+func MaxDiscountPercent(typeStaff string) float64 {
+	switch typeStaff {
+	case Cashier:
+		return 10
+	case BranchManager:
+		return 30
+	case Admin:
+		return 100
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+// Package salestatus holds the sale lifecycle. Every change of sale.status
+// and every basket change must be checked here first.
+package salestatus
+
+import "fmt"
+
+const (
+	Draft     = "draft"
+	Open      = "open"
+	Paid      = "paid"
+	Completed = "completed"
+	Cancelled = "cancelled"
+	Refunded  = "refunded"
+)
+
+var transitions = map[string][]string{
+	Draft:     {Open, Cancelled},
+	Open:      {Paid, Cancelled},
+	Paid:      {Completed, Cancelled},
+	Completed: {Refunded, Cancelled},
+}
+
+// TransitionError is returned when a sale can't move from one status to
+// another.
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e TransitionError) Error() string {
+	return fmt.Sprintf("sale can't move from %q to %q", e.From, e.To)
+}
+
+func IsValid(status string) bool {
+	switch status {
+	case Draft, Open, Paid, Completed, Cancelled, Refunded:
+		return true
+	}
+	return false
+}
+
+// Transition checks that a sale in status from may move to status to.
+func Transition(from, to string) error {
+	for _, next := range transitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return TransitionError{From: from, To: to}
+}
+
+// CanEdit checks that a sale in the given status may still have its details
+// and basket lines added, changed or removed.
+func CanEdit(status string) error {
+	if status == Draft || status == Open {
+		return nil
+	}
+	return TransitionError{From: status, To: Open}
+}
+
+// CanReturn checks that goods of a sale in the given status may be returned.
+func CanReturn(status string) error {
+	if status == Completed {
+		return nil
+	}
+	return TransitionError{From: status, To: Refunded}
+}
+
+// CanDelete checks that a sale in the given status has left no trace in
+// storage or staff balances and may be deleted.
+func CanDelete(status string) error {
+	if status == Draft || status == Open || status == Cancelled {
+		return nil
+	}
+	return TransitionError{From: status, To: Cancelled}
+}
@@ -0,0 +1,105 @@
+package salestatus
+
+import (
+	"errors"
+	"testing"
+)
+
+var statuses = []string{Draft, Open, Paid, Completed, Cancelled, Refunded}
+
+func TestTransition(t *testing.T) {
+
+	allowed := map[[2]string]bool{
+		{Draft, Open}:          true,
+		{Draft, Cancelled}:     true,
+		{Open, Paid}:           true,
+		{Open, Cancelled}:      true,
+		{Paid, Completed}:      true,
+		{Paid, Cancelled}:      true,
+		{Completed, Refunded}:  true,
+		{Completed, Cancelled}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			err := Transition(from, to)
+
+			if allowed[[2]string{from, to}] {
+				if err != nil {
+					t.Errorf("Transition(%q, %q) = %v, want nil", from, to, err)
+				}
+				continue
+			}
+
+			var transitionErr TransitionError
+			if !errors.As(err, &transitionErr) {
+				t.Errorf("Transition(%q, %q) = %v, want a TransitionError", from, to, err)
+				continue
+			}
+
+			if transitionErr.From != from || transitionErr.To != to {
+				t.Errorf("Transition(%q, %q) error is from %q to %q", from, to, transitionErr.From, transitionErr.To)
+			}
+		}
+	}
+}
+
+func TestTransitionUnknown(t *testing.T) {
+
+	for _, tt := range [][2]string{
+		{"", Open},
+		{"succes", Completed},
+		{Open, "in_procces"},
+	} {
+		if err := Transition(tt[0], tt[1]); err == nil {
+			t.Errorf("Transition(%q, %q) = nil, want an error", tt[0], tt[1])
+		}
+	}
+}
+
+func TestIsValid(t *testing.T) {
+
+	for _, status := range statuses {
+		if !IsValid(status) {
+			t.Errorf("IsValid(%q) = false", status)
+		}
+	}
+
+	for _, status := range []string{"", "in_procces", "succes", "cancel", "Completed"} {
+		if IsValid(status) {
+			t.Errorf("IsValid(%q) = true", status)
+		}
+	}
+}
+
+func TestChecks(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		check func(string) error
+		allow []string
+	}{
+		{"CanEdit", CanEdit, []string{Draft, Open}},
+		{"CanReturn", CanReturn, []string{Completed}},
+		{"CanDelete", CanDelete, []string{Draft, Open, Cancelled}},
+	}
+
+	for _, tt := range tests {
+		allowed := map[string]bool{}
+		for _, status := range tt.allow {
+			allowed[status] = true
+		}
+
+		for _, status := range statuses {
+			err := tt.check(status)
+
+			if allowed[status] && err != nil {
+				t.Errorf("%s(%q) = %v, want nil", tt.name, status, err)
+			}
+
+			if !allowed[status] && !errors.As(err, &TransitionError{}) {
+				t.Errorf("%s(%q) = %v, want a TransitionError", tt.name, status, err)
+			}
+		}
+	}
+}
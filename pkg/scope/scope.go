@@ -0,0 +1,18 @@
+package scope
+
+import "context"
+
+type branchKey struct{}
+
+// WithBranch binds the context to a single branch. Repositories that store
+// branch_id only read and change rows of that branch.
+func WithBranch(ctx context.Context, branchID string) context.Context {
+	return context.WithValue(ctx, branchKey{}, branchID)
+}
+
+// BranchID returns the branch the context is bound to, or an empty string
+// when the caller may work across branches.
+func BranchID(ctx context.Context) string {
+	branchID, _ := ctx.Value(branchKey{}).(string)
+	return branchID
+}
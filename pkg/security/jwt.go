@@ -0,0 +1,52 @@
+package security
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AccessToken  = "access"
+	RefreshToken = "refresh"
+)
+
+type Claims struct {
+	StaffID   string `json:"staff_id"`
+	BranchID  string `json:"branch_id"`
+	TypeStaff string `json:"type_staff"`
+	TokenType string `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+func GenerateJWT(claims Claims, ttl time.Duration, secretKey string) (string, error) {
+	now := time.Now()
+
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Subject:   claims.StaffID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	return token.SignedString([]byte(secretKey))
+}
+
+func ExtractClaims(tokenString, secretKey string) (Claims, error) {
+	claims := Claims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(secretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if !token.Valid {
+		return Claims{}, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,97 @@
+// Package stockalert delivers the alerts queued when a completed sale takes a
+// product below its minimum to a notifier.
+package stockalert
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/storage"
+	"context"
+	"time"
+)
+
+// Notifier delivers low stock alerts, to a log, a chat or an email.
+type Notifier interface {
+	Notify(ctx context.Context, alert models.StockAlert) error
+}
+
+// LogNotifier writes alerts to the service log.
+type LogNotifier struct {
+	Log logger.ILogger
+}
+
+func (n LogNotifier) Notify(_ context.Context, alert models.StockAlert) error {
+	n.Log.Warning("product is below its minimum stock",
+		logger.String("branch_id", alert.BranchID),
+		logger.String("product_id", alert.ProductID),
+		logger.String("product_name", alert.ProductName),
+		logger.String("sale_id", alert.SaleID),
+		logger.Int("count", alert.Count),
+		logger.Int("min_count", alert.MinCount),
+		logger.Int("reorder_count", alert.ReorderCount),
+	)
+	return nil
+}
+
+// Checker polls the queued alerts every interval and hands the ones not
+// delivered yet to the notifier.
+type Checker struct {
+	storage  storage.IStorage
+	notifier Notifier
+	log      logger.ILogger
+	interval time.Duration
+}
+
+func NewChecker(storage storage.IStorage, notifier Notifier, log logger.ILogger, interval time.Duration) *Checker {
+	return &Checker{
+		storage:  storage,
+		notifier: notifier,
+		log:      log,
+		interval: interval,
+	}
+}
+
+// Run checks until ctx is done. Alerts queued while the checker was off are
+// delivered on its first check. A zero interval turns the checker off.
+func (c *Checker) Run(ctx context.Context) {
+
+	if c.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+// check notifies the undelivered alerts oldest first and marks each one
+// delivered. It stops at an alert the notifier fails on, which is tried again
+// with the ones after it on the next check.
+func (c *Checker) check(ctx context.Context) {
+
+	alerts, err := c.storage.Storage().LowStockAlerts(ctx)
+	if err != nil {
+		c.log.Error("error while checking low stock", logger.Error(err))
+		return
+	}
+
+	for _, alert := range alerts {
+		if err = c.notifier.Notify(ctx, alert); err != nil {
+			c.log.Error("error while notifying low stock", logger.String("stock_alert_id", alert.ID), logger.Error(err))
+			return
+		}
+
+		if err = c.storage.Storage().MarkStockAlertDelivered(ctx, alert.ID); err != nil {
+			c.log.Error("error while marking low stock alert delivered", logger.String("stock_alert_id", alert.ID), logger.Error(err))
+			return
+		}
+	}
+}
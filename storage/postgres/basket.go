@@ -3,16 +3,23 @@ package postgres
 import (
 	"bazaar/api/models"
 	"bazaar/pkg/logger"
+	"bazaar/pkg/promotion"
+	"bazaar/pkg/salestatus"
 	"bazaar/storage"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// basketBranch is the branch a basket line belongs to, the one of its sale.
+const basketBranch = `(select s.branch_id from sale s where s.id = basket.sale_id)`
+
 type basketRepo struct {
 	pool *pgxpool.Pool
 	log  logger.ILogger
@@ -25,25 +32,67 @@ func NewBasketRepo(pool *pgxpool.Pool, log logger.ILogger) storage.IBasketRepo {
 	}
 }
 
-func (b *basketRepo) Create(ctx context.Context, basket models.CreateBasket) (string, error) {
+// Create adds the product to the sale basket in one transaction with the sale
+// locked: the quantity goes onto the sale's line for the product, or onto a
+// new line, within the branch stock, and the line is repriced.
+func (b *basketRepo) Create(ctx context.Context, request models.CreateBasket) (id string, err error) {
 
-	id := uuid.New()
+	if request.ProductID == "" || request.Quantity <= 0 {
+		return "", storage.ErrBasketQuantity
+	}
 
-	query := `insert into basket (id, sale_id, product_id, quantity, price) values ($1, $2, $3, $4, $5)`
+	tx, err := b.pool.Begin(ctx)
+	if err != nil {
+		b.log.Error("error while starting basket transaction", logger.Error(err))
+		return "", err
+	}
 
-	_, err := b.pool.Exec(ctx, query,
-		id,
-		basket.SaleID,
-		basket.ProductID,
-		basket.Quantity,
-		basket.Price,
-	)
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	sale, err := lockOpenSale(ctx, tx, request.SaleID)
 	if err != nil {
-		b.log.Error("error while inserting basket", logger.Error(err))
+		b.log.Error("error while locking basket sale", logger.Error(err))
+		return "", err
+	}
+
+	var quantity int
+	err = tx.QueryRow(ctx, `select id, quantity from basket
+	 where deleted_at is null and sale_id = $1 and product_id = $2
+	 order by created_at limit 1 for update`, sale.ID, request.ProductID).Scan(&id, &quantity)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		b.log.Error("error while selecting sale basket by product", logger.Error(err))
 		return "", err
 	}
 
-	return id.String(), nil
+	if err = checkBasketStock(ctx, tx, sale.BranchID, request.ProductID, quantity+request.Quantity); err != nil {
+		return "", err
+	}
+
+	if id == "" {
+		id = uuid.New().String()
+		_, err = tx.Exec(ctx, `insert into basket (id, sale_id, product_id, quantity, price) values ($1, $2, $3, $4, 0)`,
+			id, sale.ID, request.ProductID, request.Quantity)
+	} else {
+		_, err = tx.Exec(ctx, `update basket set quantity = quantity + $1, updated_at = $2 where id = $3`,
+			request.Quantity, time.Now(), id)
+	}
+	if err != nil {
+		b.log.Error("error while saving basket", logger.Error(err))
+		return "", err
+	}
+
+	if err = repriceBasket(ctx, tx, id); err != nil {
+		b.log.Error("error while repricing basket", logger.Error(err))
+		return "", err
+	}
+
+	return id, nil
 }
 
 func (b *basketRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Basket, error) {
@@ -52,15 +101,21 @@ func (b *basketRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Bask
 
 	basket := models.Basket{}
 
+	condition, args := branchCondition(ctx, basketBranch, []interface{}{id.ID})
+
 	row := b.pool.QueryRow(ctx, `select 
 	id,
     sale_id,
     product_id, 
 	quantity, 
 	price,
+	coalesce(base_price, price),
+	discount,
+	manual_discount_percent,
+	coalesce(cost, 0),
     created_at, 
 	updated_at
-	from basket where deleted_at is null and id = $1`, id.ID)
+	from basket where deleted_at is null and id = $1`+condition, args...)
 
 	err := row.Scan(
 		&basket.ID,
@@ -68,6 +123,10 @@ func (b *basketRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Bask
 		&basket.ProductID,
 		&basket.Quantity,
 		&basket.Price,
+		&basket.BasePrice,
+		&basket.Discount,
+		&basket.ManualDiscountPercent,
+		&basket.Cost,
 		&basket.CreatedAt,
 		&updatedAt,
 	)
@@ -81,6 +140,31 @@ func (b *basketRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Bask
 		basket.UpdatedAt = updatedAt.Time
 	}
 
+	rows, err := b.pool.Query(ctx, `select id, basket_id, coalesce(promotion_id::text, ''), coalesce(staff_id, ''), amount, created_at
+	 from basket_promotion where basket_id = $1 order by created_at`, basket.ID)
+	if err != nil {
+		b.log.Error("error while selecting basket promotions", logger.Error(err))
+		return models.Basket{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		basketPromotion := models.BasketPromotion{}
+		if err = rows.Scan(
+			&basketPromotion.ID,
+			&basketPromotion.BasketID,
+			&basketPromotion.PromotionID,
+			&basketPromotion.StaffID,
+			&basketPromotion.Amount,
+			&basketPromotion.CreatedAt,
+		); err != nil {
+			b.log.Error("error while scanning basket promotion", logger.Error(err))
+			return models.Basket{}, err
+		}
+
+		basket.Promotions = append(basket.Promotions, basketPromotion)
+	}
+
 	return basket, nil
 }
 
@@ -96,13 +180,16 @@ func (b *basketRepo) GetList(ctx context.Context, request models.GetBasketsListR
 		search            = request.Search
 	)
 
-	countQuery = `select count(1) from basket where deleted_at is null `
+	condition, args := branchCondition(ctx, basketBranch, nil)
 
 	if search != "" {
-		countQuery += fmt.Sprintf(` and product_id = '%s' or sale_id = '%s'`, search, search)
+		args = append(args, search)
+		condition += fmt.Sprintf(` and (product_id::text = $%d or sale_id::text = $%d)`, len(args), len(args))
 	}
-	if err := b.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
-		fmt.Println("error is while selecting count", logger.Error(err))
+
+	countQuery = `select count(1) from basket where deleted_at is null` + condition
+	if err := b.pool.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
+		b.log.Error("error is while selecting count", logger.Error(err))
 		return models.BasketsResponse{}, err
 	}
 
@@ -111,21 +198,23 @@ func (b *basketRepo) GetList(ctx context.Context, request models.GetBasketsListR
 	sale_id, 
 	product_id, 
 	quantity, 
-	price, 
+	price,
+	coalesce(base_price, price),
+	discount,
+	manual_discount_percent, 
+	coalesce(cost, 0),
 	created_at, 
 	updated_at
-	from basket where deleted_at is null`
+	from basket where deleted_at is null` + condition
 
-	if search != "" {
-		query += fmt.Sprintf(` and product_id = '%s' or sale_id = '%s'`, search, search)
-	}
-
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := b.pool.Query(ctx, query, request.Limit, offset)
+	args = append(args, request.Limit, offset)
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	rows, err := b.pool.Query(ctx, query, args...)
 	if err != nil {
-		fmt.Println("error is while selecting basket", logger.Error(err))
+		b.log.Error("error is while selecting basket", logger.Error(err))
 		return models.BasketsResponse{}, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		basket := models.Basket{}
@@ -135,10 +224,14 @@ func (b *basketRepo) GetList(ctx context.Context, request models.GetBasketsListR
 			&basket.ProductID,
 			&basket.Quantity,
 			&basket.Price,
+			&basket.BasePrice,
+			&basket.Discount,
+			&basket.ManualDiscountPercent,
+			&basket.Cost,
 			&basket.CreatedAt,
 			&updatedAt,
 		); err != nil {
-			fmt.Println("error is while scanning basket data", logger.Error(err))
+			b.log.Error("error is while scanning basket data", logger.Error(err))
 			return models.BasketsResponse{}, err
 		}
 
@@ -156,48 +249,95 @@ func (b *basketRepo) GetList(ctx context.Context, request models.GetBasketsListR
 	}, nil
 }
 
-func (b *basketRepo) Update(ctx context.Context, request models.UpdateBasket) (string, error) {
+// Update changes a basket line and reprices it, with the sale it leaves and
+// the sale it goes to locked and open for basket changes.
+func (b *basketRepo) Update(ctx context.Context, request models.UpdateBasket) (id string, err error) {
 
-	query := `update basket
-   set sale_id = $1,
-    product_id = $2, 
-	quantity = $3,
-	price = $4,
-	updated_at = $5 
-    where id = $6 
-   `
+	if request.ProductID == "" || request.Quantity <= 0 {
+		return "", storage.ErrBasketQuantity
+	}
 
-	_, err := b.pool.Exec(ctx, query,
-		request.SaleID,
+	tx, err := b.pool.Begin(ctx)
+	if err != nil {
+		b.log.Error("error while starting basket transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	sale, err := lockBasketSale(ctx, tx, request.ID)
+	if err != nil {
+		b.log.Error("error while locking basket sale", logger.Error(err))
+		return "", err
+	}
+
+	if request.SaleID != sale.ID {
+		if sale, err = lockOpenSale(ctx, tx, request.SaleID); err != nil {
+			b.log.Error("error while locking basket sale", logger.Error(err))
+			return "", err
+		}
+	}
+
+	if err = checkBasketStock(ctx, tx, sale.BranchID, request.ProductID, request.Quantity); err != nil {
+		return "", err
+	}
+
+	if _, err = tx.Exec(ctx, `update basket
+	 set sale_id = $1,
+	 product_id = $2,
+	 quantity = $3,
+	 updated_at = $4
+	 where id = $5`,
+		sale.ID,
 		request.ProductID,
 		request.Quantity,
-		request.Price,
 		time.Now(),
-		request.ID)
-	if err != nil {
+		request.ID); err != nil {
 		b.log.Error("error while updating basket data...", logger.Error(err))
 		return "", err
 	}
 
+	if err = repriceBasket(ctx, tx, request.ID); err != nil {
+		b.log.Error("error while repricing basket", logger.Error(err))
+		return "", err
+	}
+
 	return request.ID, nil
 }
 
-func (b *basketRepo) Delete(ctx context.Context, id string) error {
-
-	query := `
-	  update basket
-	  set deleted_at = $1
-	  where id = $2
-	`
+// Delete drops a basket line of a sale that is open for basket changes.
+func (b *basketRepo) Delete(ctx context.Context, id string) (err error) {
 
-	_, err := b.pool.Exec(ctx,
-		query,
-		time.Now(),
-		id)
+	tx, err := b.pool.Begin(ctx)
 	if err != nil {
+		b.log.Error("error while starting basket transaction", logger.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if _, err = lockBasketSale(ctx, tx, id); err != nil {
+		b.log.Error("error while locking basket sale", logger.Error(err))
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `update basket set deleted_at = $1 where id = $2`, time.Now(), id); err != nil {
 		b.log.Error("error while deleting basket by id", logger.Error(err))
 		return err
 	}
+
 	return nil
 }
 
@@ -221,3 +361,247 @@ func (b *basketRepo) UpdateBasketQuantity(ctx context.Context, request models.Up
 
 	return request.ID, nil
 }
+
+// GetBySaleProduct returns the sale's basket line for the product, or an empty
+// basket when the product is not in the sale yet.
+func (b *basketRepo) GetBySaleProduct(ctx context.Context, saleID, productID string) (models.Basket, error) {
+
+	var updatedAt = sql.NullTime{}
+
+	basket := models.Basket{}
+
+	err := b.pool.QueryRow(ctx, `select
+	id,
+	sale_id,
+	product_id,
+	quantity,
+	price,
+	coalesce(base_price, price),
+	discount,
+	manual_discount_percent,
+	coalesce(cost, 0),
+	created_at,
+	updated_at
+	from basket where deleted_at is null and sale_id = $1 and product_id = $2
+	order by created_at limit 1`, saleID, productID).Scan(
+		&basket.ID,
+		&basket.SaleID,
+		&basket.ProductID,
+		&basket.Quantity,
+		&basket.Price,
+		&basket.BasePrice,
+		&basket.Discount,
+		&basket.ManualDiscountPercent,
+		&basket.Cost,
+		&basket.CreatedAt,
+		&updatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Basket{}, nil
+	}
+
+	if err != nil {
+		b.log.Error("error while selecting sale basket by product", logger.Error(err))
+		return models.Basket{}, err
+	}
+
+	if updatedAt.Valid {
+		basket.UpdatedAt = updatedAt.Time
+	}
+
+	return basket, nil
+}
+
+// Reprice works out the basket line price from the product price, the best
+// running promotion and the manual discount, and records what was applied.
+func (b *basketRepo) Reprice(ctx context.Context, id string) (err error) {
+
+	tx, err := b.pool.Begin(ctx)
+	if err != nil {
+		b.log.Error("error while starting reprice basket transaction", logger.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if err = repriceBasket(ctx, tx, id); err != nil {
+		b.log.Error("error while repricing basket", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// SetManualDiscount stores the staff's manual discount on the basket line and
+// reprices it. The role cap is checked by the caller.
+func (b *basketRepo) SetManualDiscount(ctx context.Context, request models.BasketDiscount) (err error) {
+
+	tx, err := b.pool.Begin(ctx)
+	if err != nil {
+		b.log.Error("error while starting basket discount transaction", logger.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if _, err = lockBasketSale(ctx, tx, request.ID); err != nil {
+		b.log.Error("error while locking basket sale", logger.Error(err))
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `update basket set
+	 manual_discount_percent = $1,
+	 manual_discount_staff_id = nullif($2, ''),
+	 updated_at = $3
+	 where deleted_at is null and id = $4`,
+		request.Percent, request.StaffID, time.Now(), request.ID); err != nil {
+		b.log.Error("error while updating basket discount", logger.Error(err))
+		return err
+	}
+
+	if err = repriceBasket(ctx, tx, request.ID); err != nil {
+		b.log.Error("error while repricing basket", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// lockOpenSale locks the sale for a basket change and checks that its basket
+// may still change, moving a draft sale to open once its basket is touched.
+func lockOpenSale(ctx context.Context, tx pgx.Tx, saleID string) (models.Sale, error) {
+
+	sale, err := selectSaleForUpdate(ctx, tx, saleID)
+	if err != nil {
+		return models.Sale{}, err
+	}
+
+	if err = salestatus.CanEdit(sale.Status); err != nil {
+		return models.Sale{}, err
+	}
+
+	if sale.Status == salestatus.Draft {
+		if _, err = tx.Exec(ctx, `update sale set status = $1, updated_at = $2 where id = $3`,
+			salestatus.Open, time.Now(), sale.ID); err != nil {
+			return models.Sale{}, err
+		}
+
+		sale.Status = salestatus.Open
+	}
+
+	return sale, nil
+}
+
+// lockBasketSale locks the sale of the basket line with lockOpenSale. The sale
+// is locked before the line, in the order CompleteSale takes them.
+func lockBasketSale(ctx context.Context, tx pgx.Tx, basketID string) (models.Sale, error) {
+
+	var saleID string
+	if err := tx.QueryRow(ctx, `select sale_id from basket where deleted_at is null and id = $1`, basketID).Scan(&saleID); err != nil {
+		return models.Sale{}, err
+	}
+
+	return lockOpenSale(ctx, tx, saleID)
+}
+
+// checkBasketStock fails with ErrNotEnoughProduct when the branch holds less
+// than quantity of the product.
+func checkBasketStock(ctx context.Context, tx pgx.Tx, branchID, productID string, quantity int) error {
+
+	var count int
+	if err := tx.QueryRow(ctx, `select coalesce((select count from storage
+	 where deleted_at is null and branch_id = $1 and product_id = $2), 0)`, branchID, productID).Scan(&count); err != nil {
+		return err
+	}
+
+	if count < quantity {
+		return storage.ErrNotEnoughProduct
+	}
+
+	return nil
+}
+
+func repriceBasket(ctx context.Context, tx pgx.Tx, id string) error {
+
+	var (
+		basket        = models.Basket{}
+		unitPrice     float64
+		branchID      string
+		categoryID    string
+		manualStaffID string
+	)
+
+	now := time.Now()
+
+	query := `select b.id, b.product_id, b.quantity, b.manual_discount_percent, coalesce(b.manual_discount_staff_id, ''),
+	 ` + branchPrice("p.id", "s.branch_id", "$2") + `, coalesce(p.category_id::text, ''), s.branch_id
+	 from basket b
+	 join product p on p.id = b.product_id
+	 join sale s on s.id = b.sale_id
+	 where b.deleted_at is null and b.id = $1`
+
+	condition, args := branchCondition(ctx, "s.branch_id", []interface{}{id, now})
+
+	if err := tx.QueryRow(ctx, query+condition+` for update of b`, args...).Scan(
+		&basket.ID,
+		&basket.ProductID,
+		&basket.Quantity,
+		&basket.ManualDiscountPercent,
+		&manualStaffID,
+		&unitPrice,
+		&categoryID,
+		&branchID,
+	); err != nil {
+		return err
+	}
+
+	promotions, err := applicablePromotions(ctx, tx, branchID, basket.ProductID, categoryID, now)
+	if err != nil {
+		return err
+	}
+
+	best, promotionDiscount, found := promotion.Best(promotions, unitPrice, basket.Quantity)
+
+	basket.BasePrice = unitPrice * float64(basket.Quantity)
+	manualDiscount := (basket.BasePrice - promotionDiscount) * basket.ManualDiscountPercent / 100
+	basket.Discount = promotionDiscount + manualDiscount
+	basket.Price = basket.BasePrice - basket.Discount
+
+	if _, err = tx.Exec(ctx, `update basket set base_price = $1, discount = $2, price = $3, updated_at = $4 where id = $5`,
+		basket.BasePrice, basket.Discount, basket.Price, time.Now(), basket.ID); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `delete from basket_promotion where basket_id = $1`, basket.ID); err != nil {
+		return err
+	}
+
+	if found {
+		if _, err = tx.Exec(ctx, `insert into basket_promotion (id, basket_id, promotion_id, amount) values ($1, $2, $3, $4)`,
+			uuid.New(), basket.ID, best.ID, promotionDiscount); err != nil {
+			return err
+		}
+	}
+
+	if manualDiscount > 0 {
+		if _, err = tx.Exec(ctx, `insert into basket_promotion (id, basket_id, staff_id, amount) values ($1, $2, nullif($3, ''), $4)`,
+			uuid.New(), basket.ID, manualStaffID, manualDiscount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,383 @@
+package postgres
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type customerRepo struct {
+	pool *pgxpool.Pool
+	log  logger.ILogger
+}
+
+func NewCustomerRepo(pool *pgxpool.Pool, log logger.ILogger) storage.ICustomerRepo {
+	return &customerRepo{
+		pool: pool,
+		log:  log,
+	}
+}
+
+// Create registers the customer at the caller's branch. Customers are shared
+// by all branches, so reads are not limited to the branch of registration.
+func (c *customerRepo) Create(ctx context.Context, request models.CreateCustomer) (string, error) {
+
+	id := uuid.New()
+
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		c.log.Error("error while checking customer branch", logger.Error(err))
+		return "", err
+	}
+
+	if _, err = c.pool.Exec(ctx, `insert into customer (id, name, phone, branch_id) values ($1, $2, $3, nullif($4, '')::uuid)`,
+		id,
+		request.Name,
+		request.Phone,
+		branchID,
+	); err != nil {
+		c.log.Error("error while inserting customer", logger.Error(err))
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+func (c *customerRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Customer, error) {
+
+	var (
+		updatedAt = sql.NullTime{}
+		customer  = models.Customer{}
+	)
+
+	if err := c.pool.QueryRow(ctx, `select
+	id,
+	name,
+	phone,
+	coalesce(branch_id::text, ''),
+	points,
+	created_at,
+	updated_at
+	from customer where deleted_at is null and id = $1`, id.ID).Scan(
+		&customer.ID,
+		&customer.Name,
+		&customer.Phone,
+		&customer.BranchID,
+		&customer.Points,
+		&customer.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		c.log.Error("error while selecting customer", logger.Error(err))
+		return models.Customer{}, err
+	}
+
+	if updatedAt.Valid {
+		customer.UpdatedAt = updatedAt.Time
+	}
+
+	return customer, nil
+}
+
+func (c *customerRepo) GetList(ctx context.Context, request models.GetListRequest) (models.CustomersResponse, error) {
+
+	var (
+		updatedAt = sql.NullTime{}
+		customers = []models.Customer{}
+		count     = 0
+		offset    = (request.Page - 1) * request.Limit
+		condition string
+		args      []interface{}
+	)
+
+	if request.Search != "" {
+		args = append(args, request.Search)
+		condition = ` and (name ilike '%' || $1 || '%' or phone ilike '%' || $1 || '%')`
+	}
+
+	if err := c.pool.QueryRow(ctx, `select count(1) from customer where deleted_at is null`+condition, args...).Scan(&count); err != nil {
+		c.log.Error("error while selecting customer count", logger.Error(err))
+		return models.CustomersResponse{}, err
+	}
+
+	query := `select
+	id,
+	name,
+	phone,
+	coalesce(branch_id::text, ''),
+	points,
+	created_at,
+	updated_at
+	from customer where deleted_at is null` + condition + ` order by created_at desc`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := c.pool.Query(ctx, query, append(args, request.Limit, offset)...)
+	if err != nil {
+		c.log.Error("error while selecting customers", logger.Error(err))
+		return models.CustomersResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		customer := models.Customer{}
+		if err = rows.Scan(
+			&customer.ID,
+			&customer.Name,
+			&customer.Phone,
+			&customer.BranchID,
+			&customer.Points,
+			&customer.CreatedAt,
+			&updatedAt,
+		); err != nil {
+			c.log.Error("error while scanning customer", logger.Error(err))
+			return models.CustomersResponse{}, err
+		}
+
+		if updatedAt.Valid {
+			customer.UpdatedAt = updatedAt.Time
+		}
+
+		customers = append(customers, customer)
+	}
+
+	return models.CustomersResponse{
+		Customers: customers,
+		Count:     count,
+	}, nil
+}
+
+func (c *customerRepo) Update(ctx context.Context, request models.UpdateCustomer) (string, error) {
+
+	if _, err := c.pool.Exec(ctx, `update customer set name = $1, phone = $2, updated_at = $3
+	 where deleted_at is null and id = $4`,
+		request.Name,
+		request.Phone,
+		time.Now(),
+		request.ID,
+	); err != nil {
+		c.log.Error("error while updating customer", logger.Error(err))
+		return "", err
+	}
+
+	return request.ID, nil
+}
+
+func (c *customerRepo) Delete(ctx context.Context, id string) error {
+
+	if _, err := c.pool.Exec(ctx, `update customer set deleted_at = $1 where id = $2`, time.Now(), id); err != nil {
+		c.log.Error("error while deleting customer", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// History returns the customer's sales visible to the caller and their
+// loyalty ledger, newest first.
+func (c *customerRepo) History(ctx context.Context, request models.GetCustomerHistoryRequest) (models.CustomerHistory, error) {
+
+	var (
+		history = models.CustomerHistory{
+			Sales:   []models.Sale{},
+			Loyalty: []models.LoyaltyTransaction{},
+		}
+		offset = (request.Page - 1) * request.Limit
+		err    error
+	)
+
+	if history.Customer, err = c.Get(ctx, models.PrimaryKey{ID: request.CustomerID}); err != nil {
+		return models.CustomerHistory{}, err
+	}
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{request.CustomerID})
+
+	query := `select id, branch_id, shop_assistent_id, cashier_id, payment_type, price, status, client_name,
+	 coalesce(customer_id::text, ''), coalesce(receipt_number, 0), created_at
+	 from sale where deleted_at is null and customer_id = $1` + condition + ` order by created_at desc`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := c.pool.Query(ctx, query, append(args, request.Limit, offset)...)
+	if err != nil {
+		c.log.Error("error while selecting customer sales", logger.Error(err))
+		return models.CustomerHistory{}, err
+	}
+
+	for rows.Next() {
+		sale := models.Sale{}
+		if err = rows.Scan(
+			&sale.ID,
+			&sale.BranchID,
+			&sale.ShopAssistantID,
+			&sale.CashierID,
+			&sale.PaymentType,
+			&sale.Price,
+			&sale.Status,
+			&sale.ClientName,
+			&sale.CustomerID,
+			&sale.ReceiptNumber,
+			&sale.CreatedAt,
+		); err != nil {
+			rows.Close()
+			c.log.Error("error while scanning customer sale", logger.Error(err))
+			return models.CustomerHistory{}, err
+		}
+
+		history.Sales = append(history.Sales, sale)
+	}
+	rows.Close()
+
+	rows, err = c.pool.Query(ctx, `select id, customer_id, coalesce(sale_id::text, ''), transaction_type, points, created_at
+	 from loyalty_transaction where customer_id = $1
+	 order by created_at desc LIMIT $2 OFFSET $3`, request.CustomerID, request.Limit, offset)
+	if err != nil {
+		c.log.Error("error while selecting loyalty transactions", logger.Error(err))
+		return models.CustomerHistory{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		transaction := models.LoyaltyTransaction{}
+		if err = rows.Scan(
+			&transaction.ID,
+			&transaction.CustomerID,
+			&transaction.SaleID,
+			&transaction.TransactionType,
+			&transaction.Points,
+			&transaction.CreatedAt,
+		); err != nil {
+			c.log.Error("error while scanning loyalty transaction", logger.Error(err))
+			return models.CustomerHistory{}, err
+		}
+
+		history.Loyalty = append(history.Loyalty, transaction)
+	}
+
+	return history, nil
+}
+
+// settleLoyalty redeems the points tendered for the sale and credits the
+// customer with points earned on the rest of the payments. Ledger rows are
+// signed, so their sum is the customer's balance.
+func settleLoyalty(ctx context.Context, tx pgx.Tx, sale models.Sale, payments []models.SalePayment, earnPercent float64) error {
+
+	var redeemed, paid float64
+	for _, payment := range payments {
+		if payment.PaymentType == "points" {
+			redeemed += payment.Amount
+		} else {
+			paid += payment.Amount
+		}
+	}
+
+	if sale.CustomerID == "" {
+		if redeemed > 0 {
+			return storage.ErrNotEnoughPoints
+		}
+		return nil
+	}
+
+	var balance float64
+	if err := tx.QueryRow(ctx, `select points from customer where deleted_at is null and id = $1 for update`,
+		sale.CustomerID).Scan(&balance); err != nil {
+		return err
+	}
+
+	if redeemed > balance {
+		return storage.ErrNotEnoughPoints
+	}
+
+	earned := paid * earnPercent / 100
+
+	if redeemed > 0 {
+		if err := addLoyalty(ctx, tx, sale.CustomerID, sale.ID, "redeem", -redeemed); err != nil {
+			return err
+		}
+	}
+
+	if earned > 0 {
+		if err := addLoyalty(ctx, tx, sale.CustomerID, sale.ID, "earn", earned); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reverseLoyalty takes back the given share of the points earned on the sale
+// and gives back the same share of the points redeemed on it, returning the
+// redeemed points given back. With refundAll it settles whatever the sale
+// still adds to or takes from the customer's balance.
+func reverseLoyalty(ctx context.Context, tx pgx.Tx, saleID string, share float64, refundAll bool) (float64, error) {
+
+	var (
+		customerID string
+		earned     float64
+		redeemed   float64
+		net        float64
+	)
+
+	err := tx.QueryRow(ctx, `select customer_id,
+	 coalesce(sum(points) filter (where transaction_type = 'earn'), 0),
+	 coalesce(-sum(points) filter (where transaction_type = 'redeem'), 0),
+	 coalesce(sum(points), 0)
+	 from loyalty_transaction where sale_id = $1
+	 group by customer_id`, saleID).Scan(&customerID, &earned, &redeemed, &net)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	refunded := redeemed * share
+	points := refunded - earned*share
+	if refundAll {
+		points = -net
+	}
+
+	if points == 0 {
+		return refunded, nil
+	}
+
+	return refunded, addLoyalty(ctx, tx, customerID, saleID, "reverse", points)
+}
+
+// addLoyalty changes the customer's balance and records it in the ledger. A
+// reversal of points the customer already spent takes the balance to zero,
+// never below it.
+func addLoyalty(ctx context.Context, tx pgx.Tx, customerID, saleID, transactionType string, points float64) error {
+
+	var balance float64
+	if err := tx.QueryRow(ctx, `select points from customer where id = $1 for update`, customerID).Scan(&balance); err != nil {
+		return err
+	}
+
+	if balance+points < 0 {
+		if transactionType != "reverse" {
+			return storage.ErrNotEnoughPoints
+		}
+		points = -balance
+	}
+
+	if points == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, `update customer set points = points + $1, updated_at = $2 where id = $3`,
+		points, time.Now(), customerID); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(ctx, `insert into loyalty_transaction (id, customer_id, sale_id, transaction_type, points)
+	 values ($1, $2, $3, $4, $5)`, uuid.New(), customerID, saleID, transactionType, points)
+
+	return err
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -29,15 +30,23 @@ func (i *IncomeRepo) Create(ctx context.Context, income models.CreateIncome) (st
 
 	id := uuid.New()
 
+	branchID, err := scopedBranchID(ctx, income.BranchID)
+	if err != nil {
+		i.log.Error("error while checking income branch", logger.Error(err))
+		return "", err
+	}
+
 	query := `insert into income (
 		id,
 		branch_id,
+		supplier_id,
 		price
-	) values ($1, $2, $3)`
+	) values ($1, $2, nullif($3, '')::uuid, $4)`
 
-	_, err := i.pool.Exec(ctx, query,
+	_, err = i.pool.Exec(ctx, query,
 		id,
-		income.BranchID,
+		branchID,
+		income.SupplierID,
 		income.Price,
 	)
 	if err != nil {
@@ -57,17 +66,20 @@ func (i *IncomeRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Inco
 	query := `select
 	id,
 	branch_id,
+	coalesce(supplier_id::text, ''),
 	price,
 	created_at,
 	updated_at
-	from income where deleted_at is null and id = $1
-	`
+	from income where deleted_at is null and id = $1`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id.ID})
 
-	row := i.pool.QueryRow(ctx, query, id.ID)
+	row := i.pool.QueryRow(ctx, query+condition, args...)
 
 	err := row.Scan(
 		&income.ID,
 		&income.BranchID,
+		&income.SupplierID,
 		&income.Price,
 		&income.CreatedAt,
 		&updatedAt,
@@ -97,12 +109,14 @@ func (i *IncomeRepo) GetList(ctx context.Context, request models.GetListRequest)
 		search            = request.Search
 	)
 
-	countQuery = `select count(1) from income where deleted_at is null`
+	condition, args := branchCondition(ctx, "branch_id", nil)
+
+	countQuery = `select count(1) from income where deleted_at is null` + condition
 
 	if search != "" {
-		countQuery += fmt.Sprintf(` and branch_id ilike '%%%s%%' or price ilike '%%%s%%'`, search, search)
+		countQuery += fmt.Sprintf(` and (branch_id::text ilike '%%%s%%' or price::text ilike '%%%s%%')`, search, search)
 	}
-	if err := i.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+	if err := i.pool.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
 		i.log.Error("error while selecting count", logger.Error(err))
 		return models.IncomesResponse{}, err
 	}
@@ -110,17 +124,18 @@ func (i *IncomeRepo) GetList(ctx context.Context, request models.GetListRequest)
 	query = `select 
 	id,
 	branch_id,
+	coalesce(supplier_id::text, ''),
 	price,
 	created_at,
 	updated_at
-	from income where deleted_at is null
-	`
+	from income where deleted_at is null` + condition
+
 	if search != "" {
-		countQuery += fmt.Sprintf(` and branch_id ilike '%%%s%%' or price ilike '%%%s%%'`, search, search)
+		query += fmt.Sprintf(` and (branch_id::text ilike '%%%s%%' or price::text ilike '%%%s%%')`, search, search)
 	}
 
-	query += `LIMIT $1 OFFSET $2`
-	rows, err := i.pool.Query(ctx, query, request.Limit, offset)
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := i.pool.Query(ctx, query, append(args, request.Limit, offset)...)
 	if err != nil {
 		i.log.Error("error is while selecting income", logger.Error(err))
 		return models.IncomesResponse{}, err
@@ -131,6 +146,7 @@ func (i *IncomeRepo) GetList(ctx context.Context, request models.GetListRequest)
 		if err = rows.Scan(
 			&income.ID,
 			&income.BranchID,
+			&income.SupplierID,
 			&income.Price,
 			&income.CreatedAt,
 			&updatedAt,
@@ -155,25 +171,39 @@ func (i *IncomeRepo) GetList(ctx context.Context, request models.GetListRequest)
 
 func (i *IncomeRepo) Update(ctx context.Context, request models.UpdateIncome) (string, error) {
 
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		i.log.Error("error while checking income branch", logger.Error(err))
+		return "", err
+	}
+
 	query := `update income 
 	 set 
 	 branch_id = $1,
-	 price = $2,
-	 updated_at = $3
-	 where id = $4
-	 `
-
-	_, err := i.pool.Exec(ctx, query,
-		request.BranchID,
+	 supplier_id = nullif($2, '')::uuid,
+	 price = $3,
+	 updated_at = $4
+	 where deleted_at is null and id = $5`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{
+		branchID,
+		request.SupplierID,
 		request.Price,
 		time.Now(),
 		request.ID,
-	)
+	})
+
+	tag, err := i.pool.Exec(ctx, query+condition, args...)
 	if err != nil {
 		i.log.Error("error while updating income data...", logger.Error(err))
 		return "", err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
+
 	return request.ID, nil
 }
 
@@ -182,14 +212,20 @@ func (i *IncomeRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update income
 	 set deleted_at = $1
-	  where id = $2
-	`
+	  where deleted_at is null and id = $2`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{time.Now(), id})
 
-	_, err := i.pool.Exec(ctx, query, time.Now(), id)
+	tag, err := i.pool.Exec(ctx, query+condition, args...)
 	if err != nil {
 		i.log.Error("error while deleting income by id", logger.Error(err))
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+
 	return nil
 }
@@ -10,9 +10,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// incomeProductBranch is the branch an income line belongs to, the one of its
+// income.
+const incomeProductBranch = `(select i.branch_id from income i where i.id = income_products.income_id)`
+
 type IncomeProductRepo struct {
 	pool *pgxpool.Pool
 	log  logger.ILogger
@@ -25,37 +30,57 @@ func NewIncomeProductRepo(pool *pgxpool.Pool, log logger.ILogger) storage.IIncom
 	}
 }
 
-func (i *IncomeProductRepo) Create(ctx context.Context, incomeProduct models.CreateIncomeProduct) (string, error) {
-
-	id := uuid.New()
+// Create receives goods against an income in one transaction: it records the
+// income line, adds the count to the income branch's storage with a plus
+// storage transaction and recomputes the income price from its lines.
+func (i *IncomeProductRepo) Create(ctx context.Context, incomeProduct models.CreateIncomeProduct) (id string, err error) {
 
-	query := `insert into income_products (
-		id,
-		income_id,
-		product_id,
-		price,
-		count
-	) values ($1, $2, $3, $4, $5)`
+	if incomeProduct.ProductID == "" || incomeProduct.Count <= 0 || incomeProduct.Price < 0 {
+		return "", storage.ErrIncomeQuantity
+	}
 
-	_, err := i.pool.Exec(ctx, query,
-		id,
-		incomeProduct.IncomeID,
-		incomeProduct.ProductID,
-		incomeProduct.Price,
-		incomeProduct.Count,
-	)
+	tx, err := i.pool.Begin(ctx)
 	if err != nil {
-		i.log.Error("error while inserting income product", logger.Error(err))
+		i.log.Error("error while starting income product transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{incomeProduct.IncomeID})
+
+	var branchID string
+	if err = tx.QueryRow(ctx, `select branch_id from income where deleted_at is null and id = $1`+condition+` for update`,
+		args...).Scan(&branchID); err != nil {
+		i.log.Error("error while selecting income for update", logger.Error(err))
 		return "", err
 	}
 
-	return id.String(), nil
+	if id, err = receiveIncomeProduct(ctx, tx, branchID, incomeProduct.StaffID, incomeProduct); err != nil {
+		i.log.Error("error while receiving income product", logger.Error(err))
+		return "", err
+	}
 
+	if _, err = updateIncomePrice(ctx, tx, incomeProduct.IncomeID); err != nil {
+		i.log.Error("error while updating income price", logger.Error(err))
+		return "", err
+	}
+
+	return id, nil
 }
 
 func (i *IncomeProductRepo) Get(ctx context.Context, id models.PrimaryKey) (models.IncomeProduct, error) {
 
-	var updatedAt = sql.NullTime{}
+	var (
+		updatedAt = sql.NullTime{}
+		expiresAt = sql.NullTime{}
+	)
 
 	incomeProduct := models.IncomeProduct{}
 
@@ -65,12 +90,14 @@ func (i *IncomeProductRepo) Get(ctx context.Context, id models.PrimaryKey) (mode
 	product_id,
 	price,
 	count,
+	expires_at,
 	created_at,
 	updated_at
-	from income_products where deleted_at is null and id = $1
-	`
+	from income_products where deleted_at is null and id = $1`
+
+	condition, args := branchCondition(ctx, incomeProductBranch, []interface{}{id.ID})
 
-	row := i.pool.QueryRow(ctx, query, id.ID)
+	row := i.pool.QueryRow(ctx, query+condition, args...)
 
 	err := row.Scan(
 		&incomeProduct.ID,
@@ -78,6 +105,7 @@ func (i *IncomeProductRepo) Get(ctx context.Context, id models.PrimaryKey) (mode
 		&incomeProduct.ProductID,
 		&incomeProduct.Price,
 		&incomeProduct.Count,
+		&expiresAt,
 		&incomeProduct.CreatedAt,
 		&updatedAt,
 	)
@@ -87,6 +115,10 @@ func (i *IncomeProductRepo) Get(ctx context.Context, id models.PrimaryKey) (mode
 		return models.IncomeProduct{}, err
 	}
 
+	if expiresAt.Valid {
+		incomeProduct.ExpiresAt = &expiresAt.Time
+	}
+
 	if updatedAt.Valid {
 		incomeProduct.UpdatedAt = updatedAt.Time
 	}
@@ -98,6 +130,7 @@ func (i *IncomeProductRepo) GetList(ctx context.Context, request models.GetListR
 
 	var (
 		updatedAt         = sql.NullTime{}
+		expiresAt         = sql.NullTime{}
 		incomeProducts    = []models.IncomeProduct{}
 		count             = 0
 		query, countQuery string
@@ -106,12 +139,15 @@ func (i *IncomeProductRepo) GetList(ctx context.Context, request models.GetListR
 		search            = request.Search
 	)
 
-	countQuery = `select count(1) from income_products where deleted_at is null`
+	condition, args := branchCondition(ctx, incomeProductBranch, nil)
 
 	if search != "" {
-		countQuery += fmt.Sprintf(` and product_id ilike '%%%s%%' or price ilike '%%%s%%'`, search, search)
+		args = append(args, search)
+		condition += fmt.Sprintf(` and (product_id::text ilike '%%' || $%d || '%%' or price::text ilike '%%' || $%d || '%%')`, len(args), len(args))
 	}
-	if err := i.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from income_products where deleted_at is null` + condition
+	if err := i.pool.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
 		i.log.Error("error while selecting income products count", logger.Error(err))
 		return models.IncomeProductsResponse{}, err
 	}
@@ -122,20 +158,19 @@ func (i *IncomeProductRepo) GetList(ctx context.Context, request models.GetListR
 	product_id,
 	price,
 	count,
+	expires_at,
 	created_at,
 	updated_at
-	from income_products where deleted_at is null
-	`
-	if search != "" {
-		countQuery += fmt.Sprintf(` and product_id ilike '%%%s%%' or price ilike '%%%s%%'`, search, search)
-	}
+	from income_products where deleted_at is null` + condition
 
-	query += `LIMIT $1 OFFSET $2`
-	rows, err := i.pool.Query(ctx, query, request.Limit, offset)
+	args = append(args, request.Limit, offset)
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	rows, err := i.pool.Query(ctx, query, args...)
 	if err != nil {
 		i.log.Error("error is while selecting income products", logger.Error(err))
 		return models.IncomeProductsResponse{}, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		incomeProduct := models.IncomeProduct{}
@@ -145,6 +180,7 @@ func (i *IncomeProductRepo) GetList(ctx context.Context, request models.GetListR
 			&incomeProduct.ProductID,
 			&incomeProduct.Price,
 			&incomeProduct.Count,
+			&expiresAt,
 			&incomeProduct.CreatedAt,
 			&updatedAt,
 		); err != nil {
@@ -152,6 +188,11 @@ func (i *IncomeProductRepo) GetList(ctx context.Context, request models.GetListR
 			return models.IncomeProductsResponse{}, err
 		}
 
+		if expiresAt.Valid {
+			expires := expiresAt.Time
+			incomeProduct.ExpiresAt = &expires
+		}
+
 		if updatedAt.Valid {
 			incomeProduct.UpdatedAt = updatedAt.Time
 		}
@@ -166,47 +207,239 @@ func (i *IncomeProductRepo) GetList(ctx context.Context, request models.GetListR
 	}, nil
 }
 
-func (i *IncomeProductRepo) Update(ctx context.Context, request models.UpdateIncomeProduct) (string, error) {
+// Update corrects an income line in one transaction: the old line is taken
+// back off its branch storage and the corrected one received, so the storage,
+// its batches and average cost follow the correction. A correction of stock
+// that is already sold fails with ErrNotEnoughProduct.
+func (i *IncomeProductRepo) Update(ctx context.Context, request models.UpdateIncomeProduct) (id string, err error) {
+
+	if request.ProductID == "" || request.Count <= 0 || request.Price < 0 {
+		return "", storage.ErrIncomeQuantity
+	}
+
+	tx, err := i.pool.Begin(ctx)
+	if err != nil {
+		i.log.Error("error while starting income product transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	old, oldBranchID, err := lockIncomeProduct(ctx, tx, request.ID)
+	if err != nil {
+		i.log.Error("error while selecting income product for update", logger.Error(err))
+		return "", err
+	}
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{request.IncomeID})
+
+	var branchID string
+	if err = tx.QueryRow(ctx, `select branch_id from income where deleted_at is null and id = $1`+condition+` for update`,
+		args...).Scan(&branchID); err != nil {
+		i.log.Error("error while selecting income for update", logger.Error(err))
+		return "", err
+	}
+
+	if err = postIncomeProduct(ctx, tx, oldBranchID, request.StaffID, "minus", old); err != nil {
+		i.log.Error("error while taking back income product", logger.Error(err))
+		return "", err
+	}
 
-	query := `update income_products
-	 set 
+	if _, err = tx.Exec(ctx, `update income_products
+	 set
 	 income_id = $1,
 	 product_id = $2,
 	 price = $3,
 	 count = $4,
-	 updated_at = $5
-	 where id = $6
-	 `
-
-	_, err := i.pool.Exec(ctx, query,
+	 expires_at = $5,
+	 updated_at = $6
+	 where id = $7`,
 		request.IncomeID,
 		request.ProductID,
 		request.Price,
 		request.Count,
+		request.ExpiresAt,
 		time.Now(),
 		request.ID,
-	)
-	if err != nil {
+	); err != nil {
 		i.log.Error("error while updating income product data...", logger.Error(err))
 		return "", err
 	}
 
+	if err = postIncomeProduct(ctx, tx, branchID, request.StaffID, "plus", models.CreateIncomeProduct{
+		IncomeID:  request.IncomeID,
+		ProductID: request.ProductID,
+		Price:     request.Price,
+		Count:     request.Count,
+		ExpiresAt: request.ExpiresAt,
+	}); err != nil {
+		i.log.Error("error while receiving income product", logger.Error(err))
+		return "", err
+	}
+
+	for _, incomeID := range []string{old.IncomeID, request.IncomeID} {
+		if _, err = updateIncomePrice(ctx, tx, incomeID); err != nil {
+			i.log.Error("error while updating income price", logger.Error(err))
+			return "", err
+		}
+	}
+
 	return request.ID, nil
 }
 
-func (i *IncomeProductRepo) Delete(ctx context.Context, id string) error {
+// Delete drops an income line and takes its count back off the branch
+// storage. A line whose stock is already sold can't be dropped.
+func (i *IncomeProductRepo) Delete(ctx context.Context, request models.DeleteIncomeProduct) (err error) {
+
+	tx, err := i.pool.Begin(ctx)
+	if err != nil {
+		i.log.Error("error while starting income product transaction", logger.Error(err))
+		return err
+	}
 
-	query := `
-	update income_products
-	 set deleted_at = $1
-	  where id = $2
-	`
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
 
-	_, err := i.pool.Exec(ctx, query, time.Now(), id)
+	old, branchID, err := lockIncomeProduct(ctx, tx, request.ID)
 	if err != nil {
+		i.log.Error("error while selecting income product for delete", logger.Error(err))
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `update income_products set deleted_at = $1 where id = $2`, time.Now(), request.ID); err != nil {
 		i.log.Error("error while deleting income product by id", logger.Error(err))
 		return err
 	}
 
+	if err = postIncomeProduct(ctx, tx, branchID, request.StaffID, "minus", old); err != nil {
+		i.log.Error("error while taking back income product", logger.Error(err))
+		return err
+	}
+
+	if _, err = updateIncomePrice(ctx, tx, old.IncomeID); err != nil {
+		i.log.Error("error while updating income price", logger.Error(err))
+		return err
+	}
+
 	return nil
 }
+
+// lockIncomeProduct locks an income line of the caller's branch and returns
+// it with the branch of its income.
+func lockIncomeProduct(ctx context.Context, tx pgx.Tx, id string) (models.CreateIncomeProduct, string, error) {
+
+	var (
+		line      models.CreateIncomeProduct
+		branchID  string
+		expiresAt sql.NullTime
+	)
+
+	condition, args := branchCondition(ctx, "i.branch_id", []interface{}{id})
+
+	if err := tx.QueryRow(ctx, `select ip.income_id, ip.product_id, ip.price, ip.count, ip.expires_at, i.branch_id
+	 from income_products ip join income i on i.id = ip.income_id
+	 where ip.deleted_at is null and ip.id = $1`+condition+` for update of ip`, args...).Scan(
+		&line.IncomeID,
+		&line.ProductID,
+		&line.Price,
+		&line.Count,
+		&expiresAt,
+		&branchID,
+	); err != nil {
+		return models.CreateIncomeProduct{}, "", err
+	}
+
+	if expiresAt.Valid {
+		line.ExpiresAt = &expiresAt.Time
+	}
+
+	return line, branchID, nil
+}
+
+// receiveIncomeProduct records a delivered income line and puts its count on
+// the branch storage as a new batch with the line's expiry date and price,
+// folding the price into the branch average cost.
+func receiveIncomeProduct(ctx context.Context, tx pgx.Tx, branchID, staffID string, incomeProduct models.CreateIncomeProduct) (string, error) {
+
+	id := uuid.New().String()
+
+	if _, err := tx.Exec(ctx, `insert into income_products (
+		id,
+		income_id,
+		product_id,
+		price,
+		count,
+		expires_at
+	) values ($1, $2, $3, $4, $5, $6)`,
+		id,
+		incomeProduct.IncomeID,
+		incomeProduct.ProductID,
+		incomeProduct.Price,
+		incomeProduct.Count,
+		incomeProduct.ExpiresAt,
+	); err != nil {
+		return "", err
+	}
+
+	if err := postIncomeProduct(ctx, tx, branchID, staffID, "plus", incomeProduct); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// postIncomeProduct puts the count of an income line on the branch storage as
+// a new batch with the line's expiry date and price, folding the price into
+// the branch average cost. A minus takes a corrected line back off, from the
+// batches its income brought in first, and out of the average cost.
+func postIncomeProduct(ctx context.Context, tx pgx.Tx, branchID, staffID, transactionType string, incomeProduct models.CreateIncomeProduct) error {
+
+	movement := stockMovement{
+		branchID:        branchID,
+		staffID:         staffID,
+		productID:       incomeProduct.ProductID,
+		transactionType: transactionType,
+		quantity:        incomeProduct.Count,
+		price:           incomeProduct.Price * float64(incomeProduct.Count),
+		sourceType:      sourceIncome,
+		sourceID:        incomeProduct.IncomeID,
+		expiresAt:       incomeProduct.ExpiresAt,
+	}
+
+	quantity := incomeProduct.Count
+	if transactionType == "minus" {
+		movement.reason = "income correction"
+		movement.originType = sourceIncome
+		movement.originID = incomeProduct.IncomeID
+		quantity = -quantity
+	}
+
+	if _, err := moveStock(ctx, tx, movement); err != nil {
+		return err
+	}
+
+	return updateAverageCost(ctx, tx, branchID, incomeProduct.ProductID, quantity, incomeProduct.Price)
+}
+
+// updateIncomePrice sets the income price to the value of its lines.
+func updateIncomePrice(ctx context.Context, tx pgx.Tx, incomeID string) (float64, error) {
+
+	var price float64
+
+	err := tx.QueryRow(ctx, `update income set price = (
+		select coalesce(sum(price * count), 0) from income_products where deleted_at is null and income_id = $1
+	), updated_at = $2 where id = $1 returning price`, incomeID, time.Now()).Scan(&price)
+
+	return price, err
+}
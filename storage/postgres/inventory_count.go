@@ -0,0 +1,524 @@
+package postgres
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// categoryTree selects the category given as $n and all its subcategories.
+const categoryTree = `with recursive tree as (
+		select id from category where id = nullif($%d, '')::uuid
+		union
+		select c.id from category c join tree t on c.parent_id = t.id
+	)`
+
+type inventoryCountRepo struct {
+	pool *pgxpool.Pool
+	log  logger.ILogger
+}
+
+func NewInventoryCountRepo(pool *pgxpool.Pool, log logger.ILogger) storage.IInventoryCountRepo {
+	return &inventoryCountRepo{
+		pool: pool,
+		log:  log,
+	}
+}
+
+// Create opens a count for the branch and takes the storage count of every
+// product in scope as the expected quantity. A category limits the count to
+// the products of the category and its subcategories.
+func (i *inventoryCountRepo) Create(ctx context.Context, request models.CreateInventoryCount) (id string, err error) {
+
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := i.pool.Begin(ctx)
+	if err != nil {
+		i.log.Error("error while starting inventory count transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	id = uuid.New().String()
+
+	if _, err = tx.Exec(ctx, `insert into inventory_count (id, branch_id, category_id, opened_by)
+	 values ($1, $2, nullif($3, '')::uuid, nullif($4, ''))`,
+		id, branchID, request.CategoryID, request.StaffID); err != nil {
+		i.log.Error("error while inserting inventory count", logger.Error(err))
+		return "", err
+	}
+
+	rows, err := tx.Query(ctx, fmt.Sprintf(categoryTree, 2)+`
+	select s.product_id, sum(s.count) from storage s
+	 join product p on p.id = s.product_id and p.deleted_at is null
+	 where s.deleted_at is null and s.branch_id = $1
+	 and ($2 = '' or p.category_id in (select id from tree))
+	 group by s.product_id`, branchID, request.CategoryID)
+	if err != nil {
+		i.log.Error("error while selecting storage for inventory count", logger.Error(err))
+		return "", err
+	}
+
+	expected := make(map[string]int)
+	for rows.Next() {
+		var (
+			productID string
+			count     int
+		)
+		if err = rows.Scan(&productID, &count); err != nil {
+			rows.Close()
+			i.log.Error("error while scanning storage for inventory count", logger.Error(err))
+			return "", err
+		}
+		expected[productID] = count
+	}
+	rows.Close()
+
+	for productID, count := range expected {
+		if _, err = tx.Exec(ctx, `insert into inventory_count_product (id, inventory_count_id, product_id, expected_count)
+		 values ($1, $2, $3, $4)`, uuid.New(), id, productID, count); err != nil {
+			i.log.Error("error while inserting inventory count product", logger.Error(err))
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+func (i *inventoryCountRepo) Get(ctx context.Context, id models.PrimaryKey) (models.InventoryCount, error) {
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id.ID})
+
+	count, err := scanInventoryCount(i.pool.QueryRow(ctx, `select`+inventoryCountColumns+`
+	 from inventory_count where deleted_at is null and id = $1`+condition, args...))
+	if err != nil {
+		i.log.Error("error while selecting inventory count", logger.Error(err))
+		return models.InventoryCount{}, err
+	}
+
+	rows, err := i.pool.Query(ctx, `select id, inventory_count_id, product_id, expected_count, counted_count
+	 from inventory_count_product where inventory_count_id = $1 order by created_at, product_id`, count.ID)
+	if err != nil {
+		i.log.Error("error while selecting inventory count products", logger.Error(err))
+		return models.InventoryCount{}, err
+	}
+	defer rows.Close()
+
+	count.Products = []models.InventoryCountProduct{}
+	for rows.Next() {
+		var (
+			product = models.InventoryCountProduct{}
+			counted = sql.NullInt64{}
+		)
+
+		if err = rows.Scan(
+			&product.ID,
+			&product.InventoryCountID,
+			&product.ProductID,
+			&product.ExpectedCount,
+			&counted,
+		); err != nil {
+			i.log.Error("error while scanning inventory count product", logger.Error(err))
+			return models.InventoryCount{}, err
+		}
+
+		if counted.Valid {
+			countedCount := int(counted.Int64)
+			product.CountedCount = &countedCount
+			product.Variance = countedCount - product.ExpectedCount
+		}
+
+		count.Products = append(count.Products, product)
+	}
+
+	return count, nil
+}
+
+func (i *inventoryCountRepo) GetList(ctx context.Context, request models.GetInventoryCountsListRequest) (models.InventoryCountsResponse, error) {
+
+	var (
+		counts    = []models.InventoryCount{}
+		total     = 0
+		offset    = (request.Page - 1) * request.Limit
+		condition string
+		args      []interface{}
+	)
+
+	if request.Status != "" {
+		args = append(args, request.Status)
+		condition += fmt.Sprintf(` and status = $%d`, len(args))
+	}
+
+	branch, args := branchCondition(ctx, "branch_id", args)
+	condition += branch
+
+	if err := i.pool.QueryRow(ctx, `select count(1) from inventory_count where deleted_at is null`+condition, args...).Scan(&total); err != nil {
+		i.log.Error("error while selecting inventory count count", logger.Error(err))
+		return models.InventoryCountsResponse{}, err
+	}
+
+	query := `select` + inventoryCountColumns + ` from inventory_count where deleted_at is null` + condition + ` order by created_at desc`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := i.pool.Query(ctx, query, append(args, request.Limit, offset)...)
+	if err != nil {
+		i.log.Error("error while selecting inventory counts", logger.Error(err))
+		return models.InventoryCountsResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		count, err := scanInventoryCount(rows)
+		if err != nil {
+			i.log.Error("error while scanning inventory count", logger.Error(err))
+			return models.InventoryCountsResponse{}, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	return models.InventoryCountsResponse{
+		InventoryCounts: counts,
+		Count:           total,
+	}, nil
+}
+
+// Delete drops a count that was not posted.
+func (i *inventoryCountRepo) Delete(ctx context.Context, id string) error {
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{time.Now(), id})
+
+	tag, err := i.pool.Exec(ctx, `update inventory_count set deleted_at = $1
+	 where deleted_at is null and status = 'open' and id = $2`+condition, args...)
+	if err != nil {
+		i.log.Error("error while deleting inventory count", logger.Error(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return storage.ErrCountClosed
+	}
+
+	return nil
+}
+
+// Submit records counted quantities. The storage count at the time of
+// counting becomes the expected quantity, so goods sold or received while the
+// count is open are not taken for a variance. A product counted again
+// replaces its previous quantity. A product in scope that had no storage at
+// the start of the count is added as well.
+func (i *inventoryCountRepo) Submit(ctx context.Context, request models.SubmitInventoryCount) (err error) {
+
+	tx, err := i.pool.Begin(ctx)
+	if err != nil {
+		i.log.Error("error while starting inventory count transaction", logger.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	categoryID, err := lockOpenInventoryCount(ctx, tx, request.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(request.Products) == 0 {
+		err = storage.ErrCountQuantity
+		return err
+	}
+
+	for _, product := range request.Products {
+		if product.CountedCount < 0 || (product.ProductID == "" && product.Barcode == "") {
+			err = storage.ErrCountQuantity
+			return err
+		}
+
+		productID := product.ProductID
+		if productID == "" {
+			err = tx.QueryRow(ctx, `select id from product where deleted_at is null and barcode = $1`,
+				product.Barcode).Scan(&productID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = storage.ErrProductNotFound
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		tag, err := tx.Exec(ctx, fmt.Sprintf(categoryTree, 4)+`
+		insert into inventory_count_product (id, inventory_count_id, product_id, expected_count, counted_count)
+		select $1, c.id, p.id, coalesce((select s.count from storage s
+		 where s.deleted_at is null and s.branch_id = c.branch_id and s.product_id = p.id), 0), $5
+		 from product p join inventory_count c on c.id = $2
+		 where p.deleted_at is null and p.id = $3 and ($4 = '' or p.category_id in (select id from tree))
+		on conflict (inventory_count_id, product_id)
+		 do update set expected_count = excluded.expected_count, counted_count = excluded.counted_count, updated_at = now()`,
+			uuid.New(), request.ID, productID, categoryID, product.CountedCount)
+		if err != nil {
+			i.log.Error("error while saving counted product", logger.Error(err))
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return storage.ErrCountQuantity
+		}
+	}
+
+	return nil
+}
+
+// Post closes the count and books every variance between the counted and the
+// expected quantity on the branch storage as a plus or minus storage
+// transaction carrying the reason, valued at the last income price.
+// Products that were not counted are left as they are. A shortage larger than
+// what is left in storage takes the storage count to zero.
+func (i *inventoryCountRepo) Post(ctx context.Context, request models.PostInventoryCount) (err error) {
+
+	tx, err := i.pool.Begin(ctx)
+	if err != nil {
+		i.log.Error("error while starting inventory count transaction", logger.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if _, err = lockOpenInventoryCount(ctx, tx, request.ID); err != nil {
+		return err
+	}
+
+	reason := request.Reason
+	if reason == "" {
+		reason = "inventory count"
+	}
+
+	var branchID string
+	if err = tx.QueryRow(ctx, `select branch_id from inventory_count where id = $1`, request.ID).Scan(&branchID); err != nil {
+		i.log.Error("error while selecting inventory count branch", logger.Error(err))
+		return err
+	}
+
+	type variance struct {
+		productID string
+		quantity  int
+		price     float64
+	}
+
+	rows, err := tx.Query(ctx, `select product_id, counted_count - expected_count, `+lastIncomePrice("product_id")+`
+	 from inventory_count_product
+	 where inventory_count_id = $1 and counted_count is not null and counted_count <> expected_count`, request.ID)
+	if err != nil {
+		i.log.Error("error while selecting inventory variances", logger.Error(err))
+		return err
+	}
+
+	variances := []variance{}
+	for rows.Next() {
+		v := variance{}
+		if err = rows.Scan(&v.productID, &v.quantity, &v.price); err != nil {
+			rows.Close()
+			i.log.Error("error while scanning inventory variance", logger.Error(err))
+			return err
+		}
+		variances = append(variances, v)
+	}
+	rows.Close()
+
+	for _, v := range variances {
+		transactionType, quantity := "plus", v.quantity
+		if quantity < 0 {
+			transactionType, quantity = "minus", -quantity
+
+			var left int
+			err = tx.QueryRow(ctx, `select count from storage
+			 where deleted_at is null and branch_id = $1 and product_id = $2 for update`,
+				branchID, v.productID).Scan(&left)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				i.log.Error("error while selecting storage count", logger.Error(err))
+				return err
+			}
+
+			if quantity > left {
+				quantity = left
+			}
+
+			if quantity == 0 {
+				continue
+			}
+		}
+
+		if _, err = moveStock(ctx, tx, stockMovement{
+			branchID:        branchID,
+			staffID:         request.StaffID,
+			productID:       v.productID,
+			transactionType: transactionType,
+			quantity:        quantity,
+			price:           v.price * float64(quantity),
+			sourceType:      sourceInventoryCount,
+			sourceID:        request.ID,
+			reason:          reason,
+		}); err != nil {
+			i.log.Error("error while adjusting storage count", logger.Error(err))
+			return err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `update inventory_count set status = 'posted', reason = $1, posted_by = nullif($2, ''),
+	 posted_at = $3, updated_at = $3 where id = $4`, reason, request.StaffID, time.Now(), request.ID); err != nil {
+		i.log.Error("error while updating inventory count status", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Variance reports the counted products whose quantity differs from the
+// expected one. Shrinkage is the value of the missing goods.
+func (i *inventoryCountRepo) Variance(ctx context.Context, id string) (models.InventoryVariance, error) {
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id})
+
+	report := models.InventoryVariance{
+		InventoryCountID: id,
+		Products:         []models.InventoryVarianceLine{},
+	}
+
+	if err := i.pool.QueryRow(ctx, `select count(1) filter (where l.counted_count is null)
+	 from inventory_count c left join inventory_count_product l on l.inventory_count_id = c.id
+	 where c.deleted_at is null and c.id = $1`+condition+` group by c.id`, args...).Scan(&report.Uncounted); err != nil {
+		i.log.Error("error while selecting inventory count", logger.Error(err))
+		return models.InventoryVariance{}, err
+	}
+
+	rows, err := i.pool.Query(ctx, `select l.product_id, p.name, l.expected_count, l.counted_count,
+	 l.counted_count - l.expected_count, `+lastIncomePrice("l.product_id")+`
+	 from inventory_count_product l join product p on p.id = l.product_id
+	 where l.inventory_count_id = $1 and l.counted_count is not null and l.counted_count <> l.expected_count
+	 order by p.name`, id)
+	if err != nil {
+		i.log.Error("error while selecting inventory variances", logger.Error(err))
+		return models.InventoryVariance{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		line := models.InventoryVarianceLine{}
+		if err = rows.Scan(
+			&line.ProductID,
+			&line.ProductName,
+			&line.ExpectedCount,
+			&line.CountedCount,
+			&line.Variance,
+			&line.LastIncomePrice,
+		); err != nil {
+			i.log.Error("error while scanning inventory variance", logger.Error(err))
+			return models.InventoryVariance{}, err
+		}
+
+		line.VarianceValue = float64(line.Variance) * line.LastIncomePrice
+		if line.VarianceValue < 0 {
+			report.ShrinkageValue -= line.VarianceValue
+		} else {
+			report.SurplusValue += line.VarianceValue
+		}
+
+		report.Products = append(report.Products, line)
+	}
+
+	report.NetValue = report.SurplusValue - report.ShrinkageValue
+
+	return report, nil
+}
+
+const inventoryCountColumns = ` id, branch_id, coalesce(category_id::text, ''), status, coalesce(reason, ''),
+	 coalesce(opened_by, ''), coalesce(posted_by, ''), posted_at, created_at, updated_at`
+
+func scanInventoryCount(row pgx.Row) (models.InventoryCount, error) {
+
+	var (
+		postedAt  = sql.NullTime{}
+		updatedAt = sql.NullTime{}
+		count     = models.InventoryCount{}
+	)
+
+	if err := row.Scan(
+		&count.ID,
+		&count.BranchID,
+		&count.CategoryID,
+		&count.Status,
+		&count.Reason,
+		&count.OpenedBy,
+		&count.PostedBy,
+		&postedAt,
+		&count.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		return models.InventoryCount{}, err
+	}
+
+	if postedAt.Valid {
+		count.PostedAt = &postedAt.Time
+	}
+
+	if updatedAt.Valid {
+		count.UpdatedAt = updatedAt.Time
+	}
+
+	return count, nil
+}
+
+// lockOpenInventoryCount locks a count of the caller's branch that is still
+// open and returns its category.
+func lockOpenInventoryCount(ctx context.Context, tx pgx.Tx, id string) (string, error) {
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id})
+
+	var categoryID, status string
+	if err := tx.QueryRow(ctx, `select coalesce(category_id::text, ''), status from inventory_count
+	 where deleted_at is null and id = $1`+condition+` for update`, args...).Scan(&categoryID, &status); err != nil {
+		return "", err
+	}
+
+	if status != "open" {
+		return "", storage.ErrCountClosed
+	}
+
+	return categoryID, nil
+}
+
+// lastIncomePrice selects the unit price the product in column was last
+// received at, zero when it was never received.
+func lastIncomePrice(column string) string {
+	return fmt.Sprintf(`coalesce((select ip.price from income_products ip
+	 where ip.deleted_at is null and ip.product_id = %s order by ip.created_at desc limit 1), 0)`, column)
+}
@@ -13,10 +13,20 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/database/postgres" //postgres is used for database
 	_ "github.com/golang-migrate/migrate/v4/source/file"       //file is needed for migration url
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/lib/pq"
 )
 
+// querier is satisfied by both the pool and a transaction, for reads shared
+// between plain requests and transactional ones.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
 type Store struct {
 	pool *pgxpool.Pool
 	log  logger.ILogger
@@ -131,3 +141,39 @@ func (s Store) Income() storage.IIncomeRepo {
 func (s Store) IncomeProduct() storage.IIncomeProductRepo {
 	return NewIncomeProductRepo(s.pool, s.log)
 }
+
+func (s Store) SaleReturn() storage.ISaleReturnRepo {
+	return NewSaleReturnRepo(s.pool, s.log)
+}
+
+func (s Store) Promotion() storage.IPromotionRepo {
+	return NewPromotionRepo(s.pool, s.log)
+}
+
+func (s Store) Customer() storage.ICustomerRepo {
+	return NewCustomerRepo(s.pool, s.log)
+}
+
+func (s Store) Supplier() storage.ISupplierRepo {
+	return NewSupplierRepo(s.pool, s.log)
+}
+
+func (s Store) PurchaseOrder() storage.IPurchaseOrderRepo {
+	return NewPurchaseOrderRepo(s.pool, s.log)
+}
+
+func (s Store) Transfer() storage.ITransferRepo {
+	return NewTransferRepo(s.pool, s.log)
+}
+
+func (s Store) InventoryCount() storage.IInventoryCountRepo {
+	return NewInventoryCountRepo(s.pool, s.log)
+}
+
+func (s Store) Report() storage.IReportRepo {
+	return NewReportRepo(s.pool, s.log)
+}
+
+func (s Store) PriceChange() storage.IPriceChangeRepo {
+	return NewPriceChangeRepo(s.pool, s.log)
+}
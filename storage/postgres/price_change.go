@@ -0,0 +1,552 @@
+package postgres
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/pkg/pricechange"
+	"bazaar/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type priceChangeRepo struct {
+	pool *pgxpool.Pool
+	log  logger.ILogger
+}
+
+func NewPriceChangeRepo(pool *pgxpool.Pool, log logger.ILogger) storage.IPriceChangeRepo {
+	return &priceChangeRepo{
+		pool: pool,
+		log:  log,
+	}
+}
+
+const priceChangeColumns = `
+	id,
+	coalesce(branch_id::text, ''),
+	coalesce(category_id::text, ''),
+	change_type,
+	value,
+	round_to,
+	rounding,
+	effective_at,
+	status,
+	coalesce(created_by, ''),
+	coalesce(approved_by, ''),
+	approved_at,
+	applied_at,
+	coalesce(failure, ''),
+	created_at,
+	updated_at`
+
+// Create drafts a price change. A caller bound to a branch changes the prices
+// of that branch only.
+func (p *priceChangeRepo) Create(ctx context.Context, request models.CreatePriceChange) (id string, err error) {
+
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		return "", err
+	}
+
+	if request.Rounding == "" {
+		request.Rounding = pricechange.Nearest
+	}
+
+	rule := pricechange.Rule{
+		ChangeType: request.ChangeType,
+		Value:      request.Value,
+		RoundTo:    request.RoundTo,
+		Rounding:   request.Rounding,
+	}
+
+	if !rule.IsValid() || (request.CategoryID == "") == (len(request.ProductIDs) == 0) {
+		return "", storage.ErrPriceChange
+	}
+
+	for _, productID := range request.ProductIDs {
+		if productID == "" {
+			return "", storage.ErrPriceChange
+		}
+	}
+
+	effectiveAt := time.Now()
+	if request.EffectiveAt != nil {
+		effectiveAt = *request.EffectiveAt
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.log.Error("error while starting price change transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	id = uuid.New().String()
+
+	if _, err = tx.Exec(ctx, `insert into price_change (
+		id,
+		branch_id,
+		category_id,
+		change_type,
+		value,
+		round_to,
+		rounding,
+		effective_at,
+		created_by
+	) values ($1, nullif($2, '')::uuid, nullif($3, '')::uuid, $4, $5, $6, $7, $8, nullif($9, ''))`,
+		id,
+		branchID,
+		request.CategoryID,
+		request.ChangeType,
+		request.Value,
+		request.RoundTo,
+		request.Rounding,
+		effectiveAt,
+		request.StaffID,
+	); err != nil {
+		p.log.Error("error while inserting price change", logger.Error(err))
+		return "", err
+	}
+
+	for _, productID := range request.ProductIDs {
+		if _, err = tx.Exec(ctx, `insert into price_change_product (id, price_change_id, product_id) values ($1, $2, $3)
+		 on conflict (price_change_id, product_id) do nothing`, uuid.New(), id, productID); err != nil {
+			p.log.Error("error while inserting price change product", logger.Error(err))
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+func (p *priceChangeRepo) Get(ctx context.Context, id models.PrimaryKey) (models.PriceChange, error) {
+
+	condition, args := priceBranchCondition(ctx, []interface{}{id.ID})
+
+	priceChange, err := scanPriceChange(p.pool.QueryRow(ctx, `select`+priceChangeColumns+`
+	 from price_change where deleted_at is null and id = $1`+condition, args...))
+	if err != nil {
+		p.log.Error("error while selecting price change", logger.Error(err))
+		return models.PriceChange{}, err
+	}
+
+	if priceChange.Products, err = priceChangeProducts(ctx, p.pool, priceChange.ID); err != nil {
+		p.log.Error("error while selecting price change products", logger.Error(err))
+		return models.PriceChange{}, err
+	}
+
+	return priceChange, nil
+}
+
+func (p *priceChangeRepo) GetList(ctx context.Context, request models.GetPriceChangesListRequest) (models.PriceChangesResponse, error) {
+
+	var (
+		priceChanges = []models.PriceChange{}
+		count        = 0
+		offset       = (request.Page - 1) * request.Limit
+		condition    string
+		args         []interface{}
+	)
+
+	if request.Status != "" {
+		args = append(args, request.Status)
+		condition += fmt.Sprintf(` and status = $%d`, len(args))
+	}
+
+	branch, args := priceBranchCondition(ctx, args)
+	condition += branch
+
+	if err := p.pool.QueryRow(ctx, `select count(1) from price_change where deleted_at is null`+condition, args...).Scan(&count); err != nil {
+		p.log.Error("error while selecting price change count", logger.Error(err))
+		return models.PriceChangesResponse{}, err
+	}
+
+	query := `select` + priceChangeColumns + ` from price_change where deleted_at is null` + condition + ` order by effective_at desc`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := p.pool.Query(ctx, query, append(args, request.Limit, offset)...)
+	if err != nil {
+		p.log.Error("error while selecting price changes", logger.Error(err))
+		return models.PriceChangesResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		priceChange, err := scanPriceChange(rows)
+		if err != nil {
+			p.log.Error("error while scanning price change", logger.Error(err))
+			return models.PriceChangesResponse{}, err
+		}
+
+		priceChanges = append(priceChanges, priceChange)
+	}
+
+	return models.PriceChangesResponse{
+		PriceChanges: priceChanges,
+		Count:        count,
+	}, nil
+}
+
+// Delete drops a price change that was not applied yet.
+func (p *priceChangeRepo) Delete(ctx context.Context, id string) error {
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{time.Now(), id})
+
+	tag, err := p.pool.Exec(ctx, `update price_change set deleted_at = $1
+	 where deleted_at is null and status <> 'applied' and id = $2`+condition, args...)
+	if err != nil {
+		p.log.Error("error while deleting price change", logger.Error(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return storage.ErrPriceChangeStatus
+	}
+
+	return nil
+}
+
+// Approve records who approved a draft price change and hands it to the
+// scheduler. Only admins approve the changes of every branch, and nobody
+// approves their own.
+func (p *priceChangeRepo) Approve(ctx context.Context, request models.ApprovePriceChange) error {
+
+	now := time.Now()
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{pricechange.Approved, request.StaffID, now, request.ID, pricechange.Draft})
+
+	tag, err := p.pool.Exec(ctx, `update price_change set status = $1, approved_by = nullif($2, ''), approved_at = $3, updated_at = $3
+	 where deleted_at is null and id = $4 and status = $5
+	 and created_by is distinct from nullif($2, '')`+condition, args...)
+	if err != nil {
+		p.log.Error("error while approving price change", logger.Error(err))
+		return err
+	}
+
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	condition, args = branchCondition(ctx, "branch_id", []interface{}{request.ID, pricechange.Draft, request.StaffID})
+
+	var own bool
+	err = p.pool.QueryRow(ctx, `select coalesce(created_by, '') = $3 from price_change
+	 where deleted_at is null and id = $1 and status = $2`+condition, args...).Scan(&own)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		p.log.Error("error while selecting price change author", logger.Error(err))
+		return err
+	}
+
+	if own {
+		return storage.ErrSelfApproval
+	}
+
+	return storage.ErrPriceChangeStatus
+}
+
+// ApplyDue applies the approved price changes effective by at, each in its
+// own transaction, oldest first, and returns the ids of the ones applied.
+// A change another scheduler is applying is skipped. A change that can't be
+// applied is marked failed with the reason, and the rest are still applied.
+func (p *priceChangeRepo) ApplyDue(ctx context.Context, at time.Time) ([]string, error) {
+
+	rows, err := p.pool.Query(ctx, `select id from price_change
+	 where deleted_at is null and status = $1 and effective_at <= $2
+	 order by effective_at`, pricechange.Approved, at)
+	if err != nil {
+		p.log.Error("error while selecting due price changes", logger.Error(err))
+		return nil, err
+	}
+
+	due := []string{}
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			p.log.Error("error while scanning due price change", logger.Error(err))
+			return nil, err
+		}
+		due = append(due, id)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		p.log.Error("error while reading due price changes", logger.Error(err))
+		return nil, err
+	}
+
+	var (
+		applied = []string{}
+		failErr error
+	)
+
+	for _, id := range due {
+		ok, err := p.apply(ctx, id)
+		if err != nil {
+			p.log.Error("error while applying price change", logger.String("price_change_id", id), logger.Error(err))
+
+			if _, err = p.pool.Exec(ctx, `update price_change set status = $1, failure = $2, updated_at = $3
+			 where id = $4 and status = $5`, pricechange.Failed, err.Error(), time.Now(), id, pricechange.Approved); err != nil {
+				p.log.Error("error while marking price change failed", logger.String("price_change_id", id), logger.Error(err))
+				failErr = err
+			}
+			continue
+		}
+
+		if ok {
+			applied = append(applied, id)
+		}
+	}
+
+	return applied, failErr
+}
+
+// apply reprices the products of an approved price change. Without a branch
+// it sets the product prices and reprices the branch prices in effect at the
+// same time, with one it adds branch prices, in both cases effective at the
+// change's effective time and recorded in the price history as set by the
+// approver.
+func (p *priceChangeRepo) apply(ctx context.Context, id string) (ok bool, err error) {
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	priceChange, err := scanPriceChange(tx.QueryRow(ctx, `select`+priceChangeColumns+`
+	 from price_change where deleted_at is null and id = $1 and status = $2 for update skip locked`, id, pricechange.Approved))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	rule := pricechange.Rule{
+		ChangeType: priceChange.ChangeType,
+		Value:      priceChange.Value,
+		RoundTo:    priceChange.RoundTo,
+		Rounding:   priceChange.Rounding,
+	}
+
+	var rows pgx.Rows
+	if priceChange.CategoryID != "" {
+		rows, err = tx.Query(ctx, fmt.Sprintf(categoryTree, 1)+`
+		select p.id from product p where p.deleted_at is null and p.category_id in (select id from tree)`, priceChange.CategoryID)
+	} else {
+		rows, err = tx.Query(ctx, `select cp.product_id from price_change_product cp
+		 join product p on p.id = cp.product_id and p.deleted_at is null
+		 where cp.price_change_id = $1`, priceChange.ID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	productIDs := []string{}
+	for rows.Next() {
+		var productID string
+		if err = rows.Scan(&productID); err != nil {
+			rows.Close()
+			return false, err
+		}
+		productIDs = append(productIDs, productID)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+
+	for _, productID := range productIDs {
+		var oldPrice float64
+		if err = tx.QueryRow(ctx, `select `+branchPrice("p.id", "nullif($2, '')::uuid", "$3")+`
+		 from product p where p.id = $1 for update`, productID, priceChange.BranchID, priceChange.EffectiveAt).Scan(&oldPrice); err != nil {
+			return false, err
+		}
+
+		newPrice := rule.NewPrice(oldPrice)
+
+		if priceChange.BranchID == "" {
+			if _, err = tx.Exec(ctx, `update product set price = $1, updated_at = $2 where id = $3`,
+				newPrice, now, productID); err != nil {
+				return false, err
+			}
+
+			if err = repriceBranches(ctx, tx, productID, rule, priceChange); err != nil {
+				return false, err
+			}
+		}
+
+		if _, err = tx.Exec(ctx, `insert into product_price (id, product_id, branch_id, price, effective_from, staff_id)
+		 values ($1, $2, nullif($3, '')::uuid, $4, $5, nullif($6, ''))`,
+			uuid.New(), productID, priceChange.BranchID, newPrice, priceChange.EffectiveAt, priceChange.ApprovedBy); err != nil {
+			return false, err
+		}
+
+		if _, err = tx.Exec(ctx, `insert into price_change_product (id, price_change_id, product_id, old_price, new_price)
+		 values ($1, $2, $3, $4, $5)
+		 on conflict (price_change_id, product_id)
+		 do update set old_price = excluded.old_price, new_price = excluded.new_price, updated_at = $6`,
+			uuid.New(), priceChange.ID, productID, oldPrice, newPrice, now); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `update price_change set status = $1, applied_at = $2, updated_at = $2 where id = $3`,
+		pricechange.Applied, now, priceChange.ID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// repriceBranches applies the rule of a price change without a branch to the
+// branch prices of the product in effect at its effective time, so branches
+// that sell the product at their own price are repriced too.
+func repriceBranches(ctx context.Context, tx pgx.Tx, productID string, rule pricechange.Rule, priceChange models.PriceChange) error {
+
+	rows, err := tx.Query(ctx, `select distinct on (branch_id) branch_id::text, price from product_price
+	 where deleted_at is null and product_id = $1 and branch_id is not null and effective_from <= $2
+	 order by branch_id, effective_from desc, created_at desc`, productID, priceChange.EffectiveAt)
+	if err != nil {
+		return err
+	}
+
+	prices := map[string]float64{}
+	for rows.Next() {
+		var (
+			branchID string
+			price    float64
+		)
+		if err = rows.Scan(&branchID, &price); err != nil {
+			rows.Close()
+			return err
+		}
+		prices[branchID] = price
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for branchID, price := range prices {
+		if _, err = tx.Exec(ctx, `insert into product_price (id, product_id, branch_id, price, effective_from, staff_id)
+		 values ($1, $2, $3, $4, $5, nullif($6, ''))`,
+			uuid.New(), productID, branchID, rule.NewPrice(price), priceChange.EffectiveAt, priceChange.ApprovedBy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanPriceChange(row pgx.Row) (models.PriceChange, error) {
+
+	var (
+		approvedAt  = sql.NullTime{}
+		appliedAt   = sql.NullTime{}
+		updatedAt   = sql.NullTime{}
+		priceChange = models.PriceChange{}
+	)
+
+	if err := row.Scan(
+		&priceChange.ID,
+		&priceChange.BranchID,
+		&priceChange.CategoryID,
+		&priceChange.ChangeType,
+		&priceChange.Value,
+		&priceChange.RoundTo,
+		&priceChange.Rounding,
+		&priceChange.EffectiveAt,
+		&priceChange.Status,
+		&priceChange.CreatedBy,
+		&priceChange.ApprovedBy,
+		&approvedAt,
+		&appliedAt,
+		&priceChange.Failure,
+		&priceChange.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		return models.PriceChange{}, err
+	}
+
+	if approvedAt.Valid {
+		priceChange.ApprovedAt = &approvedAt.Time
+	}
+
+	if appliedAt.Valid {
+		priceChange.AppliedAt = &appliedAt.Time
+	}
+
+	if updatedAt.Valid {
+		priceChange.UpdatedAt = updatedAt.Time
+	}
+
+	return priceChange, nil
+}
+
+func priceChangeProducts(ctx context.Context, db querier, priceChangeID string) ([]models.PriceChangeProduct, error) {
+
+	rows, err := db.Query(ctx, `select id, price_change_id, product_id, old_price, new_price
+	 from price_change_product where price_change_id = $1 order by created_at`, priceChangeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.PriceChangeProduct{}
+	for rows.Next() {
+		var (
+			product  = models.PriceChangeProduct{}
+			oldPrice = sql.NullFloat64{}
+			newPrice = sql.NullFloat64{}
+		)
+
+		if err = rows.Scan(
+			&product.ID,
+			&product.PriceChangeID,
+			&product.ProductID,
+			&oldPrice,
+			&newPrice,
+		); err != nil {
+			return nil, err
+		}
+
+		if oldPrice.Valid {
+			product.OldPrice = &oldPrice.Float64
+		}
+
+		if newPrice.Valid {
+			product.NewPrice = &newPrice.Float64
+		}
+
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
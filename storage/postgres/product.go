@@ -3,13 +3,16 @@ package postgres
 import (
 	"bazaar/api/models"
 	"bazaar/pkg/logger"
+	"bazaar/pkg/scope"
 	"bazaar/storage"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -25,13 +28,28 @@ func NewProductRepo(pool *pgxpool.Pool, log logger.ILogger) storage.IProductRepo
 	}
 }
 
-func (p *productRepo) Create(ctx context.Context, product models.CreateProduct) (string, error) {
+// Create adds the product and starts its price history with its price.
+func (p *productRepo) Create(ctx context.Context, product models.CreateProduct) (id string, err error) {
 
-	id := uuid.New()
+	id = uuid.New().String()
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.log.Error("error while starting product transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
 
 	query := `insert into product (id, name, price, category_id) values ($1, $2, $3, $4)`
 
-	_, err := p.pool.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		id,
 		product.Name,
 		product.Price,
@@ -42,7 +60,12 @@ func (p *productRepo) Create(ctx context.Context, product models.CreateProduct)
 		return "", err
 	}
 
-	return id.String(), nil
+	if err = recordProductPrice(ctx, tx, id, product.StaffID, ""); err != nil {
+		p.log.Error("error while recording product price", logger.Error(err))
+		return "", err
+	}
+
+	return id, nil
 }
 
 func (p *productRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Product, error) {
@@ -82,6 +105,49 @@ func (p *productRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Pro
 	return product, nil
 }
 
+// GetByBarcode returns storage.ErrProductNotFound when no product carries
+// the barcode.
+func (p *productRepo) GetByBarcode(ctx context.Context, barcode string) (models.Product, error) {
+
+	var updatedAt = sql.NullTime{}
+
+	product := models.Product{}
+
+	row := p.pool.QueryRow(ctx, `select
+	 id,
+	 name,
+	 price,
+	 barcode,
+	 category_id,
+	 created_at,
+	 updated_at  from product where deleted_at is null and barcode = $1`, barcode)
+
+	err := row.Scan(
+		&product.ID,
+		&product.Name,
+		&product.Price,
+		&product.Barcode,
+		&product.CategoryID,
+		&product.CreatedAt,
+		&updatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Product{}, storage.ErrProductNotFound
+	}
+
+	if err != nil {
+		p.log.Error("error while selecting product by barcode", logger.Error(err))
+		return models.Product{}, err
+	}
+
+	if updatedAt.Valid {
+		product.UpdatedAt = updatedAt.Time
+	}
+
+	return product, nil
+}
+
 func (p *productRepo) GetList(ctx context.Context, request models.ProductGetListRequest) (models.ProductsResponse, error) {
 
 	var (
@@ -153,7 +219,29 @@ func (p *productRepo) GetList(ctx context.Context, request models.ProductGetList
 	}, nil
 }
 
-func (p *productRepo) Update(ctx context.Context, request models.UpdateProduct) (string, error) {
+// Update changes the product and adds its new price to the price history
+// when the price changed.
+func (p *productRepo) Update(ctx context.Context, request models.UpdateProduct) (id string, err error) {
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.log.Error("error while starting product transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	var oldPrice string
+	if err = tx.QueryRow(ctx, `select price::text from product where id = $1 for update`, request.ID).Scan(&oldPrice); err != nil {
+		p.log.Error("error while selecting product for update", logger.Error(err))
+		return "", err
+	}
 
 	query := `update product
    set 
@@ -164,7 +252,7 @@ func (p *productRepo) Update(ctx context.Context, request models.UpdateProduct)
    where id = $5  
    `
 
-	_, err := p.pool.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		request.Name,
 		request.Price,
 		request.CategoryID,
@@ -174,6 +262,12 @@ func (p *productRepo) Update(ctx context.Context, request models.UpdateProduct)
 		p.log.Error("error while updating product data...", logger.Error(err))
 		return "", err
 	}
+
+	if err = recordProductPrice(ctx, tx, request.ID, request.StaffID, oldPrice); err != nil {
+		p.log.Error("error while recording product price", logger.Error(err))
+		return "", err
+	}
+
 	return request.ID, nil
 }
 
@@ -193,3 +287,181 @@ func (p *productRepo) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// BranchPrice is the price of the product at the branch at the given time:
+// the branch price in effect then, or the product price without one.
+func (p *productRepo) BranchPrice(ctx context.Context, productID, branchID string, at time.Time) (float64, error) {
+
+	var price float64
+
+	if err := p.pool.QueryRow(ctx, `select `+branchPrice("p.id", "$2", "$3")+`
+	 from product p where p.deleted_at is null and p.id = $1`, productID, branchID, at).Scan(&price); err != nil {
+		p.log.Error("error while selecting branch price", logger.Error(err))
+		return 0, err
+	}
+
+	return price, nil
+}
+
+// CreatePrice adds a branch price to the price history. It takes effect at
+// its effective time and lasts until a later one at the branch does.
+func (p *productRepo) CreatePrice(ctx context.Context, request models.CreateProductPrice) (string, error) {
+
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		p.log.Error("error while checking product price branch", logger.Error(err))
+		return "", err
+	}
+
+	if request.ProductID == "" || branchID == "" || request.Price < 0 {
+		return "", storage.ErrProductPrice
+	}
+
+	effectiveFrom := time.Now()
+	if request.EffectiveFrom != nil {
+		effectiveFrom = *request.EffectiveFrom
+	}
+
+	id := uuid.New().String()
+
+	tag, err := p.pool.Exec(ctx, `insert into product_price (id, product_id, branch_id, price, effective_from, staff_id)
+	 select $1, p.id, $3, $4, $5, nullif($6, '') from product p where p.deleted_at is null and p.id = $2`,
+		id, request.ProductID, branchID, request.Price, effectiveFrom, request.StaffID)
+	if err != nil {
+		p.log.Error("error while inserting product price", logger.Error(err))
+		return "", err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return "", storage.ErrProductPrice
+	}
+
+	return id, nil
+}
+
+// Prices lists what every branch sells the product at now and the price
+// history of the product, latest effective first. A caller bound to a branch
+// sees its own branch prices and the product prices.
+func (p *productRepo) Prices(ctx context.Context, productID string) (models.ProductPrices, error) {
+
+	prices := models.ProductPrices{
+		ProductID: productID,
+		Branches:  []models.BranchPrice{},
+		History:   []models.ProductPrice{},
+	}
+
+	if err := p.pool.QueryRow(ctx, `select price from product where deleted_at is null and id = $1`,
+		productID).Scan(&prices.Price); err != nil {
+		p.log.Error("error while selecting product price", logger.Error(err))
+		return models.ProductPrices{}, err
+	}
+
+	condition, args := branchCondition(ctx, "br.id", []interface{}{productID, time.Now()})
+
+	rows, err := p.pool.Query(ctx, `select br.id, br.name, o.price, o.effective_from
+	 from branch br
+	 left join lateral (
+		select pp.price, pp.effective_from from product_price pp
+		where pp.deleted_at is null and pp.product_id = $1 and pp.branch_id = br.id and pp.effective_from <= $2
+		order by pp.effective_from desc, pp.created_at desc limit 1
+	 ) o on true
+	 where br.deleted_at is null`+condition+`
+	 order by br.name`, args...)
+	if err != nil {
+		p.log.Error("error while selecting branch prices", logger.Error(err))
+		return models.ProductPrices{}, err
+	}
+
+	for rows.Next() {
+		var (
+			branch        = models.BranchPrice{}
+			price         = sql.NullFloat64{}
+			effectiveFrom = sql.NullTime{}
+		)
+
+		if err = rows.Scan(&branch.BranchID, &branch.BranchName, &price, &effectiveFrom); err != nil {
+			rows.Close()
+			p.log.Error("error while scanning branch price", logger.Error(err))
+			return models.ProductPrices{}, err
+		}
+
+		branch.Price = prices.Price
+		if price.Valid {
+			branch.Price = price.Float64
+			branch.Override = true
+			branch.EffectiveFrom = &effectiveFrom.Time
+		}
+
+		prices.Branches = append(prices.Branches, branch)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		p.log.Error("error while reading branch prices", logger.Error(err))
+		return models.ProductPrices{}, err
+	}
+
+	condition, args = priceBranchCondition(ctx, []interface{}{productID})
+
+	rows, err = p.pool.Query(ctx, `select id, product_id, coalesce(branch_id::text, ''), price, effective_from,
+	 coalesce(staff_id, ''), created_at
+	 from product_price where deleted_at is null and product_id = $1`+condition+`
+	 order by effective_from desc, created_at desc`, args...)
+	if err != nil {
+		p.log.Error("error while selecting price history", logger.Error(err))
+		return models.ProductPrices{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		price := models.ProductPrice{}
+		if err = rows.Scan(
+			&price.ID,
+			&price.ProductID,
+			&price.BranchID,
+			&price.Price,
+			&price.EffectiveFrom,
+			&price.StaffID,
+			&price.CreatedAt,
+		); err != nil {
+			p.log.Error("error while scanning price history", logger.Error(err))
+			return models.ProductPrices{}, err
+		}
+
+		prices.History = append(prices.History, price)
+	}
+
+	return prices, rows.Err()
+}
+
+// branchPrice selects the price of the product p at the branch at the given
+// time: the branch price in effect then, or the product price without one.
+func branchPrice(productID, branchID, at string) string {
+	return fmt.Sprintf(`coalesce((select pp.price from product_price pp
+	 where pp.deleted_at is null and pp.product_id = %s and pp.branch_id = %s and pp.effective_from <= %s
+	 order by pp.effective_from desc, pp.created_at desc limit 1), p.price)`, productID, branchID, at)
+}
+
+// recordProductPrice adds the current product price to the price history
+// unless it is still the old price.
+func recordProductPrice(ctx context.Context, tx pgx.Tx, productID, staffID, oldPrice string) error {
+
+	_, err := tx.Exec(ctx, `insert into product_price (id, product_id, price, effective_from, staff_id)
+	 select $1, id, price, $2, nullif($3, '') from product
+	 where id = $4 and ($5 = '' or price <> $5::numeric)`, uuid.New(), time.Now(), staffID, productID, oldPrice)
+
+	return err
+}
+
+// priceBranchCondition lets a caller bound to a branch see the prices of that
+// branch and the product prices of every branch.
+func priceBranchCondition(ctx context.Context, args []interface{}) (string, []interface{}) {
+	branchID := scope.BranchID(ctx)
+	if branchID == "" {
+		return "", args
+	}
+
+	args = append(args, branchID)
+
+	return fmt.Sprintf(` and (branch_id is null or branch_id = $%d)`, len(args)), args
+}
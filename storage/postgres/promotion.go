@@ -0,0 +1,294 @@
+package postgres
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/pkg/scope"
+	"bazaar/storage"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type promotionRepo struct {
+	pool *pgxpool.Pool
+	log  logger.ILogger
+}
+
+func NewPromotionRepo(pool *pgxpool.Pool, log logger.ILogger) storage.IPromotionRepo {
+	return &promotionRepo{
+		pool: pool,
+		log:  log,
+	}
+}
+
+const promotionColumns = `
+	id,
+	name,
+	promotion_type,
+	value,
+	buy_quantity,
+	get_quantity,
+	coalesce(product_id::text, ''),
+	coalesce(category_id::text, ''),
+	coalesce(branch_id::text, ''),
+	starts_at,
+	ends_at,
+	created_at,
+	updated_at`
+
+func (p *promotionRepo) Create(ctx context.Context, request models.CreatePromotion) (string, error) {
+
+	id := uuid.New()
+
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		p.log.Error("error while checking promotion branch", logger.Error(err))
+		return "", err
+	}
+
+	query := `insert into promotion (
+		id,
+		name,
+		promotion_type,
+		value,
+		buy_quantity,
+		get_quantity,
+		product_id,
+		category_id,
+		branch_id,
+		starts_at,
+		ends_at
+	) values ($1, $2, $3, $4, $5, $6, nullif($7, '')::uuid, nullif($8, '')::uuid, nullif($9, '')::uuid, $10, $11)`
+
+	if _, err = p.pool.Exec(ctx, query,
+		id,
+		request.Name,
+		request.PromotionType,
+		request.Value,
+		request.BuyQuantity,
+		request.GetQuantity,
+		request.ProductID,
+		request.CategoryID,
+		branchID,
+		request.StartsAt,
+		request.EndsAt,
+	); err != nil {
+		p.log.Error("error while inserting promotion", logger.Error(err))
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+func (p *promotionRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Promotion, error) {
+
+	condition, args := promotionBranchCondition(ctx, []interface{}{id.ID})
+
+	promotion, err := scanPromotion(p.pool.QueryRow(ctx, `select`+promotionColumns+`
+	 from promotion where deleted_at is null and id = $1`+condition, args...))
+	if err != nil {
+		p.log.Error("error while selecting promotion", logger.Error(err))
+		return models.Promotion{}, err
+	}
+
+	return promotion, nil
+}
+
+func (p *promotionRepo) GetList(ctx context.Context, request models.GetListRequest) (models.PromotionsResponse, error) {
+
+	var (
+		promotions = []models.Promotion{}
+		count      = 0
+		offset     = (request.Page - 1) * request.Limit
+	)
+
+	condition, args := promotionBranchCondition(ctx, nil)
+
+	if request.Search != "" {
+		args = append(args, request.Search)
+		condition += fmt.Sprintf(` and name ilike '%%' || $%d || '%%'`, len(args))
+	}
+
+	if err := p.pool.QueryRow(ctx, `select count(1) from promotion where deleted_at is null`+condition, args...).Scan(&count); err != nil {
+		p.log.Error("error while selecting promotion count", logger.Error(err))
+		return models.PromotionsResponse{}, err
+	}
+
+	query := `select` + promotionColumns + ` from promotion where deleted_at is null` + condition + ` order by created_at desc`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := p.pool.Query(ctx, query, append(args, request.Limit, offset)...)
+	if err != nil {
+		p.log.Error("error while selecting promotions", logger.Error(err))
+		return models.PromotionsResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			p.log.Error("error while scanning promotion", logger.Error(err))
+			return models.PromotionsResponse{}, err
+		}
+
+		promotions = append(promotions, promotion)
+	}
+
+	return models.PromotionsResponse{
+		Promotions: promotions,
+		Count:      count,
+	}, nil
+}
+
+func (p *promotionRepo) Update(ctx context.Context, request models.UpdatePromotion) (string, error) {
+
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		p.log.Error("error while checking promotion branch", logger.Error(err))
+		return "", err
+	}
+
+	query := `update promotion set
+	name = $1,
+	promotion_type = $2,
+	value = $3,
+	buy_quantity = $4,
+	get_quantity = $5,
+	product_id = nullif($6, '')::uuid,
+	category_id = nullif($7, '')::uuid,
+	branch_id = nullif($8, '')::uuid,
+	starts_at = $9,
+	ends_at = $10,
+	updated_at = $11
+	where deleted_at is null and id = $12`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{
+		request.Name,
+		request.PromotionType,
+		request.Value,
+		request.BuyQuantity,
+		request.GetQuantity,
+		request.ProductID,
+		request.CategoryID,
+		branchID,
+		request.StartsAt,
+		request.EndsAt,
+		time.Now(),
+		request.ID,
+	})
+
+	if _, err = p.pool.Exec(ctx, query+condition, args...); err != nil {
+		p.log.Error("error while updating promotion", logger.Error(err))
+		return "", err
+	}
+
+	return request.ID, nil
+}
+
+func (p *promotionRepo) Delete(ctx context.Context, id string) error {
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{time.Now(), id})
+
+	if _, err := p.pool.Exec(ctx, `update promotion set deleted_at = $1 where id = $2`+condition, args...); err != nil {
+		p.log.Error("error while deleting promotion", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// promotionBranchCondition lets a caller bound to a branch see the promotions
+// of that branch and the ones running in every branch.
+func promotionBranchCondition(ctx context.Context, args []interface{}) (string, []interface{}) {
+	branchID := scope.BranchID(ctx)
+	if branchID == "" {
+		return "", args
+	}
+
+	args = append(args, branchID)
+
+	return fmt.Sprintf(` and (branch_id is null or branch_id = $%d)`, len(args)), args
+}
+
+// applicablePromotions returns the promotions running at the moment for the
+// product in the branch. A category promotion also covers the products of
+// its subcategories.
+func applicablePromotions(ctx context.Context, tx pgx.Tx, branchID, productID, categoryID string, at time.Time) ([]models.Promotion, error) {
+
+	rows, err := tx.Query(ctx, `with recursive ancestors as (
+		select id, parent_id from category where id = nullif($3, '')::uuid
+		union
+		select c.id, c.parent_id from category c join ancestors a on c.id = a.parent_id
+	)
+	select`+promotionColumns+`
+	 from promotion
+	 where deleted_at is null
+	 and (branch_id is null or branch_id = $1)
+	 and (product_id is null or product_id = $2)
+	 and (category_id is null or category_id in (select id from ancestors))
+	 and (starts_at is null or starts_at <= $4)
+	 and (ends_at is null or ends_at > $4)`, branchID, productID, categoryID, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []models.Promotion{}
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, rows.Err()
+}
+
+func scanPromotion(row pgx.Row) (models.Promotion, error) {
+
+	var (
+		promotion = models.Promotion{}
+		startsAt  = sql.NullTime{}
+		endsAt    = sql.NullTime{}
+		updatedAt = sql.NullTime{}
+	)
+
+	if err := row.Scan(
+		&promotion.ID,
+		&promotion.Name,
+		&promotion.PromotionType,
+		&promotion.Value,
+		&promotion.BuyQuantity,
+		&promotion.GetQuantity,
+		&promotion.ProductID,
+		&promotion.CategoryID,
+		&promotion.BranchID,
+		&startsAt,
+		&endsAt,
+		&promotion.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		return models.Promotion{}, err
+	}
+
+	if startsAt.Valid {
+		promotion.StartsAt = &startsAt.Time
+	}
+
+	if endsAt.Valid {
+		promotion.EndsAt = &endsAt.Time
+	}
+
+	if updatedAt.Valid {
+		promotion.UpdatedAt = updatedAt.Time
+	}
+
+	return promotion, nil
+}
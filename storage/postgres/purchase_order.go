@@ -0,0 +1,377 @@
+package postgres
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/storage"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type purchaseOrderRepo struct {
+	pool *pgxpool.Pool
+	log  logger.ILogger
+}
+
+func NewPurchaseOrderRepo(pool *pgxpool.Pool, log logger.ILogger) storage.IPurchaseOrderRepo {
+	return &purchaseOrderRepo{
+		pool: pool,
+		log:  log,
+	}
+}
+
+func (p *purchaseOrderRepo) Create(ctx context.Context, request models.CreatePurchaseOrder) (id string, err error) {
+
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(request.Products) == 0 {
+		return "", storage.ErrOrderQuantity
+	}
+
+	var (
+		price   float64
+		ordered = make(map[string]bool)
+	)
+	for _, product := range request.Products {
+		// a product is ordered on one line, receipts are booked by product
+		if product.ProductID == "" || product.OrderedCount <= 0 || product.Price < 0 || ordered[product.ProductID] {
+			return "", storage.ErrOrderQuantity
+		}
+		ordered[product.ProductID] = true
+		price += product.Price * float64(product.OrderedCount)
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.log.Error("error while starting purchase order transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	id = uuid.New().String()
+
+	if _, err = tx.Exec(ctx, `insert into purchase_order (id, supplier_id, branch_id, price) values ($1, $2, $3, $4)`,
+		id,
+		request.SupplierID,
+		branchID,
+		price,
+	); err != nil {
+		p.log.Error("error while inserting purchase order", logger.Error(err))
+		return "", err
+	}
+
+	for _, product := range request.Products {
+		if _, err = tx.Exec(ctx, `insert into purchase_order_product (id, purchase_order_id, product_id, ordered_count, price)
+		 values ($1, $2, $3, $4, $5)`,
+			uuid.New(),
+			id,
+			product.ProductID,
+			product.OrderedCount,
+			product.Price,
+		); err != nil {
+			p.log.Error("error while inserting purchase order product", logger.Error(err))
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+func (p *purchaseOrderRepo) Get(ctx context.Context, id models.PrimaryKey) (models.PurchaseOrder, error) {
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id.ID})
+
+	order, err := scanPurchaseOrder(p.pool.QueryRow(ctx, `select id, supplier_id, branch_id, status, price,
+	 coalesce(income_id::text, ''), received_at, created_at, updated_at
+	 from purchase_order where deleted_at is null and id = $1`+condition, args...))
+	if err != nil {
+		p.log.Error("error while selecting purchase order", logger.Error(err))
+		return models.PurchaseOrder{}, err
+	}
+
+	if order.Products, err = purchaseOrderProducts(ctx, p.pool, order.ID); err != nil {
+		p.log.Error("error while selecting purchase order products", logger.Error(err))
+		return models.PurchaseOrder{}, err
+	}
+
+	if order.Status != "received" {
+		for i := range order.Products {
+			order.Products[i].Discrepancy = 0
+		}
+	}
+
+	return order, nil
+}
+
+func (p *purchaseOrderRepo) GetList(ctx context.Context, request models.GetPurchaseOrdersListRequest) (models.PurchaseOrdersResponse, error) {
+
+	var (
+		orders    = []models.PurchaseOrder{}
+		count     = 0
+		offset    = (request.Page - 1) * request.Limit
+		condition string
+		args      []interface{}
+	)
+
+	if request.SupplierID != "" {
+		args = append(args, request.SupplierID)
+		condition += fmt.Sprintf(` and supplier_id = $%d`, len(args))
+	}
+
+	if request.Status != "" {
+		args = append(args, request.Status)
+		condition += fmt.Sprintf(` and status = $%d`, len(args))
+	}
+
+	branch, args := branchCondition(ctx, "branch_id", args)
+	condition += branch
+
+	if err := p.pool.QueryRow(ctx, `select count(1) from purchase_order where deleted_at is null`+condition, args...).Scan(&count); err != nil {
+		p.log.Error("error while selecting purchase order count", logger.Error(err))
+		return models.PurchaseOrdersResponse{}, err
+	}
+
+	query := `select id, supplier_id, branch_id, status, price,
+	 coalesce(income_id::text, ''), received_at, created_at, updated_at
+	 from purchase_order where deleted_at is null` + condition + ` order by created_at desc`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := p.pool.Query(ctx, query, append(args, request.Limit, offset)...)
+	if err != nil {
+		p.log.Error("error while selecting purchase orders", logger.Error(err))
+		return models.PurchaseOrdersResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order, err := scanPurchaseOrder(rows)
+		if err != nil {
+			p.log.Error("error while scanning purchase order", logger.Error(err))
+			return models.PurchaseOrdersResponse{}, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	return models.PurchaseOrdersResponse{
+		PurchaseOrders: orders,
+		Count:          count,
+	}, nil
+}
+
+// Receive books the delivery of an ordered purchase order in one transaction:
+// it creates an income from the supplier with a line per delivered product,
+// puts the counts on the branch storage and keeps the received counts on the
+// order so discrepancies with the ordered ones stay visible.
+func (p *purchaseOrderRepo) Receive(ctx context.Context, request models.ReceivePurchaseOrder) (err error) {
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		p.log.Error("error while starting purchase order transaction", logger.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{request.ID})
+
+	var supplierID, branchID, status string
+	if err = tx.QueryRow(ctx, `select supplier_id, branch_id, status from purchase_order
+	 where deleted_at is null and id = $1`+condition+` for update`, args...).Scan(&supplierID, &branchID, &status); err != nil {
+		p.log.Error("error while selecting purchase order for update", logger.Error(err))
+		return err
+	}
+
+	if status != "ordered" {
+		err = storage.ErrOrderClosed
+		return err
+	}
+
+	lines, err := purchaseOrderProducts(ctx, tx, request.ID)
+	if err != nil {
+		p.log.Error("error while selecting purchase order products", logger.Error(err))
+		return err
+	}
+
+	received := make(map[string]models.ReceivePurchaseOrderProduct)
+	for _, product := range request.Products {
+		if _, ok := received[product.ProductID]; ok || product.ReceivedCount < 0 || product.Price < 0 {
+			err = storage.ErrOrderQuantity
+			return err
+		}
+		received[product.ProductID] = product
+	}
+
+	for productID := range received {
+		found := false
+		for _, line := range lines {
+			if line.ProductID == productID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err = storage.ErrOrderQuantity
+			return err
+		}
+	}
+
+	incomeID := uuid.New().String()
+	if _, err = tx.Exec(ctx, `insert into income (id, branch_id, supplier_id, price) values ($1, $2, $3, 0)`,
+		incomeID, branchID, supplierID); err != nil {
+		p.log.Error("error while inserting purchase order income", logger.Error(err))
+		return err
+	}
+
+	for _, line := range lines {
+		product, ok := received[line.ProductID]
+		if !ok {
+			continue
+		}
+
+		// an order placed before products were kept to one line books the
+		// receipt on its first line for the product only
+		delete(received, line.ProductID)
+
+		price := line.Price
+		if product.Price > 0 {
+			price = product.Price
+		}
+
+		if product.ReceivedCount > 0 {
+			if _, err = receiveIncomeProduct(ctx, tx, branchID, request.StaffID, models.CreateIncomeProduct{
+				IncomeID:  incomeID,
+				ProductID: line.ProductID,
+				Price:     price,
+				Count:     product.ReceivedCount,
+				ExpiresAt: product.ExpiresAt,
+			}); err != nil {
+				p.log.Error("error while receiving purchase order product", logger.Error(err))
+				return err
+			}
+		}
+
+		if _, err = tx.Exec(ctx, `update purchase_order_product set received_count = $1, price = $2, updated_at = $3
+		 where id = $4`, product.ReceivedCount, price, time.Now(), line.ID); err != nil {
+			p.log.Error("error while updating purchase order product", logger.Error(err))
+			return err
+		}
+	}
+
+	total, err := updateIncomePrice(ctx, tx, incomeID)
+	if err != nil {
+		p.log.Error("error while updating purchase order income price", logger.Error(err))
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `update purchase_order set status = 'received', income_id = $1, price = $2,
+	 received_at = $3, updated_at = $3 where id = $4`, incomeID, total, time.Now(), request.ID); err != nil {
+		p.log.Error("error while updating purchase order status", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (p *purchaseOrderRepo) Cancel(ctx context.Context, id string) error {
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id})
+
+	tag, err := p.pool.Exec(ctx, `update purchase_order set status = 'cancelled', updated_at = now()
+	 where deleted_at is null and status = 'ordered' and id = $1`+condition, args...)
+	if err != nil {
+		p.log.Error("error while cancelling purchase order", logger.Error(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return storage.ErrOrderClosed
+	}
+
+	return nil
+}
+
+func scanPurchaseOrder(row pgx.Row) (models.PurchaseOrder, error) {
+
+	var (
+		receivedAt = sql.NullTime{}
+		updatedAt  = sql.NullTime{}
+		order      = models.PurchaseOrder{}
+	)
+
+	if err := row.Scan(
+		&order.ID,
+		&order.SupplierID,
+		&order.BranchID,
+		&order.Status,
+		&order.Price,
+		&order.IncomeID,
+		&receivedAt,
+		&order.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	if receivedAt.Valid {
+		order.ReceivedAt = &receivedAt.Time
+	}
+
+	if updatedAt.Valid {
+		order.UpdatedAt = updatedAt.Time
+	}
+
+	return order, nil
+}
+
+func purchaseOrderProducts(ctx context.Context, db querier, orderID string) ([]models.PurchaseOrderProduct, error) {
+
+	rows, err := db.Query(ctx, `select id, purchase_order_id, product_id, ordered_count, received_count, price
+	 from purchase_order_product where deleted_at is null and purchase_order_id = $1 order by created_at`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.PurchaseOrderProduct{}
+	for rows.Next() {
+		product := models.PurchaseOrderProduct{}
+		if err = rows.Scan(
+			&product.ID,
+			&product.PurchaseOrderID,
+			&product.ProductID,
+			&product.OrderedCount,
+			&product.ReceivedCount,
+			&product.Price,
+		); err != nil {
+			return nil, err
+		}
+
+		product.Discrepancy = product.ReceivedCount - product.OrderedCount
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
@@ -0,0 +1,633 @@
+package postgres
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/storage"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// signedQuantity is the change a storage transaction made to the count.
+const signedQuantity = `case st.storage_transaction_type when 'plus' then st.quantity else -st.quantity end`
+
+type reportRepo struct {
+	pool *pgxpool.Pool
+	log  logger.ILogger
+}
+
+func NewReportRepo(pool *pgxpool.Pool, log logger.ILogger) storage.IReportRepo {
+	return &reportRepo{
+		pool: pool,
+		log:  log,
+	}
+}
+
+// StockMovement lists the ledger of a product at a branch between from and
+// to. The opening balance is the live storage count less every movement
+// booked since from.
+func (r *reportRepo) StockMovement(ctx context.Context, request models.StockMovementRequest) (models.StockMovementReport, error) {
+
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		return models.StockMovementReport{}, err
+	}
+
+	if branchID == "" || request.ProductID == "" {
+		return models.StockMovementReport{}, storage.ErrReportFilter
+	}
+
+	report := models.StockMovementReport{
+		BranchID:  branchID,
+		ProductID: request.ProductID,
+		From:      request.From,
+		To:        request.To,
+		Movements: []models.StockMovement{},
+	}
+
+	if err = r.pool.QueryRow(ctx, `select
+	 coalesce((select sum(count) from storage where deleted_at is null and branch_id = $1 and product_id = $2), 0)::int -
+	 coalesce((select sum(`+signedQuantity+`) from storage_transaction st
+	  where st.deleted_at is null and st.branch_id = $1 and st.product_id = $2 and st.created_at >= $3), 0)::int`,
+		branchID, request.ProductID, request.From).Scan(&report.OpeningBalance); err != nil {
+		r.log.Error("error while selecting opening balance", logger.Error(err))
+		return models.StockMovementReport{}, err
+	}
+
+	args := []interface{}{branchID, request.ProductID, request.From}
+	condition := ""
+	if !request.To.IsZero() {
+		args = append(args, request.To)
+		condition = fmt.Sprintf(` and st.created_at < $%d`, len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, `select st.id, st.created_at, coalesce(st.source_type, ''), coalesce(st.source_id::text, ''),
+	 coalesce(st.staff_id, ''), coalesce(st.reason, ''), (`+signedQuantity+`)::int, st.price
+	 from storage_transaction st
+	 where st.deleted_at is null and st.branch_id = $1 and st.product_id = $2 and st.created_at >= $3`+condition+`
+	 order by st.created_at, st.id`, args...)
+	if err != nil {
+		r.log.Error("error while selecting stock movements", logger.Error(err))
+		return models.StockMovementReport{}, err
+	}
+	defer rows.Close()
+
+	balance := report.OpeningBalance
+	for rows.Next() {
+		var (
+			movement = models.StockMovement{}
+			quantity int
+		)
+
+		if err = rows.Scan(
+			&movement.StorageTransactionID,
+			&movement.CreatedAt,
+			&movement.SourceType,
+			&movement.SourceID,
+			&movement.StaffID,
+			&movement.Reason,
+			&quantity,
+			&movement.Price,
+		); err != nil {
+			r.log.Error("error while scanning stock movement", logger.Error(err))
+			return models.StockMovementReport{}, err
+		}
+
+		if quantity > 0 {
+			movement.In = quantity
+			report.TotalIn += quantity
+		} else {
+			movement.Out = -quantity
+			report.TotalOut -= quantity
+		}
+
+		balance += quantity
+		movement.Balance = balance
+
+		report.Movements = append(report.Movements, movement)
+	}
+
+	report.ClosingBalance = balance
+
+	return report, rows.Err()
+}
+
+// StockOnDate rebuilds the count of every product at every branch as it was
+// at the given moment by taking back from the live storage count the
+// movements booked since. Products with nothing in stock are left out.
+func (r *reportRepo) StockOnDate(ctx context.Context, request models.StockOnDateRequest) (models.StockOnDate, error) {
+
+	at := request.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	var (
+		args      = []interface{}{at}
+		condition string
+	)
+
+	if request.BranchID != "" {
+		args = append(args, request.BranchID)
+		condition += fmt.Sprintf(` and s.branch_id = $%d`, len(args))
+	}
+
+	if request.ProductID != "" {
+		args = append(args, request.ProductID)
+		condition += fmt.Sprintf(` and s.product_id = $%d`, len(args))
+	}
+
+	branch, args := branchCondition(ctx, "s.branch_id", args)
+	condition += branch
+
+	rows, err := r.pool.Query(ctx, `select s.branch_id, s.product_id, p.name,
+	 (s.count - coalesce((select sum(`+signedQuantity+`) from storage_transaction st
+	  where st.deleted_at is null and st.branch_id = s.branch_id and st.product_id = s.product_id
+	  and st.created_at >= $1), 0))::int as on_date
+	 from storage s join product p on p.id = s.product_id
+	 where s.deleted_at is null`+condition+`
+	 order by s.branch_id, p.name`, args...)
+	if err != nil {
+		r.log.Error("error while selecting stock on date", logger.Error(err))
+		return models.StockOnDate{}, err
+	}
+	defer rows.Close()
+
+	report := models.StockOnDate{
+		At:       at,
+		Products: []models.StockOnDateLine{},
+	}
+
+	for rows.Next() {
+		line := models.StockOnDateLine{}
+		if err = rows.Scan(
+			&line.BranchID,
+			&line.ProductID,
+			&line.ProductName,
+			&line.Count,
+		); err != nil {
+			r.log.Error("error while scanning stock on date", logger.Error(err))
+			return models.StockOnDate{}, err
+		}
+
+		if line.Count != 0 {
+			report.Products = append(report.Products, line)
+		}
+	}
+
+	return report, rows.Err()
+}
+
+// LowStock lists the products below their minimum. The suggested quantity
+// brings the count back to the minimum and covers as many days of sales as
+// were looked at, and is never below the reorder quantity.
+func (r *reportRepo) LowStock(ctx context.Context, request models.LowStockRequest) (models.LowStockReport, error) {
+
+	days := request.Days
+	if days <= 0 {
+		days = 30
+	}
+
+	var (
+		args      = []interface{}{time.Now().AddDate(0, 0, -days)}
+		condition string
+	)
+
+	if request.BranchID != "" {
+		args = append(args, request.BranchID)
+		condition += fmt.Sprintf(` and s.branch_id = $%d`, len(args))
+	}
+
+	branch, args := branchCondition(ctx, "s.branch_id", args)
+	condition += branch
+
+	rows, err := r.pool.Query(ctx, `select s.id, s.branch_id, s.product_id, p.name, s.count, s.min_count, s.reorder_count,
+	 coalesce((select sum(b.quantity) from basket b join sale sa on sa.id = b.sale_id
+	  where b.deleted_at is null and sa.deleted_at is null and sa.status in ('completed', 'refunded')
+	  and sa.branch_id = s.branch_id and b.product_id = s.product_id and sa.created_at >= $1), 0)::int
+	 from storage s join product p on p.id = s.product_id
+	 where s.deleted_at is null and s.count < s.min_count`+condition+`
+	 order by s.branch_id, p.name`, args...)
+	if err != nil {
+		r.log.Error("error while selecting low stock", logger.Error(err))
+		return models.LowStockReport{}, err
+	}
+	defer rows.Close()
+
+	report := models.LowStockReport{
+		Days:  days,
+		Items: []models.LowStockItem{},
+	}
+
+	for rows.Next() {
+		item := models.LowStockItem{}
+		if err = rows.Scan(
+			&item.StorageID,
+			&item.BranchID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Count,
+			&item.MinCount,
+			&item.ReorderCount,
+			&item.SoldCount,
+		); err != nil {
+			r.log.Error("error while scanning low stock", logger.Error(err))
+			return models.LowStockReport{}, err
+		}
+
+		item.DailyVelocity = float64(item.SoldCount) / float64(days)
+
+		item.SuggestedCount = item.MinCount - item.Count + item.SoldCount
+		if item.SuggestedCount < item.ReorderCount {
+			item.SuggestedCount = item.ReorderCount
+		}
+
+		report.Items = append(report.Items, item)
+	}
+
+	return report, rows.Err()
+}
+
+// ExpiringBatches lists the batches in stock expiring within the given days,
+// the expired ones included, soonest first within each branch.
+func (r *reportRepo) ExpiringBatches(ctx context.Context, request models.ExpiringBatchesRequest) (models.ExpiringBatchesReport, error) {
+
+	days := request.Days
+	if days <= 0 {
+		days = 30
+	}
+
+	var (
+		now       = time.Now()
+		args      = []interface{}{now.AddDate(0, 0, days)}
+		condition string
+	)
+
+	if request.BranchID != "" {
+		args = append(args, request.BranchID)
+		condition += fmt.Sprintf(` and b.branch_id = $%d`, len(args))
+	}
+
+	branch, args := branchCondition(ctx, "b.branch_id", args)
+	condition += branch
+
+	rows, err := r.pool.Query(ctx, `select b.id, b.branch_id, b.product_id, p.name, b.expires_at, b.count, b.cost
+	 from batch b join product p on p.id = b.product_id
+	 where b.deleted_at is null and b.count > 0 and b.expires_at <= $1`+condition+`
+	 order by b.branch_id, b.expires_at, p.name`, args...)
+	if err != nil {
+		r.log.Error("error while selecting expiring batches", logger.Error(err))
+		return models.ExpiringBatchesReport{}, err
+	}
+	defer rows.Close()
+
+	report := models.ExpiringBatchesReport{
+		Days:    days,
+		Batches: []models.ExpiringBatch{},
+	}
+
+	for rows.Next() {
+		batch := models.ExpiringBatch{}
+		if err = rows.Scan(
+			&batch.BatchID,
+			&batch.BranchID,
+			&batch.ProductID,
+			&batch.ProductName,
+			&batch.ExpiresAt,
+			&batch.Count,
+			&batch.Cost,
+		); err != nil {
+			r.log.Error("error while scanning expiring batch", logger.Error(err))
+			return models.ExpiringBatchesReport{}, err
+		}
+
+		batch.DaysLeft = int(math.Floor(batch.ExpiresAt.Sub(now).Hours() / 24))
+		batch.Value = batch.Cost * float64(batch.Count)
+
+		report.Batches = append(report.Batches, batch)
+	}
+
+	return report, rows.Err()
+}
+
+// marginGroups are the key and name each margin report grouping selects over
+// the sold lines l joined with product p.
+var marginGroups = map[string]struct {
+	key, name, join string
+}{
+	"sale":     {"l.sale_id::text", "coalesce(sa.receipt_number::text, '')", " join sale sa on sa.id = l.sale_id"},
+	"product":  {"l.product_id::text", "p.name", ""},
+	"category": {"coalesce(p.category_id::text, '')", "coalesce(c.name, '')", " left join category c on c.id = p.category_id"},
+	"branch":   {"l.branch_id::text", "coalesce(br.name, '')", " join branch br on br.id = l.branch_id"},
+}
+
+// Margin sums the revenue, cost of goods sold and gross margin of the
+// completed sales in the period by sale, product, category or branch,
+// leaving out what was returned.
+func (r *reportRepo) Margin(ctx context.Context, request models.MarginRequest) (models.MarginReport, error) {
+
+	group, ok := marginGroups[request.GroupBy]
+	if !ok {
+		return models.MarginReport{}, storage.ErrReportGroup
+	}
+
+	var (
+		args      = []interface{}{}
+		condition string
+	)
+
+	if request.BranchID != "" {
+		args = append(args, request.BranchID)
+		condition += fmt.Sprintf(` and s.branch_id = $%d`, len(args))
+	}
+
+	if !request.From.IsZero() {
+		args = append(args, request.From)
+		condition += fmt.Sprintf(` and s.created_at >= $%d`, len(args))
+	}
+
+	if !request.To.IsZero() {
+		args = append(args, request.To)
+		condition += fmt.Sprintf(` and s.created_at < $%d`, len(args))
+	}
+
+	branch, args := branchCondition(ctx, "s.branch_id", args)
+	condition += branch
+
+	rows, err := r.pool.Query(ctx, `with lines as (
+		select s.id as sale_id, s.branch_id, b.product_id,
+		 b.quantity - rt.quantity as quantity,
+		 b.price - rt.price as revenue,
+		 coalesce(b.cost, 0) * (b.quantity - rt.quantity) / nullif(b.quantity, 0) as cogs
+		from basket b
+		join sale s on s.id = b.sale_id
+		cross join lateral (
+			select coalesce(sum(rp.quantity), 0) as quantity, coalesce(sum(rp.price), 0) as price
+			from sale_return_product rp join sale_return sr on sr.id = rp.sale_return_id
+			where rp.deleted_at is null and sr.deleted_at is null and rp.basket_id = b.id
+		) rt
+		where b.deleted_at is null and s.deleted_at is null and s.status in ('completed', 'refunded')`+condition+`
+	)
+	select `+group.key+`, `+group.name+`, coalesce(sum(l.quantity), 0)::int, coalesce(sum(l.revenue), 0), coalesce(sum(l.cogs), 0)
+	 from lines l join product p on p.id = l.product_id`+group.join+`
+	 group by 1, 2
+	 order by 4 desc`, args...)
+	if err != nil {
+		r.log.Error("error while selecting margin", logger.Error(err))
+		return models.MarginReport{}, err
+	}
+	defer rows.Close()
+
+	report := models.MarginReport{
+		GroupBy: request.GroupBy,
+		From:    request.From,
+		To:      request.To,
+		Lines:   []models.MarginLine{},
+	}
+
+	for rows.Next() {
+		line := models.MarginLine{}
+		if err = rows.Scan(
+			&line.Key,
+			&line.Name,
+			&line.Quantity,
+			&line.Revenue,
+			&line.COGS,
+		); err != nil {
+			r.log.Error("error while scanning margin", logger.Error(err))
+			return models.MarginReport{}, err
+		}
+
+		report.Total.Quantity += line.Quantity
+		report.Total.Revenue += line.Revenue
+		report.Total.COGS += line.COGS
+
+		report.Lines = append(report.Lines, withMargin(line))
+	}
+
+	report.Total = withMargin(report.Total)
+
+	return report, rows.Err()
+}
+
+func withMargin(line models.MarginLine) models.MarginLine {
+
+	line.GrossMargin = line.Revenue - line.COGS
+	if line.Revenue != 0 {
+		line.MarginPercent = line.GrossMargin / line.Revenue * 100
+	}
+
+	return line
+}
+
+// salesLines selects the completed sales of the report filter, their basket
+// lines less what was returned, their tenders by payment type with the
+// returns paid back in each, sales paid before split tenders taken as one
+// tender of the whole price, and every category with its top level one.
+const salesLines = `with recursive roots as (
+		select id, id as root_id from category where parent_id is null
+		union all
+		select c.id, r.root_id from category c join roots r on c.parent_id = r.id
+	),
+	sales as (
+		select s.id, s.branch_id, s.cashier_id, coalesce(s.shop_assistent_id, '') as shop_assistant_id,
+		 s.payment_type, s.price, s.completed_at
+		from sale s
+		where s.deleted_at is null and s.status in ('completed', 'refunded')%s
+	),
+	sold as (
+		select s.id as sale_id, s.branch_id, s.cashier_id, s.shop_assistant_id, s.completed_at,
+		 b.product_id, b.quantity - rt.quantity as quantity, b.price - rt.price as price
+		from basket b
+		join sales s on s.id = b.sale_id
+		cross join lateral (
+			select coalesce(sum(rp.quantity), 0) as quantity, coalesce(sum(rp.price), 0) as price
+			from sale_return_product rp join sale_return sr on sr.id = rp.sale_return_id
+			where rp.deleted_at is null and sr.deleted_at is null and rp.basket_id = b.id
+		) rt
+		where b.deleted_at is null
+	),
+	tenders as (
+		select t.sale_id, t.payment_type, sum(t.paid) as paid, sum(t.returned) as returned
+		from (
+			select s.id as sale_id, sp.payment_type, sp.amount as paid, 0 as returned
+			from sale_payment sp join sales s on s.id = sp.sale_id
+			where sp.deleted_at is null
+			union all
+			select s.id, coalesce(s.payment_type, ''), s.price, 0
+			from sales s
+			where not exists (select 1 from sale_payment sp where sp.deleted_at is null and sp.sale_id = s.id)
+			union all
+			select s.id, coalesce(sr.payment_type, ''), 0, sr.amount
+			from sale_return sr join sales s on s.id = sr.sale_id
+			where sr.deleted_at is null
+		) t
+		group by 1, 2
+	)`
+
+// salesGroups are the key, name, joins and order of each sales report
+// grouping over the sold lines l.
+var salesGroups = map[string]struct {
+	key, name, join, order string
+}{
+	"branch":         {"l.branch_id::text", "coalesce(br.name, '')", " left join branch br on br.id = l.branch_id", "5 desc"},
+	"day":            {"to_char(l.completed_at, 'YYYY-MM-DD')", "to_char(l.completed_at, 'YYYY-MM-DD')", "", "1"},
+	"hour":           {"to_char(l.completed_at, 'HH24')", "to_char(l.completed_at, 'HH24')", "", "1"},
+	"cashier":        {"l.cashier_id", "coalesce(st.name, '')", " left join staff st on st.id = l.cashier_id", "5 desc"},
+	"shop_assistant": {"l.shop_assistant_id", "coalesce(st.name, '')", " left join staff st on st.id = l.shop_assistant_id", "5 desc"},
+	"category": {"coalesce(rc.id::text, '')", "coalesce(rc.name, '')", ` join product p on p.id = l.product_id
+	 left join roots r on r.id = p.category_id
+	 left join category rc on rc.id = r.root_id`, "5 desc"},
+}
+
+// Sales sums the sales completed in the period by branch, day, hour of the
+// day, cashier, shop assistant, top level category or payment type, with the
+// totals and the best selling products by revenue, leaving out what was
+// returned. A payment type has the quantity of every sale paid with it and
+// the amount paid with it less the returns paid back in it.
+func (r *reportRepo) Sales(ctx context.Context, request models.SalesReportRequest) (models.SalesReport, error) {
+
+	var groupQuery string
+
+	if request.GroupBy == "payment_type" {
+		groupQuery = `
+	select t.payment_type, t.payment_type, count(distinct t.sale_id) filter (where t.paid > 0)::int,
+	 coalesce(sum(q.quantity) filter (where t.paid > 0), 0)::int, coalesce(sum(t.paid - t.returned), 0)
+	 from tenders t
+	 left join (select l.sale_id, sum(l.quantity) as quantity from sold l group by 1) q on q.sale_id = t.sale_id
+	 group by 1, 2
+	 order by 5 desc`
+	} else {
+		group, ok := salesGroups[request.GroupBy]
+		if !ok {
+			return models.SalesReport{}, storage.ErrReportGroup
+		}
+
+		groupQuery = `
+	select ` + group.key + `, ` + group.name + `, count(distinct l.sale_id)::int, coalesce(sum(l.quantity), 0)::int, coalesce(sum(l.price), 0)
+	 from sold l` + group.join + `
+	 group by 1, 2
+	 order by ` + group.order
+	}
+
+	top := request.Top
+	if top <= 0 {
+		top = 10
+	}
+
+	var (
+		args      = []interface{}{}
+		condition string
+	)
+
+	if request.BranchID != "" {
+		args = append(args, request.BranchID)
+		condition += fmt.Sprintf(` and s.branch_id = $%d`, len(args))
+	}
+
+	if !request.From.IsZero() {
+		args = append(args, request.From)
+		condition += fmt.Sprintf(` and s.completed_at >= $%d`, len(args))
+	}
+
+	if !request.To.IsZero() {
+		args = append(args, request.To)
+		condition += fmt.Sprintf(` and s.completed_at < $%d`, len(args))
+	}
+
+	branch, args := branchCondition(ctx, "s.branch_id", args)
+	condition += branch
+
+	with := fmt.Sprintf(salesLines, condition)
+
+	report := models.SalesReport{
+		GroupBy:     request.GroupBy,
+		From:        request.From,
+		To:          request.To,
+		Lines:       []models.SalesReportLine{},
+		TopProducts: []models.TopProduct{},
+	}
+
+	if err := r.pool.QueryRow(ctx, with+`
+	select count(distinct l.sale_id)::int, coalesce(sum(l.quantity), 0)::int, coalesce(sum(l.price), 0) from sold l`, args...).Scan(
+		&report.Total.SalesCount,
+		&report.Total.Quantity,
+		&report.Total.Revenue,
+	); err != nil {
+		r.log.Error("error while selecting sales totals", logger.Error(err))
+		return models.SalesReport{}, err
+	}
+
+	report.Total = withAverageTicket(report.Total)
+
+	rows, err := r.pool.Query(ctx, with+groupQuery, args...)
+	if err != nil {
+		r.log.Error("error while selecting sales by group", logger.Error(err))
+		return models.SalesReport{}, err
+	}
+
+	for rows.Next() {
+		line := models.SalesReportLine{}
+		if err = rows.Scan(
+			&line.Key,
+			&line.Name,
+			&line.SalesCount,
+			&line.Quantity,
+			&line.Revenue,
+		); err != nil {
+			rows.Close()
+			r.log.Error("error while scanning sales by group", logger.Error(err))
+			return models.SalesReport{}, err
+		}
+
+		report.Lines = append(report.Lines, withAverageTicket(line))
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		r.log.Error("error while reading sales by group", logger.Error(err))
+		return models.SalesReport{}, err
+	}
+
+	rows, err = r.pool.Query(ctx, with+fmt.Sprintf(`
+	select l.product_id::text, p.name, coalesce(sum(l.quantity), 0)::int, coalesce(sum(l.price), 0)
+	 from sold l join product p on p.id = l.product_id
+	 group by 1, 2
+	 order by 4 desc, 3 desc
+	 limit $%d`, len(args)+1), append(args, top)...)
+	if err != nil {
+		r.log.Error("error while selecting top products", logger.Error(err))
+		return models.SalesReport{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product := models.TopProduct{}
+		if err = rows.Scan(
+			&product.ProductID,
+			&product.ProductName,
+			&product.Quantity,
+			&product.Revenue,
+		); err != nil {
+			r.log.Error("error while scanning top products", logger.Error(err))
+			return models.SalesReport{}, err
+		}
+
+		report.TopProducts = append(report.TopProducts, product)
+	}
+
+	return report, rows.Err()
+}
+
+func withAverageTicket(line models.SalesReportLine) models.SalesReportLine {
+
+	if line.SalesCount > 0 {
+		line.AverageTicket = line.Revenue / float64(line.SalesCount)
+	}
+
+	return line
+}
@@ -3,13 +3,16 @@ package postgres
 import (
 	"bazaar/api/models"
 	"bazaar/pkg/logger"
+	"bazaar/pkg/salestatus"
 	"bazaar/storage"
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -29,17 +32,24 @@ func (s *saleRepo) Create(ctx context.Context, sale models.CreateSale) (string,
 
 	id := uuid.New()
 
-	query := `insert into sale (id, branch_id, shop_assistent_id, cashier_id, payment_type, price, status, client_name) values ($1, $2, $3, $4, $5, $6, $7, $8)`
+	branchID, err := scopedBranchID(ctx, sale.BranchID)
+	if err != nil {
+		s.log.Error("error while checking sale branch", logger.Error(err))
+		return "", err
+	}
 
-	_, err := s.pool.Exec(ctx, query,
+	query := `insert into sale (id, branch_id, shop_assistent_id, cashier_id, payment_type, price, status, client_name, customer_id) values ($1, $2, $3, $4, $5, $6, $7, $8, nullif($9, '')::uuid)`
+
+	_, err = s.pool.Exec(ctx, query,
 		id,
-		sale.BranchID,
+		branchID,
 		sale.ShopAssistantID,
 		sale.CashierID,
 		sale.PaymentType,
 		sale.Price,
-		sale.Status,
+		salestatus.Draft,
 		sale.ClientName,
+		sale.CustomerID,
 	)
 	if err != nil {
 		s.log.Error("error while inserting sale", logger.Error(err))
@@ -55,7 +65,11 @@ func (s *saleRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Sale,
 
 	sale := models.Sale{}
 
-	row := s.pool.QueryRow(ctx, `select id, branch_id, shop_assistent_id, cashier_id, payment_type, price, status, client_name, created_at, updated_at  from sale where deleted_at is null and id = $1`, id.ID)
+	query := `select id, branch_id, shop_assistent_id, cashier_id, payment_type, price, status, client_name, coalesce(customer_id::text, ''), coalesce(receipt_number, 0), created_at, updated_at  from sale where deleted_at is null and id = $1`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id.ID})
+
+	row := s.pool.QueryRow(ctx, query+condition, args...)
 
 	err := row.Scan(
 		&sale.ID,
@@ -66,6 +80,8 @@ func (s *saleRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Sale,
 		&sale.Price,
 		&sale.Status,
 		&sale.ClientName,
+		&sale.CustomerID,
+		&sale.ReceiptNumber,
 		&sale.CreatedAt,
 		&updatedAt,
 	)
@@ -94,12 +110,14 @@ func (s *saleRepo) GetList(ctx context.Context, request models.GetListRequest) (
 		search            = request.Search
 	)
 
-	countQuery = `select count(1) from sale where deleted_at is null`
+	condition, args := branchCondition(ctx, "branch_id", nil)
+
+	countQuery = `select count(1) from sale where deleted_at is null` + condition
 
 	if search != "" {
-		countQuery += fmt.Sprintf(` and status ilike '%%%s%%' or payment_type ilike '%%%s%%'`, search, search)
+		countQuery += fmt.Sprintf(` and (status ilike '%%%s%%' or payment_type ilike '%%%s%%')`, search, search)
 	}
-	if err := s.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+	if err := s.pool.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", logger.Error(err))
 		return models.SalesResponse{}, err
 	}
@@ -113,15 +131,17 @@ func (s *saleRepo) GetList(ctx context.Context, request models.GetListRequest) (
 	price, 
 	status, 
 	client_name, 
+	coalesce(customer_id::text, ''), 
+	coalesce(receipt_number, 0), 
 	created_at, 
-	updated_at from sale where deleted_at is null`
+	updated_at from sale where deleted_at is null` + condition
 
 	if search != "" {
-		query += fmt.Sprintf(` and status ilike '%%%s%%' or payment_type ilike '%%%s%%'`, search, search)
+		query += fmt.Sprintf(` and (status ilike '%%%s%%' or payment_type ilike '%%%s%%')`, search, search)
 	}
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := s.pool.Query(ctx, query, request.Limit, offset)
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := s.pool.Query(ctx, query, append(args, request.Limit, offset)...)
 	if err != nil {
 		fmt.Println("error is while selecting product", logger.Error(err))
 		return models.SalesResponse{}, err
@@ -138,6 +158,8 @@ func (s *saleRepo) GetList(ctx context.Context, request models.GetListRequest) (
 			&sale.Price,
 			&sale.Status,
 			&sale.ClientName,
+			&sale.CustomerID,
+			&sale.ReceiptNumber,
 			&sale.CreatedAt,
 			&updatedAt,
 		); err != nil {
@@ -159,7 +181,40 @@ func (s *saleRepo) GetList(ctx context.Context, request models.GetListRequest) (
 	}, nil
 }
 
-func (s *saleRepo) Update(ctx context.Context, request models.UpdateSale) (string, error) {
+// Update changes the details of a sale that is still a draft or open. The
+// sale is locked while its status is checked, so a sale completed or
+// cancelled meanwhile is not changed.
+func (s *saleRepo) Update(ctx context.Context, request models.UpdateSale) (id string, err error) {
+
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		s.log.Error("error while checking sale branch", logger.Error(err))
+		return "", err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("error while starting update sale transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	sale, err := selectSaleForUpdate(ctx, tx, request.ID)
+	if err != nil {
+		s.log.Error("error while selecting sale for update", logger.Error(err))
+		return "", err
+	}
+
+	if err = salestatus.CanEdit(sale.Status); err != nil {
+		return "", err
+	}
 
 	query := `update sale set 
 	branch_id = $1, 
@@ -167,37 +222,58 @@ func (s *saleRepo) Update(ctx context.Context, request models.UpdateSale) (strin
 	cashier_id = $3, 
 	payment_type = $4, 
 	price = $5, 
-	status = $6, 
-	client_name = $7, 
+	client_name = $6, 
+	customer_id = nullif($7, '')::uuid, 
 	updated_at = $8 
 	 where id = $9`
 
-	_, err := s.pool.Exec(ctx, query,
-		request.BranchID,
+	if _, err = tx.Exec(ctx, query,
+		branchID,
 		request.ShopAssistantID,
 		request.CashierID,
 		request.PaymentType,
 		request.Price,
-		request.Status,
 		request.ClientName,
+		request.CustomerID,
 		time.Now(),
-		request.ID,
-	)
-	if err != nil {
+		sale.ID,
+	); err != nil {
 		s.log.Error("error while updating sale data...", logger.Error(err))
 		return "", err
 	}
-	return request.ID, nil
+
+	return sale.ID, nil
 }
 
-func (s *saleRepo) Delete(ctx context.Context, id string) error {
+// Delete drops a sale that left no trace in storage or staff balances. The
+// sale is locked while its status is checked.
+func (s *saleRepo) Delete(ctx context.Context, id string) (err error) {
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("error while starting delete sale transaction", logger.Error(err))
+		return err
+	}
 
-	query := `update sale 
-	set deleted_at = $1 
-	where id = $2`
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
 
-	_, err := s.pool.Exec(ctx, query, time.Now(), id)
+	sale, err := selectSaleForUpdate(ctx, tx, id)
 	if err != nil {
+		s.log.Error("error while selecting sale for update", logger.Error(err))
+		return err
+	}
+
+	if err = salestatus.CanDelete(sale.Status); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `update sale set deleted_at = $1 where id = $2`, time.Now(), sale.ID); err != nil {
 		s.log.Error("error while deleting sale by id", logger.Error(err))
 		return err
 	}
@@ -205,22 +281,775 @@ func (s *saleRepo) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (s *saleRepo) UpdateSalePrice(ctx context.Context, request models.SaleRequest) (string, error) {
+func (s *saleRepo) UpdateStatus(ctx context.Context, request models.UpdateSaleStatus) (err error) {
 
-	query := `update sale set 
-  price = $1,
-  status = $2,
-  updated_at = $3 
-  where id = $4 `
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("error while starting update sale status transaction", logger.Error(err))
+		return err
+	}
 
-	if rowsAffected, err := s.pool.Exec(ctx, query, request.TotalPrice, request.Status, time.Now(), request.ID); err != nil {
-		if r := rowsAffected.RowsAffected(); r == 0 {
-			s.log.Error("error in rows affected ", logger.Error(err))
-			return "", err
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
 		}
-		s.log.Error("error while updating sale price and status...", logger.Error(err))
-		return "", err
+	}()
+
+	sale, err := selectSaleForUpdate(ctx, tx, request.ID)
+	if err != nil {
+		s.log.Error("error while selecting sale for update", logger.Error(err))
+		return err
+	}
+
+	if err = salestatus.Transition(sale.Status, request.Status); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, `update sale set status = $1, updated_at = $2,
+	 completed_at = case when $1 = 'completed' then $2 else completed_at end where id = $3`,
+		request.Status, time.Now(), sale.ID); err != nil {
+		s.log.Error("error while updating sale status", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// CompleteSale closes the sale in one transaction: it locks and decrements the
+// branch storage rows, writes the storage transactions, records the payment
+// tenders, sets the sale price and status and credits the staff commissions.
+func (s *saleRepo) CompleteSale(ctx context.Context, request models.SaleRequest) (receipt models.SaleReceipt, err error) {
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("error while starting complete sale transaction", logger.Error(err))
+		return models.SaleReceipt{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	sale, err := selectSaleForUpdate(ctx, tx, request.ID)
+	if err != nil {
+		s.log.Error("error while selecting sale for update", logger.Error(err))
+		return models.SaleReceipt{}, err
+	}
+
+	// a sale is paid at the till on its way to completed
+	if err = salestatus.Transition(sale.Status, salestatus.Paid); err != nil {
+		return models.SaleReceipt{}, err
+	}
+
+	baskets, err := selectSaleBaskets(ctx, tx, sale.ID)
+	if err != nil {
+		s.log.Error("error while selecting sale baskets", logger.Error(err))
+		return models.SaleReceipt{}, err
+	}
+
+	var (
+		totalPrice float64
+		quantities = make(map[string]int)
+		prices     = make(map[string]float64)
+		unitCosts  = make(map[string]float64)
+		productIDs = []string{}
+	)
+
+	for _, basket := range baskets {
+		totalPrice += basket.Price
+
+		if _, ok := quantities[basket.ProductID]; !ok {
+			productIDs = append(productIDs, basket.ProductID)
+		}
+		quantities[basket.ProductID] += basket.Quantity
+		prices[basket.ProductID] += basket.Price
+	}
+
+	payments, err := salePayments(sale, request.Payments, totalPrice)
+	if err != nil {
+		return models.SaleReceipt{}, err
+	}
+
+	rows, err := tx.Query(ctx, `select id, product_id, count from storage
+	 where deleted_at is null and branch_id = $1 and product_id = any($2)
+	 order by id for update`, sale.BranchID, productIDs)
+	if err != nil {
+		s.log.Error("error while locking storage rows", logger.Error(err))
+		return models.SaleReceipt{}, err
+	}
+
+	storages := make(map[string]models.Storage)
+	for rows.Next() {
+		storageRow := models.Storage{}
+		if err = rows.Scan(&storageRow.ID, &storageRow.ProductID, &storageRow.Count); err != nil {
+			rows.Close()
+			s.log.Error("error while scanning storage row", logger.Error(err))
+			return models.SaleReceipt{}, err
+		}
+
+		if _, ok := storages[storageRow.ProductID]; !ok {
+			storages[storageRow.ProductID] = storageRow
+		}
+	}
+	rows.Close()
+
+	for _, productID := range productIDs {
+		storageRow, ok := storages[productID]
+		if !ok || storageRow.Count < quantities[productID] {
+			err = storage.ErrNotEnoughProduct
+			return models.SaleReceipt{}, err
+		}
+
+		var transactionID string
+		transactionID, err = moveStock(ctx, tx, stockMovement{
+			branchID:        sale.BranchID,
+			staffID:         sale.CashierID,
+			productID:       productID,
+			transactionType: "minus",
+			quantity:        quantities[productID],
+			price:           prices[productID],
+			sourceType:      sourceSale,
+			sourceID:        sale.ID,
+		})
+		if err != nil {
+			s.log.Error("error while taking product from storage", logger.Error(err))
+			return models.SaleReceipt{}, err
+		}
+
+		if unitCosts[productID], err = soldUnitCost(ctx, tx, request.CostMethod, transactionID,
+			sale.BranchID, productID, quantities[productID]); err != nil {
+			s.log.Error("error while costing sold product", logger.Error(err))
+			return models.SaleReceipt{}, err
+		}
+	}
+
+	for i := range baskets {
+		baskets[i].Cost = unitCosts[baskets[i].ProductID] * float64(baskets[i].Quantity)
+
+		if _, err = tx.Exec(ctx, `update basket set cost = $1, updated_at = $2 where id = $3`,
+			baskets[i].Cost, time.Now(), baskets[i].ID); err != nil {
+			s.log.Error("error while snapshotting basket cost", logger.Error(err))
+			return models.SaleReceipt{}, err
+		}
+	}
+
+	for _, payment := range payments {
+		if _, err = tx.Exec(ctx, `insert into sale_payment (
+			id,
+			sale_id,
+			payment_type,
+			amount,
+			tendered,
+			change) values ($1, $2, $3, $4, $5, $6)`,
+			payment.ID,
+			payment.SaleID,
+			payment.PaymentType,
+			payment.Amount,
+			payment.Tendered,
+			payment.Change,
+		); err != nil {
+			s.log.Error("error while inserting sale payment", logger.Error(err))
+			return models.SaleReceipt{}, err
+		}
+
+		receipt.Change += payment.Change
+	}
+
+	if _, err = tx.Exec(ctx, `update sale set status = $1, updated_at = $2 where id = $3`,
+		salestatus.Paid, time.Now(), sale.ID); err != nil {
+		s.log.Error("error while updating sale status", logger.Error(err))
+		return models.SaleReceipt{}, err
+	}
+	sale.Status = salestatus.Paid
+
+	if err = salestatus.Transition(sale.Status, salestatus.Completed); err != nil {
+		return models.SaleReceipt{}, err
+	}
+
+	if err = settleLoyalty(ctx, tx, sale, payments, request.LoyaltyEarnPercent); err != nil {
+		s.log.Error("error while settling loyalty points", logger.Error(err))
+		return models.SaleReceipt{}, err
+	}
+
+	receiptNumber, err := nextReceiptNumber(ctx, tx, sale.BranchID)
+	if err != nil {
+		s.log.Error("error while numbering sale receipt", logger.Error(err))
+		return models.SaleReceipt{}, err
+	}
+
+	if _, err = tx.Exec(ctx, `update sale set price = $1, status = $2, payment_type = $3, receipt_number = $4, updated_at = $5, completed_at = $5 where id = $6`,
+		totalPrice, salestatus.Completed, salePaymentType(sale, payments), receiptNumber, time.Now(), sale.ID); err != nil {
+		s.log.Error("error while updating sale price and status", logger.Error(err))
+		return models.SaleReceipt{}, err
+	}
+
+	for _, staffID := range []string{sale.CashierID, sale.ShopAssistantID} {
+		if staffID == "" {
+			continue
+		}
+
+		if err = creditCommission(ctx, tx, sale, staffID, payments, totalPrice); err != nil {
+			s.log.Error("error while crediting staff commission", logger.Error(err))
+			return models.SaleReceipt{}, err
+		}
+	}
+
+	if err = tx.QueryRow(ctx, `select id, branch_id, shop_assistent_id, cashier_id, payment_type, price, status, client_name, coalesce(customer_id::text, ''), receipt_number, created_at, updated_at
+	 from sale where id = $1`, sale.ID).Scan(
+		&receipt.Sale.ID,
+		&receipt.Sale.BranchID,
+		&receipt.Sale.ShopAssistantID,
+		&receipt.Sale.CashierID,
+		&receipt.Sale.PaymentType,
+		&receipt.Sale.Price,
+		&receipt.Sale.Status,
+		&receipt.Sale.ClientName,
+		&receipt.Sale.CustomerID,
+		&receipt.Sale.ReceiptNumber,
+		&receipt.Sale.CreatedAt,
+		&receipt.Sale.UpdatedAt,
+	); err != nil {
+		s.log.Error("error while selecting completed sale", logger.Error(err))
+		return models.SaleReceipt{}, err
 	}
-	return request.ID, nil
 
+	receipt.Baskets = baskets
+	receipt.Payments = payments
+	receipt.TotalPrice = totalPrice
+
+	return receipt, nil
+}
+
+// selectSaleForUpdate reads the sale and locks its row until tx ends.
+func selectSaleForUpdate(ctx context.Context, tx pgx.Tx, id string) (models.Sale, error) {
+
+	sale := models.Sale{}
+
+	query := `select id, branch_id, shop_assistent_id, cashier_id, payment_type, price, status, client_name, coalesce(customer_id::text, ''), created_at
+	 from sale where deleted_at is null and id = $1`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id})
+
+	if err := tx.QueryRow(ctx, query+condition+` for update`, args...).Scan(
+		&sale.ID,
+		&sale.BranchID,
+		&sale.ShopAssistantID,
+		&sale.CashierID,
+		&sale.PaymentType,
+		&sale.Price,
+		&sale.Status,
+		&sale.ClientName,
+		&sale.CustomerID,
+		&sale.CreatedAt,
+	); err != nil {
+		return models.Sale{}, err
+	}
+
+	return sale, nil
+}
+
+func selectSaleBaskets(ctx context.Context, tx pgx.Tx, saleID string) ([]models.Basket, error) {
+
+	var (
+		updatedAt = sql.NullTime{}
+		baskets   = []models.Basket{}
+	)
+
+	rows, err := tx.Query(ctx, `select 
+	id,
+	sale_id,
+	product_id, 
+	quantity, 
+	price,
+	coalesce(base_price, price),
+	discount,
+	manual_discount_percent,
+	coalesce(cost, 0),
+	created_at, 
+	updated_at
+	from basket where deleted_at is null and sale_id = $1`, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		basket := models.Basket{}
+		if err = rows.Scan(
+			&basket.ID,
+			&basket.SaleID,
+			&basket.ProductID,
+			&basket.Quantity,
+			&basket.Price,
+			&basket.BasePrice,
+			&basket.Discount,
+			&basket.ManualDiscountPercent,
+			&basket.Cost,
+			&basket.CreatedAt,
+			&updatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if updatedAt.Valid {
+			basket.UpdatedAt = updatedAt.Time
+		}
+
+		baskets = append(baskets, basket)
+	}
+
+	return baskets, rows.Err()
+}
+
+// nextReceiptNumber locks the branch row so that completed sales of one branch
+// get gapless, sequential receipt numbers.
+func nextReceiptNumber(ctx context.Context, tx pgx.Tx, branchID string) (int, error) {
+
+	if _, err := tx.Exec(ctx, `select id from branch where id = $1 for update`, branchID); err != nil {
+		return 0, err
+	}
+
+	number := 0
+	if err := tx.QueryRow(ctx, `select coalesce(max(receipt_number), 0) + 1 from sale where branch_id = $1`,
+		branchID).Scan(&number); err != nil {
+		return 0, err
+	}
+
+	return number, nil
+}
+
+// salePayments checks the tenders against the sale total and works out the
+// change given for cash. A sale closed without tenders is paid in full with
+// its own payment type.
+func salePayments(sale models.Sale, tenders []models.CreateSalePayment, totalPrice float64) ([]models.SalePayment, error) {
+
+	if len(tenders) == 0 && totalPrice > 0 {
+		tenders = []models.CreateSalePayment{{
+			PaymentType: sale.PaymentType,
+			Amount:      totalPrice,
+		}}
+	}
+
+	var (
+		paid     float64
+		payments = []models.SalePayment{}
+	)
+
+	for _, tender := range tenders {
+		if tender.Amount <= 0 {
+			return nil, storage.ErrPaymentTotal
+		}
+
+		payment := models.SalePayment{
+			ID:          uuid.New().String(),
+			SaleID:      sale.ID,
+			PaymentType: tender.PaymentType,
+			Amount:      tender.Amount,
+			Tendered:    tender.Amount,
+		}
+
+		switch tender.PaymentType {
+		case "cash":
+			if tender.Tendered != 0 {
+				if tender.Tendered < tender.Amount {
+					return nil, storage.ErrPaymentTotal
+				}
+				payment.Tendered = tender.Tendered
+				payment.Change = tender.Tendered - tender.Amount
+			}
+		case "card", "points":
+		default:
+			return nil, storage.ErrPaymentTotal
+		}
+
+		paid += tender.Amount
+		payments = append(payments, payment)
+	}
+
+	if math.Abs(paid-totalPrice) > 0.005 {
+		return nil, storage.ErrPaymentTotal
+	}
+
+	return payments, nil
+}
+
+// salePaymentType is the single tender type of the sale, or "mixed" when it
+// was paid with several kinds of tender.
+func salePaymentType(sale models.Sale, payments []models.SalePayment) string {
+
+	if len(payments) == 0 {
+		return sale.PaymentType
+	}
+
+	paymentType := payments[0].PaymentType
+	for _, payment := range payments {
+		if payment.PaymentType != paymentType {
+			return "mixed"
+		}
+	}
+
+	return paymentType
+}
+
+// creditCommission adds the staff's tarif commission for the sale to their
+// balance and records it in transactions. The commission is split by tender:
+// card tenders use AmountForCard and cash and points tenders AmountForCash,
+// with fixed tarifs shared out by each tender's part of the total.
+func creditCommission(ctx context.Context, tx pgx.Tx, sale models.Sale, staffID string, payments []models.SalePayment, totalPrice float64) error {
+
+	tarif := models.Tarif{}
+
+	if err := tx.QueryRow(ctx, `select t.tarif_type, t.amount_for_cash, t.amount_for_card
+	 from staff s join tarif t on t.id = s.tarif_id
+	 where s.id = $1`, staffID).Scan(
+		&tarif.TarifType,
+		&tarif.AmountForCash,
+		&tarif.AmountForCard,
+	); err != nil {
+		return err
+	}
+
+	var amount float64
+	for _, payment := range payments {
+		rate := tarif.AmountForCash
+		if payment.PaymentType == "card" {
+			rate = tarif.AmountForCard
+		}
+
+		if tarif.TarifType == "fixed" {
+			amount += rate * payment.Amount / totalPrice
+		} else {
+			amount += rate * payment.Amount
+		}
+	}
+
+	if amount == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, `update staff set balance = balance + $1, updated_at = $2 where id = $3`,
+		amount, time.Now(), staffID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `insert into transactions (
+		id, 
+		sale_id, 
+		staff_id, 
+		transaction_type,
+		source_type, 
+		amount, 
+		description) 
+	values 
+	($1, $2, $3, $4, $5, $6, $7)`,
+		uuid.New(),
+		sale.ID,
+		staffID,
+		"topup",
+		"sales",
+		amount,
+		"commission for sale",
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CancelSale marks the sale cancelled. For a completed sale it also returns
+// the quantities not returned yet to the branch storage, withdraws the
+// commissions credited for it and undoes its loyalty points, all in one
+// transaction.
+func (s *saleRepo) CancelSale(ctx context.Context, request models.CancelSale) (err error) {
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("error while starting cancel sale transaction", logger.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	sale, err := selectSaleForUpdate(ctx, tx, request.ID)
+	if err != nil {
+		s.log.Error("error while selecting sale for update", logger.Error(err))
+		return err
+	}
+
+	if err = salestatus.Transition(sale.Status, salestatus.Cancelled); err != nil {
+		return err
+	}
+
+	if sale.Status == salestatus.Completed {
+		baskets, err := selectSaleBaskets(ctx, tx, sale.ID)
+		if err != nil {
+			s.log.Error("error while selecting sale baskets", logger.Error(err))
+			return err
+		}
+
+		for _, basket := range baskets {
+			var returned int
+			if err = tx.QueryRow(ctx, `select coalesce(sum(rp.quantity), 0)
+			 from sale_return_product rp join sale_return r on r.id = rp.sale_return_id
+			 where rp.deleted_at is null and r.deleted_at is null and rp.basket_id = $1`, basket.ID).Scan(&returned); err != nil {
+				s.log.Error("error while selecting returned quantity", logger.Error(err))
+				return err
+			}
+
+			quantity := basket.Quantity - returned
+			if quantity <= 0 {
+				continue
+			}
+
+			price := basket.Price / float64(basket.Quantity) * float64(quantity)
+			if _, err = moveStock(ctx, tx, stockMovement{
+				branchID:        sale.BranchID,
+				staffID:         request.StaffID,
+				productID:       basket.ProductID,
+				transactionType: "plus",
+				quantity:        quantity,
+				price:           price,
+				sourceType:      sourceSale,
+				sourceID:        sale.ID,
+				reason:          "sale cancelled",
+				originType:      sourceSale,
+				originID:        sale.ID,
+			}); err != nil {
+				s.log.Error("error while returning product to storage", logger.Error(err))
+				return err
+			}
+		}
+
+		if err = reverseCommissions(ctx, tx, sale.ID, "commission reversal for cancelled sale"); err != nil {
+			s.log.Error("error while reversing staff commissions", logger.Error(err))
+			return err
+		}
+
+		if _, err = reverseLoyalty(ctx, tx, sale.ID, 1, true); err != nil {
+			s.log.Error("error while reversing loyalty points", logger.Error(err))
+			return err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `update sale set status = $1, updated_at = $2 where id = $3`,
+		salestatus.Cancelled, time.Now(), sale.ID); err != nil {
+		s.log.Error("error while updating sale status", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// reverseCommissions withdraws from staff balances every sales commission
+// credited for the sale.
+func reverseCommissions(ctx context.Context, tx pgx.Tx, saleID, description string) error {
+
+	type commission struct {
+		staffID string
+		amount  float64
+	}
+
+	rows, err := tx.Query(ctx, `select staff_id, sum(case when transaction_type = 'topup' then amount else -amount end)
+	 from transactions
+	 where deleted_at is null and sale_id = $1 and source_type = 'sales'
+	 group by staff_id`, saleID)
+	if err != nil {
+		return err
+	}
+
+	commissions := []commission{}
+	for rows.Next() {
+		c := commission{}
+		if err = rows.Scan(&c.staffID, &c.amount); err != nil {
+			rows.Close()
+			return err
+		}
+		commissions = append(commissions, c)
+	}
+	rows.Close()
+
+	for _, c := range commissions {
+		if c.amount <= 0 {
+			continue
+		}
+
+		if err = withdrawCommission(ctx, tx, saleID, c.staffID, c.amount, description); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func withdrawCommission(ctx context.Context, tx pgx.Tx, saleID, staffID string, amount float64, description string) error {
+
+	if _, err := tx.Exec(ctx, `update staff set balance = balance - $1, updated_at = $2 where id = $3`,
+		amount, time.Now(), staffID); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(ctx, `insert into transactions (
+		id, 
+		sale_id, 
+		staff_id, 
+		transaction_type,
+		source_type, 
+		amount, 
+		description) 
+	values 
+	($1, $2, $3, $4, $5, $6, $7)`,
+		uuid.New(),
+		saleID,
+		staffID,
+		"withdraw",
+		"sales",
+		amount,
+		description,
+	)
+
+	return err
+}
+
+func (s *saleRepo) GetPayments(ctx context.Context, saleID string) ([]models.SalePayment, error) {
+
+	payments := []models.SalePayment{}
+
+	query := `select p.id, p.sale_id, p.payment_type, p.amount, p.tendered, p.change, p.created_at
+	 from sale_payment p join sale s on s.id = p.sale_id
+	 where p.deleted_at is null and p.sale_id = $1`
+
+	condition, args := branchCondition(ctx, "s.branch_id", []interface{}{saleID})
+
+	rows, err := s.pool.Query(ctx, query+condition+` order by p.created_at`, args...)
+	if err != nil {
+		s.log.Error("error while selecting sale payments", logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		payment := models.SalePayment{}
+		if err = rows.Scan(
+			&payment.ID,
+			&payment.SaleID,
+			&payment.PaymentType,
+			&payment.Amount,
+			&payment.Tendered,
+			&payment.Change,
+			&payment.CreatedAt,
+		); err != nil {
+			s.log.Error("error while scanning sale payment", logger.Error(err))
+			return nil, err
+		}
+
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// GetReceipt assembles the printable receipt of a sale: its basket lines with
+// product names and barcodes, payments, branch and cashier. Only a sale that
+// was completed has a receipt, one cancelled or refunded since keeps it with
+// its status.
+func (s *saleRepo) GetReceipt(ctx context.Context, saleID string) (models.Receipt, error) {
+
+	var (
+		receipt     = models.Receipt{}
+		cashierName = sql.NullString{}
+	)
+
+	query := `select
+	s.id,
+	coalesce(s.receipt_number, 0),
+	s.status,
+	b.name,
+	b.address,
+	st.name,
+	s.client_name,
+	s.created_at
+	from sale s
+	join branch b on b.id = s.branch_id
+	left join staff st on st.id = s.cashier_id
+	where s.deleted_at is null and s.id = $1`
+
+	condition, args := branchCondition(ctx, "s.branch_id", []interface{}{saleID})
+
+	if err := s.pool.QueryRow(ctx, query+condition, args...).Scan(
+		&receipt.SaleID,
+		&receipt.ReceiptNumber,
+		&receipt.Status,
+		&receipt.BranchName,
+		&receipt.BranchAddress,
+		&cashierName,
+		&receipt.ClientName,
+		&receipt.CreatedAt,
+	); err != nil {
+		s.log.Error("error while selecting sale receipt", logger.Error(err))
+		return models.Receipt{}, err
+	}
+
+	// the receipt number is given when the sale is completed
+	if receipt.ReceiptNumber == 0 {
+		return models.Receipt{}, storage.ErrNoReceipt
+	}
+
+	receipt.CashierName = cashierName.String
+
+	rows, err := s.pool.Query(ctx, `select bs.product_id, p.name, p.barcode, bs.quantity, bs.price
+	 from basket bs join product p on p.id = bs.product_id
+	 where bs.deleted_at is null and bs.sale_id = $1
+	 order by bs.created_at`, saleID)
+	if err != nil {
+		s.log.Error("error while selecting receipt lines", logger.Error(err))
+		return models.Receipt{}, err
+	}
+
+	for rows.Next() {
+		line := models.ReceiptLine{}
+		if err = rows.Scan(
+			&line.ProductID,
+			&line.ProductName,
+			&line.Barcode,
+			&line.Quantity,
+			&line.Price,
+		); err != nil {
+			rows.Close()
+			s.log.Error("error while scanning receipt line", logger.Error(err))
+			return models.Receipt{}, err
+		}
+
+		if line.Quantity != 0 {
+			line.UnitPrice = line.Price / float64(line.Quantity)
+		}
+
+		receipt.TotalPrice += line.Price
+		receipt.Lines = append(receipt.Lines, line)
+	}
+	rows.Close()
+
+	payments, err := s.GetPayments(ctx, saleID)
+	if err != nil {
+		return models.Receipt{}, err
+	}
+
+	for _, payment := range payments {
+		receipt.Change += payment.Change
+	}
+
+	receipt.Payments = payments
+
+	return receipt, nil
 }
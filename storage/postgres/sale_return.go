@@ -0,0 +1,423 @@
+package postgres
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/pkg/salestatus"
+	"bazaar/storage"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type saleReturnRepo struct {
+	pool *pgxpool.Pool
+	log  logger.ILogger
+}
+
+func NewSaleReturnRepo(pool *pgxpool.Pool, log logger.ILogger) storage.ISaleReturnRepo {
+	return &saleReturnRepo{
+		pool: pool,
+		log:  log,
+	}
+}
+
+// Create records a return against a completed sale in one transaction: it
+// checks the lines against what was sold, restocks the sale's branch, stores
+// the refund and withdraws the matching share of staff commissions.
+func (s *saleReturnRepo) Create(ctx context.Context, request models.CreateSaleReturn) (id string, err error) {
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("error while starting sale return transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	sale, err := selectSaleForUpdate(ctx, tx, request.SaleID)
+	if err != nil {
+		s.log.Error("error while selecting sale for update", logger.Error(err))
+		return "", err
+	}
+
+	if err = salestatus.CanReturn(sale.Status); err != nil {
+		return "", err
+	}
+
+	if len(request.Products) == 0 {
+		err = storage.ErrReturnQuantity
+		return "", err
+	}
+
+	var (
+		returnID   = uuid.New().String()
+		amount     float64
+		saleTotal  float64
+		soldCount  int
+		lines      = []models.SaleReturnProduct{}
+		returnedBy = make(map[string]int)
+	)
+
+	if err = tx.QueryRow(ctx, `select coalesce(sum(price), 0), coalesce(sum(quantity), 0)
+	 from basket where deleted_at is null and sale_id = $1`, sale.ID).Scan(&saleTotal, &soldCount); err != nil {
+		s.log.Error("error while selecting sale totals", logger.Error(err))
+		return "", err
+	}
+
+	for _, product := range request.Products {
+		basket := models.Basket{}
+
+		if err = tx.QueryRow(ctx, `select id, product_id, quantity, price
+		 from basket where deleted_at is null and id = $1 and sale_id = $2`, product.BasketID, sale.ID).Scan(
+			&basket.ID,
+			&basket.ProductID,
+			&basket.Quantity,
+			&basket.Price,
+		); err != nil {
+			s.log.Error("error while selecting returned basket", logger.Error(err))
+			return "", err
+		}
+
+		var returned int
+		if err = tx.QueryRow(ctx, `select coalesce(sum(rp.quantity), 0)
+		 from sale_return_product rp join sale_return r on r.id = rp.sale_return_id
+		 where rp.deleted_at is null and r.deleted_at is null and rp.basket_id = $1`, basket.ID).Scan(&returned); err != nil {
+			s.log.Error("error while selecting returned quantity", logger.Error(err))
+			return "", err
+		}
+
+		returned += returnedBy[basket.ID]
+
+		if product.Quantity <= 0 || returned+product.Quantity > basket.Quantity {
+			err = storage.ErrReturnQuantity
+			return "", err
+		}
+
+		returnedBy[basket.ID] += product.Quantity
+
+		price := basket.Price / float64(basket.Quantity) * float64(product.Quantity)
+		amount += price
+
+		lines = append(lines, models.SaleReturnProduct{
+			ID:           uuid.New().String(),
+			SaleReturnID: returnID,
+			BasketID:     basket.ID,
+			ProductID:    basket.ProductID,
+			Quantity:     product.Quantity,
+			Price:        price,
+		})
+	}
+
+	// The share of the points tender in the returned value goes back to the
+	// customer as points, the rest is refunded in the return's payment type.
+	var points float64
+	if saleTotal > 0 {
+		if err = withdrawCommissionShare(ctx, tx, sale.ID, amount/saleTotal); err != nil {
+			s.log.Error("error while withdrawing staff commissions", logger.Error(err))
+			return "", err
+		}
+
+		if points, err = reverseLoyalty(ctx, tx, sale.ID, amount/saleTotal, false); err != nil {
+			s.log.Error("error while reversing loyalty points", logger.Error(err))
+			return "", err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `insert into sale_return (
+		id,
+		sale_id,
+		staff_id,
+		payment_type,
+		amount,
+		points,
+		reason) values ($1, $2, $3, $4, $5, $6, $7)`,
+		returnID,
+		sale.ID,
+		request.StaffID,
+		request.PaymentType,
+		amount,
+		points,
+		request.Reason,
+	); err != nil {
+		s.log.Error("error while inserting sale return", logger.Error(err))
+		return "", err
+	}
+
+	for _, line := range lines {
+		if _, err = tx.Exec(ctx, `insert into sale_return_product (
+			id,
+			sale_return_id,
+			basket_id,
+			product_id,
+			quantity,
+			price) values ($1, $2, $3, $4, $5, $6)`,
+			line.ID,
+			line.SaleReturnID,
+			line.BasketID,
+			line.ProductID,
+			line.Quantity,
+			line.Price,
+		); err != nil {
+			s.log.Error("error while inserting sale return product", logger.Error(err))
+			return "", err
+		}
+
+		if _, err = moveStock(ctx, tx, stockMovement{
+			branchID:        sale.BranchID,
+			staffID:         request.StaffID,
+			productID:       line.ProductID,
+			transactionType: "plus",
+			quantity:        line.Quantity,
+			price:           line.Price,
+			sourceType:      sourceSaleReturn,
+			sourceID:        returnID,
+			reason:          request.Reason,
+			originType:      sourceSale,
+			originID:        sale.ID,
+		}); err != nil {
+			s.log.Error("error while returning product to storage", logger.Error(err))
+			return "", err
+		}
+	}
+
+	var totalReturned int
+	if err = tx.QueryRow(ctx, `select coalesce(sum(rp.quantity), 0)
+	 from sale_return_product rp join sale_return r on r.id = rp.sale_return_id
+	 where rp.deleted_at is null and r.deleted_at is null and r.sale_id = $1`, sale.ID).Scan(&totalReturned); err != nil {
+		s.log.Error("error while selecting total returned quantity", logger.Error(err))
+		return "", err
+	}
+
+	if totalReturned >= soldCount {
+		if err = salestatus.Transition(sale.Status, salestatus.Refunded); err != nil {
+			return "", err
+		}
+
+		if _, err = tx.Exec(ctx, `update sale set status = $1, updated_at = $2 where id = $3`,
+			salestatus.Refunded, time.Now(), sale.ID); err != nil {
+			s.log.Error("error while updating sale status", logger.Error(err))
+			return "", err
+		}
+	}
+
+	return returnID, nil
+}
+
+func (s *saleReturnRepo) Get(ctx context.Context, id models.PrimaryKey) (models.SaleReturn, error) {
+
+	var (
+		updatedAt  = sql.NullTime{}
+		reason     = sql.NullString{}
+		saleReturn = models.SaleReturn{}
+	)
+
+	query := `select
+	r.id,
+	r.sale_id,
+	r.staff_id,
+	r.payment_type,
+	r.amount,
+	r.points,
+	r.reason,
+	r.created_at,
+	r.updated_at
+	from sale_return r join sale s on s.id = r.sale_id
+	where r.deleted_at is null and r.id = $1`
+
+	condition, args := branchCondition(ctx, "s.branch_id", []interface{}{id.ID})
+
+	if err := s.pool.QueryRow(ctx, query+condition, args...).Scan(
+		&saleReturn.ID,
+		&saleReturn.SaleID,
+		&saleReturn.StaffID,
+		&saleReturn.PaymentType,
+		&saleReturn.Amount,
+		&saleReturn.Points,
+		&reason,
+		&saleReturn.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		s.log.Error("error while selecting sale return", logger.Error(err))
+		return models.SaleReturn{}, err
+	}
+
+	if updatedAt.Valid {
+		saleReturn.UpdatedAt = updatedAt.Time
+	}
+
+	saleReturn.Reason = reason.String
+
+	products, err := s.getProducts(ctx, saleReturn.ID)
+	if err != nil {
+		s.log.Error("error while selecting sale return products", logger.Error(err))
+		return models.SaleReturn{}, err
+	}
+
+	saleReturn.Products = products
+
+	return saleReturn, nil
+}
+
+func (s *saleReturnRepo) GetList(ctx context.Context, request models.GetSaleReturnsListRequest) (models.SaleReturnsResponse, error) {
+
+	var (
+		updatedAt   = sql.NullTime{}
+		reason      = sql.NullString{}
+		saleReturns = []models.SaleReturn{}
+		count       = 0
+		offset      = (request.Page - 1) * request.Limit
+	)
+
+	condition, args := branchCondition(ctx, "s.branch_id", []interface{}{request.SaleID})
+
+	countQuery := `select count(1) from sale_return r join sale s on s.id = r.sale_id
+	 where r.deleted_at is null and r.sale_id = $1` + condition
+
+	if err := s.pool.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
+		s.log.Error("error while selecting sale return count", logger.Error(err))
+		return models.SaleReturnsResponse{}, err
+	}
+
+	query := `select
+	r.id,
+	r.sale_id,
+	r.staff_id,
+	r.payment_type,
+	r.amount,
+	r.points,
+	r.reason,
+	r.created_at,
+	r.updated_at
+	from sale_return r join sale s on s.id = r.sale_id
+	where r.deleted_at is null and r.sale_id = $1` + condition + ` order by r.created_at`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+
+	rows, err := s.pool.Query(ctx, query, append(args, request.Limit, offset)...)
+	if err != nil {
+		s.log.Error("error while selecting sale returns", logger.Error(err))
+		return models.SaleReturnsResponse{}, err
+	}
+
+	for rows.Next() {
+		saleReturn := models.SaleReturn{}
+		if err = rows.Scan(
+			&saleReturn.ID,
+			&saleReturn.SaleID,
+			&saleReturn.StaffID,
+			&saleReturn.PaymentType,
+			&saleReturn.Amount,
+			&saleReturn.Points,
+			&reason,
+			&saleReturn.CreatedAt,
+			&updatedAt,
+		); err != nil {
+			rows.Close()
+			s.log.Error("error while scanning sale return", logger.Error(err))
+			return models.SaleReturnsResponse{}, err
+		}
+
+		if updatedAt.Valid {
+			saleReturn.UpdatedAt = updatedAt.Time
+		}
+
+		saleReturn.Reason = reason.String
+
+		saleReturns = append(saleReturns, saleReturn)
+	}
+	rows.Close()
+
+	for i := range saleReturns {
+		products, err := s.getProducts(ctx, saleReturns[i].ID)
+		if err != nil {
+			s.log.Error("error while selecting sale return products", logger.Error(err))
+			return models.SaleReturnsResponse{}, err
+		}
+		saleReturns[i].Products = products
+	}
+
+	return models.SaleReturnsResponse{
+		SaleReturns: saleReturns,
+		Count:       count,
+	}, nil
+}
+
+func (s *saleReturnRepo) getProducts(ctx context.Context, saleReturnID string) ([]models.SaleReturnProduct, error) {
+
+	products := []models.SaleReturnProduct{}
+
+	rows, err := s.pool.Query(ctx, `select id, sale_return_id, basket_id, product_id, quantity, price
+	 from sale_return_product where deleted_at is null and sale_return_id = $1`, saleReturnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product := models.SaleReturnProduct{}
+		if err = rows.Scan(
+			&product.ID,
+			&product.SaleReturnID,
+			&product.BasketID,
+			&product.ProductID,
+			&product.Quantity,
+			&product.Price,
+		); err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+// withdrawCommissionShare withdraws the given share of every sales commission
+// credited for the sale.
+func withdrawCommissionShare(ctx context.Context, tx pgx.Tx, saleID string, share float64) error {
+
+	type commission struct {
+		staffID string
+		amount  float64
+	}
+
+	rows, err := tx.Query(ctx, `select staff_id, sum(amount)
+	 from transactions
+	 where deleted_at is null and sale_id = $1 and source_type = 'sales' and transaction_type = 'topup'
+	 group by staff_id`, saleID)
+	if err != nil {
+		return err
+	}
+
+	commissions := []commission{}
+	for rows.Next() {
+		c := commission{}
+		if err = rows.Scan(&c.staffID, &c.amount); err != nil {
+			rows.Close()
+			return err
+		}
+		commissions = append(commissions, c)
+	}
+	rows.Close()
+
+	for _, c := range commissions {
+		if amount := c.amount * share; amount > 0 {
+			if err = withdrawCommission(ctx, tx, saleID, c.staffID, amount, "commission reversal for sale return"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
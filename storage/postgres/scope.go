@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"bazaar/pkg/scope"
+	"bazaar/storage"
+	"context"
+	"fmt"
+)
+
+// branchCondition returns a branch_id filter for the next placeholder when
+// ctx is bound to a branch, together with args extended by its value.
+func branchCondition(ctx context.Context, column string, args []interface{}) (string, []interface{}) {
+	branchID := scope.BranchID(ctx)
+	if branchID == "" {
+		return "", args
+	}
+
+	args = append(args, branchID)
+
+	return fmt.Sprintf(` and %s = $%d`, column, len(args)), args
+}
+
+// scopedBranchID returns the branch a new row should be written to. A caller
+// bound to a branch can't write rows for another one.
+func scopedBranchID(ctx context.Context, branchID string) (string, error) {
+	scopedID := scope.BranchID(ctx)
+	if scopedID == "" {
+		return branchID, nil
+	}
+
+	if branchID != "" && branchID != scopedID {
+		return "", storage.ErrBranchAccessDenied
+	}
+
+	return scopedID, nil
+}
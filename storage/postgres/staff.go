@@ -4,6 +4,7 @@ import (
 	"bazaar/api/models"
 	"bazaar/pkg/check"
 	"bazaar/pkg/logger"
+	"bazaar/pkg/security"
 	"bazaar/storage"
 	"context"
 	"database/sql"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -30,6 +32,18 @@ func (s *staffRepo) Create(ctx context.Context, request models.CreateStaff) (str
 
 	id := uuid.New()
 
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		s.log.Error("error while checking staff branch", logger.Error(err))
+		return "", err
+	}
+
+	hashedPassword, err := security.HashPassword(request.Password)
+	if err != nil {
+		s.log.Error("error while hashing staff password", logger.Error(err))
+		return "", err
+	}
+
 	query := `insert into staff (
 		id, 
 		branch_id, 
@@ -43,9 +57,9 @@ func (s *staffRepo) Create(ctx context.Context, request models.CreateStaff) (str
 		login, 
 		password) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
 
-	_, err := s.pool.Exec(ctx, query,
+	_, err = s.pool.Exec(ctx, query,
 		id,
-		request.BranchID,
+		branchID,
 		request.TarifID,
 		request.TypeStaff,
 		request.Name,
@@ -54,7 +68,7 @@ func (s *staffRepo) Create(ctx context.Context, request models.CreateStaff) (str
 		check.CalculateAge(request.BirthDate),
 		request.Gender,
 		request.Login,
-		request.Password,
+		hashedPassword,
 	)
 	if err != nil {
 		s.log.Error("error while inserting staff data", logger.Error(err))
@@ -81,11 +95,12 @@ func (s *staffRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Staff
 	gender, 
 	login, 
 	balance,
-	password, 
 	created_at, 
 	updated_at from staff where deleted_at is null and id = $1`
 
-	row := s.pool.QueryRow(ctx, query, id.ID)
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id.ID})
+
+	row := s.pool.QueryRow(ctx, query+condition, args...)
 
 	err := row.Scan(
 		&staff.ID,
@@ -98,7 +113,6 @@ func (s *staffRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Staff
 		&staff.Gender,
 		&staff.Login,
 		&staff.Balance,
-		&staff.Password,
 		&staff.CreatedAt,
 		&updatedAt,
 	)
@@ -126,12 +140,14 @@ func (s *staffRepo) GetList(ctx context.Context, request models.GetListRequest)
 		search            = request.Search
 	)
 
-	countQuery = `select count(1) from staff where deleted_at is null `
+	condition, args := branchCondition(ctx, "branch_id", nil)
+
+	countQuery = `select count(1) from staff where deleted_at is null` + condition
 
 	if search != "" {
-		countQuery += fmt.Sprintf(`and name ilike '%%%s%%' or login ilike '%%%s%%' `, search, search)
+		countQuery += fmt.Sprintf(` and (name ilike '%%%s%%' or login ilike '%%%s%%')`, search, search)
 	}
-	if err := s.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+	if err := s.pool.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting staff count", logger.Error(err))
 		return models.StaffsResponse{}, err
 	}
@@ -147,16 +163,15 @@ func (s *staffRepo) GetList(ctx context.Context, request models.GetListRequest)
 	gender, 
 	login,
 	balance, 
-	password, 
 	created_at, 
-	updated_at from staff where deleted_at is null`
+	updated_at from staff where deleted_at is null` + condition
 
 	if search != "" {
-		query += fmt.Sprintf(` and name ilike '%%%s%%' or login ilike '%%%s%%' `, search, search)
+		query += fmt.Sprintf(` and (name ilike '%%%s%%' or login ilike '%%%s%%')`, search, search)
 	}
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := s.pool.Query(ctx, query, request.Limit, offset)
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := s.pool.Query(ctx, query, append(args, request.Limit, offset)...)
 	if err != nil {
 		fmt.Println("error is while selecting staff", logger.Error(err))
 		return models.StaffsResponse{}, err
@@ -175,7 +190,6 @@ func (s *staffRepo) GetList(ctx context.Context, request models.GetListRequest)
 			&staff.Gender,
 			&staff.Login,
 			&staff.Balance,
-			&staff.Password,
 			&staff.CreatedAt,
 			&updatedAt,
 		); err != nil {
@@ -199,6 +213,12 @@ func (s *staffRepo) GetList(ctx context.Context, request models.GetListRequest)
 
 func (s *staffRepo) Update(ctx context.Context, request models.UpdateStaff) (string, error) {
 
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		s.log.Error("error while checking staff branch", logger.Error(err))
+		return "", err
+	}
+
 	query := `update staff
    set 
    branch_id = $1, 
@@ -209,13 +229,12 @@ func (s *staffRepo) Update(ctx context.Context, request models.UpdateStaff) (str
    age = $6, 
    gender = $7, 
    login = $8, 
-   password = $9,
-   balance = $10, 
-   updated_at = $11
-   where id = $12
-   `
-	_, err := s.pool.Exec(ctx, query,
-		request.BranchID,
+   balance = $9, 
+   updated_at = $10
+   where deleted_at is null and id = $11`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{
+		branchID,
 		request.TarifID,
 		request.TypeStaff,
 		request.Name,
@@ -223,15 +242,21 @@ func (s *staffRepo) Update(ctx context.Context, request models.UpdateStaff) (str
 		check.CalculateAge(request.BirthDate),
 		request.Gender,
 		request.Login,
-		request.Password,
 		request.Balance,
 		time.Now(),
 		request.ID,
-	)
+	})
+
+	tag, err := s.pool.Exec(ctx, query+condition, args...)
 	if err != nil {
 		s.log.Error("error while updating staff data...", logger.Error(err))
 		return "", err
 	}
+
+	if tag.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return request.ID, nil
 }
 
@@ -240,14 +265,20 @@ func (s *staffRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update staff
 	 set deleted_at = $1
-	  where id = $2
-	`
+	  where deleted_at is null and id = $2`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{time.Now(), id})
 
-	_, err := s.pool.Exec(ctx, query, time.Now(), id)
+	tag, err := s.pool.Exec(ctx, query+condition, args...)
 	if err != nil {
 		s.log.Error("error while deleting staff by id", logger.Error(err))
 		return err
 	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
 
@@ -272,3 +303,151 @@ func (s *staffRepo) UpdateStaffBalance(ctx context.Context, request models.Updat
 	return nil
 
 }
+
+func (s *staffRepo) GetByLogin(ctx context.Context, login string) (models.Staff, error) {
+
+	var updatedAt = sql.NullTime{}
+
+	staff := models.Staff{}
+
+	query := `select 
+	id, 
+	branch_id, 
+	tarif_id, 
+	type_staff, 
+	name, 
+	birth_date::text, 
+	age, 
+	gender, 
+	login, 
+	balance,
+	password, 
+	created_at, 
+	updated_at from staff where deleted_at is null and login = $1`
+
+	row := s.pool.QueryRow(ctx, query, login)
+
+	err := row.Scan(
+		&staff.ID,
+		&staff.BranchID,
+		&staff.TarifID,
+		&staff.TypeStaff,
+		&staff.Name,
+		&staff.BirthDate,
+		&staff.Age,
+		&staff.Gender,
+		&staff.Login,
+		&staff.Balance,
+		&staff.Password,
+		&staff.CreatedAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		s.log.Error("error while selecting staff by login", logger.Error(err))
+		return models.Staff{}, err
+	}
+
+	if updatedAt.Valid {
+		staff.UpdatedAt = updatedAt.Time
+	}
+
+	return staff, nil
+}
+
+func (s *staffRepo) GetPassword(ctx context.Context, id string) (string, error) {
+
+	var password string
+
+	query := `select password from staff where deleted_at is null and id = $1`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id})
+
+	if err := s.pool.QueryRow(ctx, query+condition, args...).Scan(&password); err != nil {
+		s.log.Error("error while selecting staff password", logger.Error(err))
+		return "", err
+	}
+
+	return password, nil
+}
+
+func (s *staffRepo) UpdatePassword(ctx context.Context, id, password string) error {
+
+	hashedPassword, err := security.HashPassword(password)
+	if err != nil {
+		s.log.Error("error while hashing staff password", logger.Error(err))
+		return err
+	}
+
+	query := `update staff
+	 set 
+	 password = $1,
+	 updated_at = $2
+	 where deleted_at is null and id = $3`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{hashedPassword, time.Now(), id})
+
+	tag, err := s.pool.Exec(ctx, query+condition, args...)
+	if err != nil {
+		s.log.Error("error while updating staff password", logger.Error(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (s *staffRepo) RehashPasswords(ctx context.Context) (int, error) {
+
+	var (
+		plainPasswords = make(map[string]string)
+		rehashed       = 0
+	)
+
+	rows, err := s.pool.Query(ctx, `select id, password from staff`)
+	if err != nil {
+		s.log.Error("error while selecting staff passwords", logger.Error(err))
+		return 0, err
+	}
+
+	for rows.Next() {
+		var id, password string
+		if err = rows.Scan(&id, &password); err != nil {
+			rows.Close()
+			s.log.Error("error while scanning staff password", logger.Error(err))
+			return 0, err
+		}
+
+		if !security.IsHashed(password) {
+			plainPasswords[id] = password
+		}
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		s.log.Error("error while reading staff passwords", logger.Error(err))
+		return 0, err
+	}
+
+	for id, password := range plainPasswords {
+		hashedPassword, err := security.HashPassword(password)
+		if err != nil {
+			s.log.Error("error while hashing staff password", logger.Error(err))
+			return rehashed, err
+		}
+
+		query := `update staff set password = $1 where id = $2 and password = $3`
+
+		if _, err = s.pool.Exec(ctx, query, hashedPassword, id, password); err != nil {
+			s.log.Error("error while rehashing staff password", logger.Error(err))
+			return rehashed, err
+		}
+
+		rehashed++
+	}
+
+	return rehashed, nil
+}
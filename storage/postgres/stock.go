@@ -0,0 +1,397 @@
+package postgres
+
+import (
+	"bazaar/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Documents a storage transaction can come from.
+const (
+	sourceSale           = "sale"
+	sourceSaleReturn     = "sale_return"
+	sourceIncome         = "income"
+	sourceTransfer       = "transfer"
+	sourceInventoryCount = "inventory_count"
+	sourceAdjustment     = "adjustment"
+)
+
+// Ways a sold unit can be costed.
+const (
+	costAverage = "average"
+	costFIFO    = "fifo"
+)
+
+// stockMovement is a change of one product's count at a branch caused by a
+// source document. Price is the value of the whole quantity. A plus movement
+// naming an origin document puts stock back into the batches that document's
+// minus movements took it from, expiresAt dates the batch of anything else it
+// brings in.
+type stockMovement struct {
+	branchID        string
+	staffID         string
+	productID       string
+	transactionType string
+	quantity        int
+	price           float64
+	sourceType      string
+	sourceID        string
+	reason          string
+	originType      string
+	originID        string
+	expiresAt       *time.Time
+}
+
+// moveStock applies the movement to the branch storage and records it in the
+// storage transaction ledger with the count left after it, returning the
+// ledger row id. A plus movement
+// creates the storage row on the first receipt, a minus movement fails with
+// ErrNotEnoughProduct rather than take the count below zero. The branch
+// batches follow the storage count, see takeBatches and putBatches.
+func moveStock(ctx context.Context, tx pgx.Tx, movement stockMovement) (string, error) {
+
+	var (
+		id      = uuid.New().String()
+		balance int
+		err     error
+	)
+
+	if movement.transactionType == "minus" {
+		err = tx.QueryRow(ctx, `update storage set count = count - $1, updated_at = $2
+		 where deleted_at is null and branch_id = $3 and product_id = $4 and count >= $1 returning count`,
+			movement.quantity, time.Now(), movement.branchID, movement.productID).Scan(&balance)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", storage.ErrNotEnoughProduct
+		}
+	} else {
+		err = tx.QueryRow(ctx, `insert into storage (id, product_id, branch_id, count) values ($1, $2, $3, $4)
+		 on conflict (branch_id, product_id) where deleted_at is null
+		 do update set count = storage.count + excluded.count, updated_at = $5 returning count`,
+			uuid.New(), movement.productID, movement.branchID, movement.quantity, time.Now()).Scan(&balance)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = tx.Exec(ctx, `insert into storage_transaction (
+		id,
+		staff_id,
+		product_id,
+		storage_transaction_type,
+		price,
+		quantity,
+		branch_id,
+		source_type,
+		source_id,
+		reason,
+		balance_after) values ($1, nullif($2, ''), $3, $4, $5, $6, $7, $8, nullif($9, '')::uuid, nullif($10, ''), $11)`,
+		id,
+		movement.staffID,
+		movement.productID,
+		movement.transactionType,
+		movement.price,
+		movement.quantity,
+		movement.branchID,
+		movement.sourceType,
+		movement.sourceID,
+		movement.reason,
+		balance,
+	); err != nil {
+		return "", err
+	}
+
+	if movement.transactionType == "minus" {
+		err = takeBatches(ctx, tx, id, movement)
+	} else {
+		err = putBatches(ctx, tx, id, movement)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if movement.sourceType == sourceSale && movement.transactionType == "minus" {
+		if err = raiseStockAlert(ctx, tx, id, balance, movement); err != nil {
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+// raiseStockAlert queues a low stock alert when a sale takes the product from
+// its minimum or above to below it. The alert is written in the sale's
+// transaction, so it is there exactly when the sale is.
+func raiseStockAlert(ctx context.Context, tx pgx.Tx, transactionID string, balance int, movement stockMovement) error {
+
+	_, err := tx.Exec(ctx, `insert into stock_alert (id, storage_transaction_id, branch_id, product_id, sale_id, count, min_count, reorder_count)
+	 select $1, $2, s.branch_id, s.product_id, nullif($3, '')::uuid, $4, s.min_count, s.reorder_count from storage s
+	 where s.deleted_at is null and s.branch_id = $5 and s.product_id = $6 and $4 < s.min_count and $4 + $7 >= s.min_count`,
+		uuid.New(), transactionID, movement.sourceID, balance, movement.branchID, movement.productID, movement.quantity)
+
+	return err
+}
+
+// takeBatches takes a minus movement from the branch batches of the product
+// first expired first out, batches without an expiry date going last. A minus
+// movement naming an origin document, as when a receipt is corrected, takes
+// from the batches that document brought in first. Stock counted before
+// batches were kept has no batch and is taken without one.
+func takeBatches(ctx context.Context, tx pgx.Tx, transactionID string, movement stockMovement) error {
+
+	type batch struct {
+		id    string
+		count int
+	}
+
+	rows, err := tx.Query(ctx, `select b.id, b.count from batch b
+	 where b.deleted_at is null and b.branch_id = $1 and b.product_id = $2 and b.count > 0
+	 order by exists (
+		select 1 from stock_batch_movement l join storage_transaction st on st.id = l.storage_transaction_id
+		where l.batch_id = b.id and st.storage_transaction_type = 'plus'
+		and st.source_type = $3 and st.source_id = nullif($4, '')::uuid
+	 ) desc, b.expires_at nulls last, b.created_at for update of b`,
+		movement.branchID, movement.productID, movement.originType, movement.originID)
+	if err != nil {
+		return err
+	}
+
+	batches := []batch{}
+	for rows.Next() {
+		b := batch{}
+		if err = rows.Scan(&b.id, &b.count); err != nil {
+			rows.Close()
+			return err
+		}
+		batches = append(batches, b)
+	}
+	rows.Close()
+
+	remaining := movement.quantity
+	for _, b := range batches {
+		if remaining == 0 {
+			break
+		}
+
+		quantity := b.count
+		if quantity > remaining {
+			quantity = remaining
+		}
+		remaining -= quantity
+
+		if _, err = tx.Exec(ctx, `update batch set count = count - $1, updated_at = $2 where id = $3`,
+			quantity, time.Now(), b.id); err != nil {
+			return err
+		}
+
+		if err = linkBatch(ctx, tx, transactionID, b.id, quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// putBatches brings a plus movement into the branch batches. The quantity the
+// origin document took and has not put back yet returns to the batches it
+// came from, latest expiry first; at another branch, as on a transfer, it
+// goes into new batches with the same expiry date and cost. The rest makes a
+// new batch, costed at the movement price when goods are received or adjusted
+// and at the branch average cost otherwise, as the price of a cancelled or
+// returned sale is what the goods sold for.
+func putBatches(ctx context.Context, tx pgx.Tx, transactionID string, movement stockMovement) error {
+
+	type taken struct {
+		id        string
+		batchID   string
+		quantity  int
+		branchID  string
+		expiresAt sql.NullTime
+		cost      float64
+	}
+
+	remaining := movement.quantity
+
+	if movement.originType != "" {
+		rows, err := tx.Query(ctx, `select l.id, l.batch_id, l.quantity - l.restored, b.branch_id, b.expires_at, b.cost
+		 from stock_batch_movement l
+		 join storage_transaction st on st.id = l.storage_transaction_id
+		 join batch b on b.id = l.batch_id
+		 where st.storage_transaction_type = 'minus' and st.source_type = $1 and st.source_id = $2
+		 and st.product_id = $3 and l.quantity > l.restored
+		 order by b.expires_at desc nulls first, b.created_at desc for update of l`,
+			movement.originType, movement.originID, movement.productID)
+		if err != nil {
+			return err
+		}
+
+		takes := []taken{}
+		for rows.Next() {
+			t := taken{}
+			if err = rows.Scan(&t.id, &t.batchID, &t.quantity, &t.branchID, &t.expiresAt, &t.cost); err != nil {
+				rows.Close()
+				return err
+			}
+			takes = append(takes, t)
+		}
+		rows.Close()
+
+		for _, t := range takes {
+			if remaining == 0 {
+				break
+			}
+
+			quantity := t.quantity
+			if quantity > remaining {
+				quantity = remaining
+			}
+			remaining -= quantity
+
+			if _, err = tx.Exec(ctx, `update stock_batch_movement set restored = restored + $1 where id = $2`,
+				quantity, t.id); err != nil {
+				return err
+			}
+
+			batchID := t.batchID
+			if t.branchID == movement.branchID {
+				_, err = tx.Exec(ctx, `update batch set count = count + $1, updated_at = $2 where id = $3`,
+					quantity, time.Now(), batchID)
+			} else {
+				var expiresAt *time.Time
+				if t.expiresAt.Valid {
+					expiresAt = &t.expiresAt.Time
+				}
+				batchID, err = createBatch(ctx, tx, movement.branchID, movement.productID, expiresAt, t.cost, quantity)
+			}
+			if err != nil {
+				return err
+			}
+
+			if err = linkBatch(ctx, tx, transactionID, batchID, quantity); err != nil {
+				return err
+			}
+		}
+	}
+
+	if remaining == 0 {
+		return nil
+	}
+
+	cost := movement.price / float64(movement.quantity)
+	if movement.sourceType != sourceIncome && movement.sourceType != sourceAdjustment {
+		if err := tx.QueryRow(ctx, `select average_cost from storage
+		 where deleted_at is null and branch_id = $1 and product_id = $2`,
+			movement.branchID, movement.productID).Scan(&cost); err != nil {
+			return err
+		}
+	}
+
+	batchID, err := createBatch(ctx, tx, movement.branchID, movement.productID, movement.expiresAt, cost, remaining)
+	if err != nil {
+		return err
+	}
+
+	return linkBatch(ctx, tx, transactionID, batchID, remaining)
+}
+
+func createBatch(ctx context.Context, tx pgx.Tx, branchID, productID string, expiresAt *time.Time, cost float64, count int) (string, error) {
+
+	id := uuid.New().String()
+
+	_, err := tx.Exec(ctx, `insert into batch (id, branch_id, product_id, expires_at, cost, received_count, count)
+	 values ($1, $2, $3, $4, $5, $6, $6)`, id, branchID, productID, expiresAt, cost, count)
+
+	return id, err
+}
+
+// linkBatch records how much of a storage transaction went through a batch.
+func linkBatch(ctx context.Context, tx pgx.Tx, transactionID, batchID string, quantity int) error {
+
+	_, err := tx.Exec(ctx, `insert into stock_batch_movement (id, storage_transaction_id, batch_id, quantity)
+	 values ($1, $2, $3, $4)`, uuid.New(), transactionID, batchID, quantity)
+
+	return err
+}
+
+// updateAverageCost folds a receipt of quantity units at unitCost into the
+// weighted average cost of the branch storage. It runs after the receipt's
+// moveStock, so the storage count already includes the quantity. A negative
+// quantity takes a corrected receipt back out of the average.
+func updateAverageCost(ctx context.Context, tx pgx.Tx, branchID, productID string, quantity int, unitCost float64) error {
+
+	_, err := tx.Exec(ctx, `update storage set average_cost = case
+	 when count > 0 then greatest((average_cost * (count - $1) + $2 * $1) / count, 0)
+	 when $1 > 0 then $2 else average_cost end
+	 where deleted_at is null and branch_id = $3 and product_id = $4`, quantity, unitCost, branchID, productID)
+
+	return err
+}
+
+// postAdjustment posts a manual adjustment to the ledger. A plus adjustment
+// priced by hand brings the goods in at that cost like a receipt, one without
+// a price, as goods found on the shelf, at the branch average cost, which
+// leaves the average as it is. A minus adjustment is valued at the average
+// cost.
+func postAdjustment(ctx context.Context, tx pgx.Tx, movement stockMovement) (string, error) {
+
+	movement.sourceType = sourceAdjustment
+
+	priced := movement.transactionType == "plus" && movement.price > 0
+	if !priced {
+		var averageCost float64
+		if err := tx.QueryRow(ctx, `select coalesce((select average_cost from storage
+		 where deleted_at is null and branch_id = $1 and product_id = $2), 0)`,
+			movement.branchID, movement.productID).Scan(&averageCost); err != nil {
+			return "", err
+		}
+		movement.price = averageCost * float64(movement.quantity)
+	}
+
+	id, err := moveStock(ctx, tx, movement)
+	if err != nil {
+		return "", err
+	}
+
+	if priced {
+		if err = updateAverageCost(ctx, tx, movement.branchID, movement.productID,
+			movement.quantity, movement.price/float64(movement.quantity)); err != nil {
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+// soldUnitCost is the cost of a unit taken by a minus storage transaction.
+// The fifo method costs the quantity at the batches it was taken from and
+// anything taken without a batch at the average cost, the average method
+// costs it all at the branch average.
+func soldUnitCost(ctx context.Context, tx pgx.Tx, method, transactionID, branchID, productID string, quantity int) (float64, error) {
+
+	var averageCost float64
+	if err := tx.QueryRow(ctx, `select coalesce((select average_cost from storage
+	 where deleted_at is null and branch_id = $1 and product_id = $2), 0)`, branchID, productID).Scan(&averageCost); err != nil {
+		return 0, err
+	}
+
+	if method != costFIFO || quantity <= 0 {
+		return averageCost, nil
+	}
+
+	var (
+		batchCost     float64
+		batchQuantity int
+	)
+
+	if err := tx.QueryRow(ctx, `select coalesce(sum(l.quantity * b.cost), 0), coalesce(sum(l.quantity), 0)::int
+	 from stock_batch_movement l join batch b on b.id = l.batch_id
+	 where l.storage_transaction_id = $1`, transactionID).Scan(&batchCost, &batchQuantity); err != nil {
+		return 0, err
+	}
+
+	return (batchCost + averageCost*float64(quantity-batchQuantity)) / float64(quantity), nil
+}
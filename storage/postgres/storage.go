@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -25,23 +26,53 @@ func NewStorageRepo(pool *pgxpool.Pool, log logger.ILogger) storage.IStorageRepo
 	}
 }
 
-func (s *storageRepo) Create(ctx context.Context, storage models.CreateStorage) (string, error) {
+// Create opens the storage of a product at a branch with its thresholds and
+// posts an opening count to the ledger as a plus adjustment.
+func (s *storageRepo) Create(ctx context.Context, request models.CreateStorage) (id string, err error) {
 
-	id := uuid.New()
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		s.log.Error("error while checking storage branch", logger.Error(err))
+		return "", err
+	}
 
-	query := `insert into storage (id, product_id, branch_id, count) values ($1, $2, $3, $4)`
+	if branchID == "" || request.ProductID == "" || request.Count < 0 {
+		return "", storage.ErrAdjustmentQuantity
+	}
 
-	_, err := s.pool.Exec(ctx, query,
-		id,
-		storage.ProductID,
-		storage.BranchID,
-		storage.Count,
-	)
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
+		s.log.Error("error while starting storage transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	id = uuid.New().String()
+
+	if _, err = tx.Exec(ctx, `insert into storage (id, product_id, branch_id, count, min_count, reorder_count) values ($1, $2, $3, 0, $4, $5)`,
+		id,
+		request.ProductID,
+		branchID,
+		request.MinCount,
+		request.ReorderCount,
+	); err != nil {
 		s.log.Error("error while inserting storage", logger.Error(err))
 		return "", err
 	}
-	return id.String(), nil
+
+	if err = adjustStorage(ctx, tx, branchID, request.ProductID, request.StaffID, request.Reason, request.Count, request.Cost); err != nil {
+		s.log.Error("error while adjusting storage count", logger.Error(err))
+		return "", err
+	}
+
+	return id, nil
 }
 
 func (s *storageRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Storage, error) {
@@ -50,19 +81,29 @@ func (s *storageRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Sto
 
 	storage := models.Storage{}
 
-	row := s.pool.QueryRow(ctx, `select 
+	query := `select 
 	id, 
 	product_id, 
 	branch_id, 
 	count, 
+	min_count, 
+	reorder_count, 
+	average_cost, 
 	created_at, 
-	updated_at  from storage where deleted_at is null and id = $1`, id.ID)
+	updated_at  from storage where deleted_at is null and id = $1`
+
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id.ID})
+
+	row := s.pool.QueryRow(ctx, query+condition, args...)
 
 	err := row.Scan(
 		&storage.ID,
 		&storage.ProductID,
 		&storage.BranchID,
 		&storage.Count,
+		&storage.MinCount,
+		&storage.ReorderCount,
+		&storage.AverageCost,
 		&storage.CreatedAt,
 		&updatedAt,
 	)
@@ -91,12 +132,14 @@ func (s *storageRepo) GetList(ctx context.Context, request models.GetListRequest
 		search            = request.Search
 	)
 
-	countQuery = `select count(1) from storage where deleted_at is null`
+	condition, args := branchCondition(ctx, "branch_id", nil)
+
+	countQuery = `select count(1) from storage where deleted_at is null` + condition
 
 	if search != "" {
 		countQuery += fmt.Sprintf(` and product_id = '%s'`, search)
 	}
-	if err := s.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+	if err := s.pool.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", logger.Error(err))
 		return models.StoragesResponse{}, err
 	}
@@ -106,15 +149,18 @@ func (s *storageRepo) GetList(ctx context.Context, request models.GetListRequest
 	product_id, 
 	branch_id, 
 	count, 
+	min_count, 
+	reorder_count, 
+	average_cost, 
 	created_at, 
-	updated_at from storage where deleted_at is null`
+	updated_at from storage where deleted_at is null` + condition
 
 	if search != "" {
-		countQuery += fmt.Sprintf(` and product_id = '%s'`, search)
+		query += fmt.Sprintf(` and product_id = '%s'`, search)
 	}
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := s.pool.Query(ctx, query, request.Limit, offset)
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := s.pool.Query(ctx, query, append(args, request.Limit, offset)...)
 	if err != nil {
 		fmt.Println("error is while selecting product", logger.Error(err))
 		return models.StoragesResponse{}, err
@@ -127,6 +173,9 @@ func (s *storageRepo) GetList(ctx context.Context, request models.GetListRequest
 			&storage.ProductID,
 			&storage.BranchID,
 			&storage.Count,
+			&storage.MinCount,
+			&storage.ReorderCount,
+			&storage.AverageCost,
 			&storage.CreatedAt,
 			&updatedAt,
 		); err != nil {
@@ -148,60 +197,178 @@ func (s *storageRepo) GetList(ctx context.Context, request models.GetListRequest
 	}, nil
 }
 
-func (s *storageRepo) Update(ctx context.Context, request models.UpdateStorage) (string, error) {
+// Update sets the stock thresholds of a storage. A count, when given, is
+// reached with an adjustment posted to the ledger, never written directly.
+func (s *storageRepo) Update(ctx context.Context, request models.UpdateStorage) (id string, err error) {
 
-	query := ` update storage set 
-	product_id = $1, 
-	branch_id = $2, 
-	count = $3, 
-	updated_at = $4 
-	where id = $5`
+	if request.Count != nil && *request.Count < 0 {
+		return "", storage.ErrAdjustmentQuantity
+	}
 
-	_, err := s.pool.Exec(ctx, query,
-		request.ProductID,
-		request.BranchID,
-		request.Count,
-		time.Now(),
-		request.ID,
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("error while starting storage transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	var (
+		branchID, productID string
+		count               int
 	)
 
-	if err != nil {
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{request.ID})
+
+	if err = tx.QueryRow(ctx, `select branch_id, product_id, count from storage
+	 where deleted_at is null and id = $1`+condition+` for update`, args...).Scan(&branchID, &productID, &count); err != nil {
+		s.log.Error("error while selecting storage for update", logger.Error(err))
+		return "", err
+	}
+
+	if _, err = tx.Exec(ctx, `update storage set min_count = $1, reorder_count = $2, updated_at = $3 where id = $4`,
+		request.MinCount, request.ReorderCount, time.Now(), request.ID); err != nil {
 		s.log.Error("error while updating storage data...", logger.Error(err))
 		return "", err
 	}
 
+	if request.Count != nil {
+		if err = adjustStorage(ctx, tx, branchID, productID, request.StaffID, request.Reason, *request.Count-count, request.Cost); err != nil {
+			s.log.Error("error while adjusting storage count", logger.Error(err))
+			return "", err
+		}
+	}
+
 	return request.ID, nil
 }
 
+// Delete drops a storage whose count is adjusted to zero, so the ledger still
+// adds up to the stock on hand.
 func (s *storageRepo) Delete(ctx context.Context, id string) error {
 
-	query := `update storage 
-	set deleted_at = $1 
-	where id = $2`
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id})
 
-	_, err := s.pool.Exec(ctx, query, time.Now(), id)
+	var count int
+	if err := s.pool.QueryRow(ctx, `select count from storage where deleted_at is null and id = $1`+condition, args...).Scan(&count); err != nil {
+		s.log.Error("error while selecting storage for delete", logger.Error(err))
+		return err
+	}
+
+	tag, err := s.pool.Exec(ctx, `update storage set deleted_at = $1 where deleted_at is null and id = $2 and count = 0`, time.Now(), id)
 	if err != nil {
 		s.log.Error("error while deleting storage by id", logger.Error(err))
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return storage.ErrStorageNotEmpty
+	}
+
 	return nil
 }
 
-func (s *storageRepo) UpdateCount(ctx context.Context, request models.UpdateCount) error {
+// adjustStorage posts a signed change of the branch count to the ledger as a
+// manual adjustment, units added costing unitCost, see postAdjustment.
+func adjustStorage(ctx context.Context, tx pgx.Tx, branchID, productID, staffID, reason string, quantity int, unitCost float64) error {
+
+	if quantity == 0 {
+		return nil
+	}
+
+	transactionType := "plus"
+	if quantity < 0 {
+		transactionType = "minus"
+		quantity = -quantity
+	}
+
+	_, err := postAdjustment(ctx, tx, stockMovement{
+		branchID:        branchID,
+		staffID:         staffID,
+		productID:       productID,
+		transactionType: transactionType,
+		quantity:        quantity,
+		price:           unitCost * float64(quantity),
+		reason:          reason,
+	})
+
+	return err
+}
+
+// GetProductCount returns how many units of the product the branch holds.
+func (s *storageRepo) GetProductCount(ctx context.Context, branchID, productID string) (int, error) {
+
+	branchID, err := scopedBranchID(ctx, branchID)
+	if err != nil {
+		s.log.Error("error while checking storage branch", logger.Error(err))
+		return 0, err
+	}
+
+	count := 0
+
+	if err = s.pool.QueryRow(ctx, `select coalesce(sum(count), 0) from storage
+	 where deleted_at is null and branch_id = $1 and product_id = $2`, branchID, productID).Scan(&count); err != nil {
+		s.log.Error("error while selecting product count", logger.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
 
-	query := `update storage set 
-	count = count + $1,
-	updated_at = $2
-	where id = $3
-	`
+// LowStockAlerts returns the low stock alerts not delivered yet, oldest
+// first.
+func (s *storageRepo) LowStockAlerts(ctx context.Context) ([]models.StockAlert, error) {
 
-	_, err := s.pool.Exec(ctx, query, request.Count, time.Now(), request.ID)
+	rows, err := s.pool.Query(ctx, `select a.id, a.branch_id, a.product_id, p.name, coalesce(a.sale_id::text, ''),
+	 a.count, a.min_count, a.reorder_count, a.created_at
+	 from stock_alert a
+	 join product p on p.id = a.product_id
+	 where a.delivered_at is null
+	 order by a.created_at, a.id`)
 	if err != nil {
-		s.log.Error("error while update storage count", logger.Error(err))
+		s.log.Error("error while selecting low stock alerts", logger.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []models.StockAlert{}
+	for rows.Next() {
+		alert := models.StockAlert{}
+		if err = rows.Scan(
+			&alert.ID,
+			&alert.BranchID,
+			&alert.ProductID,
+			&alert.ProductName,
+			&alert.SaleID,
+			&alert.Count,
+			&alert.MinCount,
+			&alert.ReorderCount,
+			&alert.At,
+		); err != nil {
+			s.log.Error("error while scanning low stock alert", logger.Error(err))
+			return nil, err
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// MarkStockAlertDelivered records that the alert reached the notifier, so it
+// is not handed out again.
+func (s *storageRepo) MarkStockAlertDelivered(ctx context.Context, id string) error {
+
+	if _, err := s.pool.Exec(ctx, `update stock_alert set delivered_at = $1 where id = $2 and delivered_at is null`,
+		time.Now(), id); err != nil {
+		s.log.Error("error while marking stock alert delivered", logger.Error(err))
 		return err
 	}
 
 	return nil
-
 }
@@ -7,9 +7,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"time"
 
-	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -25,136 +24,135 @@ func NewStorageTransactionRepo(pool *pgxpool.Pool, log logger.ILogger) storage.I
 	}
 }
 
-func (s *storageTransactionRepo) Create(ctx context.Context, request models.CreateStorageTransaction) (string, error) {
+// Create applies a manual adjustment to the branch storage and records it in
+// the ledger like any other stock movement.
+func (s *storageTransactionRepo) Create(ctx context.Context, request models.CreateStorageTransaction) (id string, err error) {
 
-	id := uuid.New()
+	branchID, err := scopedBranchID(ctx, request.BranchID)
+	if err != nil {
+		return "", err
+	}
 
-	query := `insert into storage_transaction (id, staff_id, product_id, storage_transaction_type, price, quantity) values ($1, $2, $3, $4, $5, $6)`
+	quantity := int(request.Quantity)
+	if branchID == "" || request.ProductID == "" || quantity <= 0 || float64(quantity) != request.Quantity || request.Price < 0 ||
+		(request.StorageTransactionType != "plus" && request.StorageTransactionType != "minus") {
+		return "", storage.ErrAdjustmentQuantity
+	}
 
-	_, err := s.pool.Exec(ctx, query,
-		id,
-		request.StaffID,
-		request.ProductID,
-		request.StorageTransactionType,
-		request.Price,
-		request.Quantity,
-	)
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
+		s.log.Error("error while starting storage transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if id, err = postAdjustment(ctx, tx, stockMovement{
+		branchID:        branchID,
+		staffID:         request.StaffID,
+		productID:       request.ProductID,
+		transactionType: request.StorageTransactionType,
+		quantity:        quantity,
+		price:           request.Price,
+		reason:          request.Reason,
+	}); err != nil {
 		s.log.Error("error while inserting storage transaction data", logger.Error(err))
 		return "", err
 	}
-	return id.String(), nil
+
+	return id, nil
 }
 
 func (s *storageTransactionRepo) Get(ctx context.Context, id models.PrimaryKey) (models.StorageTransaction, error) {
 
-	var updatedAt = sql.NullTime{}
+	condition, args := branchCondition(ctx, "branch_id", []interface{}{id.ID})
 
-	storageTransaction := models.StorageTransaction{}
-
-	query := `select id,
-	 staff_id, 
-	 product_id, 
-	 storage_transaction_type, 
-	 price, 
-	 quantity, 
-	 created_at, 
-	 updated_at 
+	query := `select` + storageTransactionColumns + `
 	 from storage_transaction
-	 where deleted_at is null and id = $1`
-
-	row := s.pool.QueryRow(ctx, query, id.ID)
-
-	err := row.Scan(
-		&storageTransaction.ID,
-		&storageTransaction.StaffID,
-		&storageTransaction.ProductID,
-		&storageTransaction.StorageTransactionType,
-		&storageTransaction.Price,
-		&storageTransaction.Quantity,
-		&storageTransaction.CreatedAt,
-		&updatedAt,
-	)
+	 where deleted_at is null and id = $1` + condition
 
+	storageTransaction, err := scanStorageTransaction(s.pool.QueryRow(ctx, query, args...))
 	if err != nil {
 		s.log.Error("error while selecting storage transaction data", logger.Error(err))
 		return models.StorageTransaction{}, err
 	}
 
-	if updatedAt.Valid {
-		storageTransaction.UpdatedAt = updatedAt.Time
-	}
-
 	return storageTransaction, nil
 }
 
-func (s *storageTransactionRepo) GetList(ctx context.Context, request models.GetListRequest) (models.StorageTransactionsResponse, error) {
+// GetList returns the ledger oldest first so balances read in order.
+func (s *storageTransactionRepo) GetList(ctx context.Context, request models.GetStorageTransactionsListRequest) (models.StorageTransactionsResponse, error) {
 
 	var (
-		updatedAt           = sql.NullTime{}
 		storageTransactions = []models.StorageTransaction{}
 		count               = 0
-		query, countQuery   string
-		page                = request.Page
-		offset              = (page - 1) * request.Limit
-		search              = request.Search
+		offset              = (request.Page - 1) * request.Limit
+		condition           string
+		args                []interface{}
 	)
 
-	countQuery = `select count(1) from storage_transaction where deleted_at is null `
+	filters := []struct {
+		column string
+		value  string
+	}{
+		{"branch_id::text", request.BranchID},
+		{"product_id::text", request.ProductID},
+		{"storage_transaction_type", request.StorageTransactionType},
+		{"source_type", request.SourceType},
+		{"source_id::text", request.SourceID},
+	}
 
-	if search != "" {
-		countQuery += fmt.Sprintf(`and storage_transaction_type ilike '%%%s%%'`, search)
+	for _, filter := range filters {
+		if filter.value != "" {
+			args = append(args, filter.value)
+			condition += fmt.Sprintf(` and %s = $%d`, filter.column, len(args))
+		}
 	}
-	if err := s.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
-		fmt.Println("error is while selecting storage_transaction count", logger.Error(err))
-		return models.StorageTransactionsResponse{}, err
+
+	if !request.FromDate.IsZero() {
+		args = append(args, request.FromDate)
+		condition += fmt.Sprintf(` and created_at >= $%d`, len(args))
 	}
 
-	query = `select 
-	id, 
-	staff_id, 
-	product_id, 
-	storage_transaction_type, 
-	price, 
-	quantity, 
-	created_at, 
-	updated_at
-	from storage_transaction 
-	where deleted_at is null`
+	if !request.ToDate.IsZero() {
+		args = append(args, request.ToDate)
+		condition += fmt.Sprintf(` and created_at < $%d`, len(args))
+	}
+
+	branch, args := branchCondition(ctx, "branch_id", args)
+	condition += branch
 
-	if search != "" {
-		query += fmt.Sprintf(` where storage_transaction_type ilike '%%%s%%'`, search)
+	if err := s.pool.QueryRow(ctx, `select count(1) from storage_transaction where deleted_at is null`+condition, args...).Scan(&count); err != nil {
+		s.log.Error("error is while selecting storage_transaction count", logger.Error(err))
+		return models.StorageTransactionsResponse{}, err
 	}
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := s.pool.Query(ctx, query, request.Limit, offset)
+	query := `select` + storageTransactionColumns + `
+	 from storage_transaction
+	 where deleted_at is null` + condition + ` order by created_at, id`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := s.pool.Query(ctx, query, append(args, request.Limit, offset)...)
 	if err != nil {
-		fmt.Println("error is while selecting storage transaction", logger.Error(err))
+		s.log.Error("error is while selecting storage transaction", logger.Error(err))
 		return models.StorageTransactionsResponse{}, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
-		storageTransaction := models.StorageTransaction{}
-		if err = rows.Scan(
-			&storageTransaction.ID,
-			&storageTransaction.StaffID,
-			&storageTransaction.ProductID,
-			&storageTransaction.StorageTransactionType,
-			&storageTransaction.Price,
-			&storageTransaction.Quantity,
-			&storageTransaction.CreatedAt,
-			&updatedAt,
-		); err != nil {
-			fmt.Println("error is while scanning storage transaction data", logger.Error(err))
+		storageTransaction, err := scanStorageTransaction(rows)
+		if err != nil {
+			s.log.Error("error is while scanning storage transaction data", logger.Error(err))
 			return models.StorageTransactionsResponse{}, err
 		}
 
-		if updatedAt.Valid {
-			storageTransaction.UpdatedAt = updatedAt.Time
-		}
-
 		storageTransactions = append(storageTransactions, storageTransaction)
-
 	}
 
 	return models.StorageTransactionsResponse{
@@ -163,48 +161,54 @@ func (s *storageTransactionRepo) GetList(ctx context.Context, request models.Get
 	}, nil
 }
 
-func (s *storageTransactionRepo) Update(ctx context.Context, request models.UpdateStorageTransaction) (string, error) {
-
-	query := `update storage_transaction
-   set 
-   staff_id = $1, 
-   product_id = $2, 
-   storage_transaction_type = $3,
-   price = $4, 
-   quantity = $5, 
-   updated_at = $6
-   where id = $7
-   `
-	_, err := s.pool.Exec(ctx, query,
-		request.StaffID,
-		request.ProductID,
-		request.StorageTransactionType,
-		request.Price,
-		request.Quantity,
-		time.Now(),
-		request.ID,
-	)
-	if err != nil {
-		s.log.Error("error while updating storage_transaction data...", logger.Error(err))
-		return "", err
-	}
+const storageTransactionColumns = ` id,
+	 coalesce(staff_id, ''),
+	 product_id,
+	 storage_transaction_type,
+	 price,
+	 quantity,
+	 coalesce(branch_id::text, ''),
+	 coalesce(source_type, ''),
+	 coalesce(source_id::text, ''),
+	 coalesce(reason, ''),
+	 balance_after,
+	 created_at,
+	 updated_at`
+
+func scanStorageTransaction(row pgx.Row) (models.StorageTransaction, error) {
 
-	return request.ID, nil
-}
+	var (
+		updatedAt          = sql.NullTime{}
+		balanceAfter       = sql.NullInt64{}
+		storageTransaction = models.StorageTransaction{}
+	)
 
-func (s *storageTransactionRepo) Delete(ctx context.Context, id string) error {
+	if err := row.Scan(
+		&storageTransaction.ID,
+		&storageTransaction.StaffID,
+		&storageTransaction.ProductID,
+		&storageTransaction.StorageTransactionType,
+		&storageTransaction.Price,
+		&storageTransaction.Quantity,
+		&storageTransaction.BranchID,
+		&storageTransaction.SourceType,
+		&storageTransaction.SourceID,
+		&storageTransaction.Reason,
+		&balanceAfter,
+		&storageTransaction.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		return models.StorageTransaction{}, err
+	}
 
-	query := `
-	update storage_transaction
-	 set deleted_at = $1
-	  where id = $2
-	`
+	if balanceAfter.Valid {
+		balance := int(balanceAfter.Int64)
+		storageTransaction.BalanceAfter = &balance
+	}
 
-	_, err := s.pool.Exec(ctx, query, time.Now(), id)
-	if err != nil {
-		s.log.Error("error while deleting storage_transaction by id", logger.Error(err))
-		return err
+	if updatedAt.Valid {
+		storageTransaction.UpdatedAt = updatedAt.Time
 	}
 
-	return nil
+	return storageTransaction, nil
 }
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/storage"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type supplierRepo struct {
+	pool *pgxpool.Pool
+	log  logger.ILogger
+}
+
+func NewSupplierRepo(pool *pgxpool.Pool, log logger.ILogger) storage.ISupplierRepo {
+	return &supplierRepo{
+		pool: pool,
+		log:  log,
+	}
+}
+
+func (s *supplierRepo) Create(ctx context.Context, request models.CreateSupplier) (string, error) {
+
+	id := uuid.New()
+
+	if _, err := s.pool.Exec(ctx, `insert into supplier (id, name, phone, address) values ($1, $2, $3, $4)`,
+		id,
+		request.Name,
+		request.Phone,
+		request.Address,
+	); err != nil {
+		s.log.Error("error while inserting supplier", logger.Error(err))
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+func (s *supplierRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Supplier, error) {
+
+	var (
+		updatedAt = sql.NullTime{}
+		phone     = sql.NullString{}
+		address   = sql.NullString{}
+		supplier  = models.Supplier{}
+	)
+
+	if err := s.pool.QueryRow(ctx, `select id, name, phone, address, created_at, updated_at
+	 from supplier where deleted_at is null and id = $1`, id.ID).Scan(
+		&supplier.ID,
+		&supplier.Name,
+		&phone,
+		&address,
+		&supplier.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		s.log.Error("error while selecting supplier", logger.Error(err))
+		return models.Supplier{}, err
+	}
+
+	supplier.Phone = phone.String
+	supplier.Address = address.String
+
+	if updatedAt.Valid {
+		supplier.UpdatedAt = updatedAt.Time
+	}
+
+	return supplier, nil
+}
+
+func (s *supplierRepo) GetList(ctx context.Context, request models.GetListRequest) (models.SuppliersResponse, error) {
+
+	var (
+		updatedAt = sql.NullTime{}
+		phone     = sql.NullString{}
+		address   = sql.NullString{}
+		suppliers = []models.Supplier{}
+		count     = 0
+		offset    = (request.Page - 1) * request.Limit
+		condition string
+		args      []interface{}
+	)
+
+	if request.Search != "" {
+		args = append(args, request.Search)
+		condition = ` and (name ilike '%' || $1 || '%' or phone ilike '%' || $1 || '%')`
+	}
+
+	if err := s.pool.QueryRow(ctx, `select count(1) from supplier where deleted_at is null`+condition, args...).Scan(&count); err != nil {
+		s.log.Error("error while selecting supplier count", logger.Error(err))
+		return models.SuppliersResponse{}, err
+	}
+
+	query := `select id, name, phone, address, created_at, updated_at
+	 from supplier where deleted_at is null` + condition + ` order by name`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := s.pool.Query(ctx, query, append(args, request.Limit, offset)...)
+	if err != nil {
+		s.log.Error("error while selecting suppliers", logger.Error(err))
+		return models.SuppliersResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		supplier := models.Supplier{}
+		if err = rows.Scan(
+			&supplier.ID,
+			&supplier.Name,
+			&phone,
+			&address,
+			&supplier.CreatedAt,
+			&updatedAt,
+		); err != nil {
+			s.log.Error("error while scanning supplier", logger.Error(err))
+			return models.SuppliersResponse{}, err
+		}
+
+		supplier.Phone = phone.String
+		supplier.Address = address.String
+
+		if updatedAt.Valid {
+			supplier.UpdatedAt = updatedAt.Time
+		}
+
+		suppliers = append(suppliers, supplier)
+	}
+
+	return models.SuppliersResponse{
+		Suppliers: suppliers,
+		Count:     count,
+	}, nil
+}
+
+func (s *supplierRepo) Update(ctx context.Context, request models.UpdateSupplier) (string, error) {
+
+	if _, err := s.pool.Exec(ctx, `update supplier set name = $1, phone = $2, address = $3, updated_at = $4
+	 where deleted_at is null and id = $5`,
+		request.Name,
+		request.Phone,
+		request.Address,
+		time.Now(),
+		request.ID,
+	); err != nil {
+		s.log.Error("error while updating supplier", logger.Error(err))
+		return "", err
+	}
+
+	return request.ID, nil
+}
+
+func (s *supplierRepo) Delete(ctx context.Context, id string) error {
+
+	if _, err := s.pool.Exec(ctx, `update supplier set deleted_at = $1 where id = $2`, time.Now(), id); err != nil {
+		s.log.Error("error while deleting supplier", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// CreatePayment records a payment to the supplier, pgx.ErrNoRows when there
+// is no such supplier.
+func (s *supplierRepo) CreatePayment(ctx context.Context, request models.CreateSupplierPayment) (models.SupplierPayment, error) {
+
+	payment := models.SupplierPayment{
+		ID:          uuid.New().String(),
+		SupplierID:  request.SupplierID,
+		StaffID:     request.StaffID,
+		Amount:      request.Amount,
+		Description: request.Description,
+	}
+
+	if err := s.pool.QueryRow(ctx, `insert into supplier_payment (id, supplier_id, staff_id, amount, description)
+	 select $1, sp.id, nullif($3, ''), $4, $5 from supplier sp where sp.deleted_at is null and sp.id = $2
+	 returning created_at`,
+		payment.ID,
+		payment.SupplierID,
+		payment.StaffID,
+		payment.Amount,
+		payment.Description,
+	).Scan(&payment.CreatedAt); err != nil {
+		s.log.Error("error while inserting supplier payment", logger.Error(err))
+		return models.SupplierPayment{}, err
+	}
+
+	return payment, nil
+}
+
+// Payable sums the value of the incomes received from the supplier at every
+// branch and the payments made to them, which are not kept by branch.
+func (s *supplierRepo) Payable(ctx context.Context, supplierID string) (models.SupplierPayable, error) {
+
+	payable := models.SupplierPayable{
+		SupplierID: supplierID,
+	}
+
+	if err := s.pool.QueryRow(ctx, `select
+	 (select coalesce(sum(price), 0) from income where deleted_at is null and supplier_id = $1),
+	 (select coalesce(sum(amount), 0) from supplier_payment where deleted_at is null and supplier_id = $1)
+	 from supplier where id = $1`,
+		supplierID).Scan(&payable.Received, &payable.Paid); err != nil {
+		s.log.Error("error while selecting supplier payable", logger.Error(err))
+		return models.SupplierPayable{}, err
+	}
+
+	payable.Balance = payable.Received - payable.Paid
+
+	return payable, nil
+}
@@ -11,9 +11,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// transactionBranch is the branch a transaction belongs to, the one of its
+// staff.
+const transactionBranch = `(select s.branch_id from staff s where s.id = transactions.staff_id)`
+
 type transactionRepo struct {
 	pool *pgxpool.Pool
 	log  logger.ILogger
@@ -38,10 +43,9 @@ func (t *transactionRepo) Create(ctx context.Context, request models.CreateTrans
 		source_type, 
 		amount, 
 		description) 
-	values 
-	($1, $2, $3, $4, $5, $6, $7)`
+	select $1, $2, s.id, $4, $5, $6, $7 from staff s where s.id = $3`
 
-	_, err := t.pool.Exec(ctx, query,
+	condition, args := branchCondition(ctx, "s.branch_id", []interface{}{
 		id,
 		request.SaleID,
 		request.StaffID,
@@ -49,12 +53,21 @@ func (t *transactionRepo) Create(ctx context.Context, request models.CreateTrans
 		request.SourceType,
 		request.Amount,
 		request.Description,
-	)
+	})
+
+	tag, err := t.pool.Exec(ctx, query+condition, args...)
 	if err != nil {
 		t.log.Error("error while inserting transaction data", logger.Error(err))
 		return "", err
 	}
 
+	if tag.RowsAffected() == 0 {
+		if condition != "" {
+			return "", storage.ErrBranchAccessDenied
+		}
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 }
 
@@ -77,7 +90,9 @@ func (t *transactionRepo) Get(ctx context.Context, id models.PrimaryKey) (models
 	from transactions
 	 where deleted_at is null and id = $1`
 
-	row := t.pool.QueryRow(ctx, query, id.ID)
+	condition, args := branchCondition(ctx, transactionBranch, []interface{}{id.ID})
+
+	row := t.pool.QueryRow(ctx, query+condition, args...)
 
 	err := row.Scan(
 		&transaction.ID,
@@ -115,7 +130,9 @@ func (t *transactionRepo) GetList(ctx context.Context, request models.GetListTra
 		query, countQuery string
 	)
 
-	countQuery = `select count(1) from transactions where deleted_at is null `
+	condition, args := branchCondition(ctx, transactionBranch, nil)
+
+	countQuery = `select count(1) from transactions where deleted_at is null` + condition
 	if fromAmount != 0 && toAmount != 0 {
 		countQuery += fmt.Sprintf(` and amount between %f and %f `, fromAmount, toAmount)
 	} else if fromAmount != 0 && toAmount == 0 {
@@ -124,7 +141,7 @@ func (t *transactionRepo) GetList(ctx context.Context, request models.GetListTra
 		countQuery += ` and amount <= ` + strconv.FormatFloat(toAmount, 'f', 2, 64)
 
 	}
-	if err := t.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+	if err := t.pool.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
 		fmt.Println("error is while scanning row", logger.Error(err))
 		return models.TransactionsResponse{}, err
 	}
@@ -138,7 +155,7 @@ func (t *transactionRepo) GetList(ctx context.Context, request models.GetListTra
 	amount,
     description, 
 	created_at, 
-	updated_at from transactions where deleted_at is null `
+	updated_at from transactions where deleted_at is null` + condition
 
 	if fromAmount != 0 && toAmount != 0 {
 		query += fmt.Sprintf(` and amount between %f and %f `, fromAmount, toAmount)
@@ -149,9 +166,9 @@ func (t *transactionRepo) GetList(ctx context.Context, request models.GetListTra
 
 	}
 
-	query += ` LIMIT $1 OFFSET $2 `
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
 
-	rows, err := t.pool.Query(ctx, query, request.Limit, offset)
+	rows, err := t.pool.Query(ctx, query, append(args, request.Limit, offset)...)
 	if err != nil {
 		fmt.Println("error is while selecting all transaction", logger.Error(err))
 		return models.TransactionsResponse{}, err
@@ -197,9 +214,9 @@ func (t *transactionRepo) Update(ctx context.Context, request models.UpdateTrans
    amount = $5, 
    description = $6, 
    updated_at = $7
-   where id = $8
-   `
-	_, err := t.pool.Exec(ctx, query,
+   where id = $8`
+
+	condition, args := branchCondition(ctx, transactionBranch, []interface{}{
 		request.SaleID,
 		request.StaffID,
 		request.TransactionType,
@@ -208,11 +225,24 @@ func (t *transactionRepo) Update(ctx context.Context, request models.UpdateTrans
 		request.Description,
 		time.Now(),
 		request.ID,
-	)
+	})
+
+	if condition != "" {
+		// the new staff has to work at the caller's branch as well
+		args = append(args, request.StaffID)
+		condition += fmt.Sprintf(` and exists (select 1 from staff s where s.id = $%d and s.branch_id = $%d)`, len(args), len(args)-1)
+	}
+
+	tag, err := t.pool.Exec(ctx, query+condition, args...)
 	if err != nil {
 		t.log.Error("error while updating transaction data...", logger.Error(err))
 		return "", err
 	}
+
+	if tag.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return request.ID, nil
 }
 
@@ -221,15 +251,20 @@ func (t *transactionRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update transactions
 	 set deleted_at = $1
-	  where id = $2
-	`
+	  where deleted_at is null and id = $2`
+
+	condition, args := branchCondition(ctx, transactionBranch, []interface{}{time.Now(), id})
 
-	_, err := t.pool.Exec(ctx, query, time.Now(), id)
+	tag, err := t.pool.Exec(ctx, query+condition, args...)
 	if err != nil {
 		t.log.Error("error while deleting transaction by id", logger.Error(err))
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
 
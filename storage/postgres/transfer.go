@@ -0,0 +1,401 @@
+package postgres
+
+import (
+	"bazaar/api/models"
+	"bazaar/pkg/logger"
+	"bazaar/pkg/scope"
+	"bazaar/storage"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type transferRepo struct {
+	pool *pgxpool.Pool
+	log  logger.ILogger
+}
+
+func NewTransferRepo(pool *pgxpool.Pool, log logger.ILogger) storage.ITransferRepo {
+	return &transferRepo{
+		pool: pool,
+		log:  log,
+	}
+}
+
+// Create drafts a transfer out of the caller's branch. Nothing leaves storage
+// until the transfer is dispatched.
+func (t *transferRepo) Create(ctx context.Context, request models.CreateTransfer) (id string, err error) {
+
+	fromBranchID, err := scopedBranchID(ctx, request.FromBranchID)
+	if err != nil {
+		return "", err
+	}
+
+	if fromBranchID == "" || request.ToBranchID == "" || fromBranchID == request.ToBranchID || len(request.Products) == 0 {
+		return "", storage.ErrTransferQuantity
+	}
+
+	for _, product := range request.Products {
+		if product.ProductID == "" || product.Count <= 0 {
+			return "", storage.ErrTransferQuantity
+		}
+	}
+
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		t.log.Error("error while starting transfer transaction", logger.Error(err))
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	id = uuid.New().String()
+
+	if _, err = tx.Exec(ctx, `insert into transfer (id, from_branch_id, to_branch_id) values ($1, $2, $3)`,
+		id, fromBranchID, request.ToBranchID); err != nil {
+		t.log.Error("error while inserting transfer", logger.Error(err))
+		return "", err
+	}
+
+	for _, product := range request.Products {
+		if _, err = tx.Exec(ctx, `insert into transfer_product (id, transfer_id, product_id, count) values ($1, $2, $3, $4)`,
+			uuid.New(), id, product.ProductID, product.Count); err != nil {
+			t.log.Error("error while inserting transfer product", logger.Error(err))
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+func (t *transferRepo) Get(ctx context.Context, id models.PrimaryKey) (models.Transfer, error) {
+
+	condition, args := transferBranchCondition(ctx, []interface{}{id.ID})
+
+	transfer, err := scanTransfer(t.pool.QueryRow(ctx, `select id, from_branch_id, to_branch_id, status,
+	 coalesce(dispatched_by, ''), dispatched_at, coalesce(received_by, ''), received_at, created_at, updated_at
+	 from transfer where deleted_at is null and id = $1`+condition, args...))
+	if err != nil {
+		t.log.Error("error while selecting transfer", logger.Error(err))
+		return models.Transfer{}, err
+	}
+
+	if transfer.Products, err = transferProducts(ctx, t.pool, transfer.ID); err != nil {
+		t.log.Error("error while selecting transfer products", logger.Error(err))
+		return models.Transfer{}, err
+	}
+
+	return transfer, nil
+}
+
+func (t *transferRepo) GetList(ctx context.Context, request models.GetTransfersListRequest) (models.TransfersResponse, error) {
+
+	var (
+		transfers = []models.Transfer{}
+		count     = 0
+		offset    = (request.Page - 1) * request.Limit
+		condition string
+		args      []interface{}
+	)
+
+	if request.Status != "" {
+		args = append(args, request.Status)
+		condition += fmt.Sprintf(` and status = $%d`, len(args))
+	}
+
+	if request.BranchID != "" {
+		args = append(args, request.BranchID)
+		condition += fmt.Sprintf(` and (from_branch_id = $%d or to_branch_id = $%d)`, len(args), len(args))
+	}
+
+	branch, args := transferBranchCondition(ctx, args)
+	condition += branch
+
+	if err := t.pool.QueryRow(ctx, `select count(1) from transfer where deleted_at is null`+condition, args...).Scan(&count); err != nil {
+		t.log.Error("error while selecting transfer count", logger.Error(err))
+		return models.TransfersResponse{}, err
+	}
+
+	query := `select id, from_branch_id, to_branch_id, status,
+	 coalesce(dispatched_by, ''), dispatched_at, coalesce(received_by, ''), received_at, created_at, updated_at
+	 from transfer where deleted_at is null` + condition + ` order by created_at desc`
+
+	query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	rows, err := t.pool.Query(ctx, query, append(args, request.Limit, offset)...)
+	if err != nil {
+		t.log.Error("error while selecting transfers", logger.Error(err))
+		return models.TransfersResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			t.log.Error("error while scanning transfer", logger.Error(err))
+			return models.TransfersResponse{}, err
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return models.TransfersResponse{
+		Transfers: transfers,
+		Count:     count,
+	}, nil
+}
+
+// Delete drops a transfer that was not dispatched yet.
+func (t *transferRepo) Delete(ctx context.Context, id string) error {
+
+	condition, args := branchCondition(ctx, "from_branch_id", []interface{}{time.Now(), id})
+
+	tag, err := t.pool.Exec(ctx, `update transfer set deleted_at = $1
+	 where deleted_at is null and status = 'draft' and id = $2`+condition, args...)
+	if err != nil {
+		t.log.Error("error while deleting transfer", logger.Error(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return storage.ErrTransferStatus
+	}
+
+	return nil
+}
+
+// Dispatch takes the transfer lines out of the source branch storage with
+// minus storage transactions and puts the transfer in transit.
+func (t *transferRepo) Dispatch(ctx context.Context, request models.UpdateTransferStatus) (err error) {
+
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		t.log.Error("error while starting transfer transaction", logger.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	condition, args := branchCondition(ctx, "from_branch_id", []interface{}{request.ID})
+
+	var branchID, status string
+	if err = tx.QueryRow(ctx, `select from_branch_id, status from transfer
+	 where deleted_at is null and id = $1`+condition+` for update`, args...).Scan(&branchID, &status); err != nil {
+		t.log.Error("error while selecting transfer for update", logger.Error(err))
+		return err
+	}
+
+	if status != "draft" {
+		err = storage.ErrTransferStatus
+		return err
+	}
+
+	lines, err := transferProducts(ctx, tx, request.ID)
+	if err != nil {
+		t.log.Error("error while selecting transfer products", logger.Error(err))
+		return err
+	}
+
+	for _, line := range lines {
+		if err = moveTransferLine(ctx, tx, branchID, request, "minus", line); err != nil {
+			t.log.Error("error while taking transfer product from storage", logger.Error(err))
+			return err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `update transfer set status = 'in_transit', dispatched_by = $1, dispatched_at = $2, updated_at = $2
+	 where id = $3`, request.StaffID, time.Now(), request.ID); err != nil {
+		t.log.Error("error while updating transfer status", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Receive puts the transfer lines on the destination branch storage with plus
+// storage transactions and closes the transfer.
+func (t *transferRepo) Receive(ctx context.Context, request models.UpdateTransferStatus) (err error) {
+
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		t.log.Error("error while starting transfer transaction", logger.Error(err))
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	condition, args := branchCondition(ctx, "to_branch_id", []interface{}{request.ID})
+
+	var fromBranchID, branchID, status string
+	if err = tx.QueryRow(ctx, `select from_branch_id, to_branch_id, status from transfer
+	 where deleted_at is null and id = $1`+condition+` for update`, args...).Scan(&fromBranchID, &branchID, &status); err != nil {
+		t.log.Error("error while selecting transfer for update", logger.Error(err))
+		return err
+	}
+
+	if status != "in_transit" {
+		err = storage.ErrTransferStatus
+		return err
+	}
+
+	lines, err := transferProducts(ctx, tx, request.ID)
+	if err != nil {
+		t.log.Error("error while selecting transfer products", logger.Error(err))
+		return err
+	}
+
+	for _, line := range lines {
+		if err = moveTransferLine(ctx, tx, branchID, request, "plus", line); err != nil {
+			t.log.Error("error while putting transfer product to storage", logger.Error(err))
+			return err
+		}
+
+		// the goods arrive at what they cost the sending branch
+		var averageCost float64
+		if err = tx.QueryRow(ctx, `select coalesce((select average_cost from storage
+		 where deleted_at is null and branch_id = $1 and product_id = $2), 0)`, fromBranchID, line.ProductID).Scan(&averageCost); err != nil {
+			t.log.Error("error while selecting sending branch cost", logger.Error(err))
+			return err
+		}
+
+		if err = updateAverageCost(ctx, tx, branchID, line.ProductID, line.Count, averageCost); err != nil {
+			t.log.Error("error while updating average cost", logger.Error(err))
+			return err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `update transfer set status = 'received', received_by = $1, received_at = $2, updated_at = $2
+	 where id = $3`, request.StaffID, time.Now(), request.ID); err != nil {
+		t.log.Error("error while updating transfer status", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// moveTransferLine moves a transfer line in or out of the branch storage,
+// valued at the product price. Received stock keeps the expiry dates of the
+// batches it was dispatched from.
+func moveTransferLine(ctx context.Context, tx pgx.Tx, branchID string, request models.UpdateTransferStatus, transactionType string, line models.TransferProduct) error {
+
+	var price float64
+	if err := tx.QueryRow(ctx, `select price from product where id = $1`, line.ProductID).Scan(&price); err != nil {
+		return err
+	}
+
+	_, err := moveStock(ctx, tx, stockMovement{
+		branchID:        branchID,
+		staffID:         request.StaffID,
+		productID:       line.ProductID,
+		transactionType: transactionType,
+		quantity:        line.Count,
+		price:           price * float64(line.Count),
+		sourceType:      sourceTransfer,
+		sourceID:        request.ID,
+		originType:      sourceTransfer,
+		originID:        request.ID,
+	})
+
+	return err
+}
+
+// transferBranchCondition lets a caller bound to a branch see the transfers
+// leaving and arriving at that branch.
+func transferBranchCondition(ctx context.Context, args []interface{}) (string, []interface{}) {
+	branchID := scope.BranchID(ctx)
+	if branchID == "" {
+		return "", args
+	}
+
+	args = append(args, branchID)
+
+	return fmt.Sprintf(` and (from_branch_id = $%d or to_branch_id = $%d)`, len(args), len(args)), args
+}
+
+func scanTransfer(row pgx.Row) (models.Transfer, error) {
+
+	var (
+		dispatchedAt = sql.NullTime{}
+		receivedAt   = sql.NullTime{}
+		updatedAt    = sql.NullTime{}
+		transfer     = models.Transfer{}
+	)
+
+	if err := row.Scan(
+		&transfer.ID,
+		&transfer.FromBranchID,
+		&transfer.ToBranchID,
+		&transfer.Status,
+		&transfer.DispatchedBy,
+		&dispatchedAt,
+		&transfer.ReceivedBy,
+		&receivedAt,
+		&transfer.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		return models.Transfer{}, err
+	}
+
+	if dispatchedAt.Valid {
+		transfer.DispatchedAt = &dispatchedAt.Time
+	}
+
+	if receivedAt.Valid {
+		transfer.ReceivedAt = &receivedAt.Time
+	}
+
+	if updatedAt.Valid {
+		transfer.UpdatedAt = updatedAt.Time
+	}
+
+	return transfer, nil
+}
+
+func transferProducts(ctx context.Context, db querier, transferID string) ([]models.TransferProduct, error) {
+
+	rows, err := db.Query(ctx, `select id, transfer_id, product_id, count
+	 from transfer_product where deleted_at is null and transfer_id = $1 order by created_at`, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []models.TransferProduct{}
+	for rows.Next() {
+		product := models.TransferProduct{}
+		if err = rows.Scan(
+			&product.ID,
+			&product.TransferID,
+			&product.ProductID,
+			&product.Count,
+		); err != nil {
+			return nil, err
+		}
+
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
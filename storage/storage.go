@@ -3,6 +3,8 @@ package storage
 import (
 	"bazaar/api/models"
 	"context"
+	"errors"
+	"time"
 )
 
 type IStorage interface {
@@ -19,6 +21,15 @@ type IStorage interface {
 	Storage() IStorageRepo
 	Income() IIncomeRepo
 	IncomeProduct() IIncomeProductRepo
+	SaleReturn() ISaleReturnRepo
+	Promotion() IPromotionRepo
+	Customer() ICustomerRepo
+	Supplier() ISupplierRepo
+	PurchaseOrder() IPurchaseOrderRepo
+	Transfer() ITransferRepo
+	InventoryCount() IInventoryCountRepo
+	Report() IReportRepo
+	PriceChange() IPriceChangeRepo
 }
 
 type ICategoryRepo interface {
@@ -36,14 +47,16 @@ type IStaffRepo interface {
 	Update(context.Context, models.UpdateStaff) (string, error)
 	Delete(context.Context, string) error
 	UpdateStaffBalance(context.Context, models.UpdateStaffBalance) error
+	GetByLogin(context.Context, string) (models.Staff, error)
+	GetPassword(context.Context, string) (string, error)
+	UpdatePassword(ctx context.Context, id, password string) error
+	RehashPasswords(context.Context) (int, error)
 }
 
 type IStorageTransactionRepo interface {
 	Create(context.Context, models.CreateStorageTransaction) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.StorageTransaction, error)
-	GetList(context.Context, models.GetListRequest) (models.StorageTransactionsResponse, error)
-	Update(context.Context, models.UpdateStorageTransaction) (string, error)
-	Delete(context.Context, string) error
+	GetList(context.Context, models.GetStorageTransactionsListRequest) (models.StorageTransactionsResponse, error)
 }
 
 type ITarifRepo interface {
@@ -70,6 +83,9 @@ type IBasketRepo interface {
 	Update(context.Context, models.UpdateBasket) (string, error)
 	Delete(context.Context, string) error
 	UpdateBasketQuantity(context.Context, models.UpdateBasketQuantity) (string, error)
+	GetBySaleProduct(ctx context.Context, saleID, productID string) (models.Basket, error)
+	Reprice(ctx context.Context, id string) error
+	SetManualDiscount(context.Context, models.BasketDiscount) error
 }
 
 type IBranchRepo interface {
@@ -84,8 +100,12 @@ type IProductRepo interface {
 	Create(context.Context, models.CreateProduct) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.Product, error)
 	GetList(context.Context, models.ProductGetListRequest) (models.ProductsResponse, error)
+	GetByBarcode(ctx context.Context, barcode string) (models.Product, error)
 	Update(context.Context, models.UpdateProduct) (string, error)
 	Delete(context.Context, string) error
+	BranchPrice(ctx context.Context, productID, branchID string, at time.Time) (float64, error)
+	CreatePrice(context.Context, models.CreateProductPrice) (string, error)
+	Prices(context.Context, string) (models.ProductPrices, error)
 }
 
 type ISaleRepo interface {
@@ -94,7 +114,11 @@ type ISaleRepo interface {
 	GetList(context.Context, models.GetListRequest) (models.SalesResponse, error)
 	Update(context.Context, models.UpdateSale) (string, error)
 	Delete(context.Context, string) error
-	UpdateSalePrice(context.Context, models.SaleRequest) (string, error)
+	UpdateStatus(context.Context, models.UpdateSaleStatus) error
+	CompleteSale(context.Context, models.SaleRequest) (models.SaleReceipt, error)
+	CancelSale(context.Context, models.CancelSale) error
+	GetPayments(ctx context.Context, saleID string) ([]models.SalePayment, error)
+	GetReceipt(ctx context.Context, saleID string) (models.Receipt, error)
 }
 
 type IStorageRepo interface {
@@ -103,7 +127,9 @@ type IStorageRepo interface {
 	GetList(context.Context, models.GetListRequest) (models.StoragesResponse, error)
 	Update(context.Context, models.UpdateStorage) (string, error)
 	Delete(context.Context, string) error
-	UpdateCount(context.Context, models.UpdateCount) error
+	GetProductCount(ctx context.Context, branchID, productID string) (int, error)
+	LowStockAlerts(ctx context.Context) ([]models.StockAlert, error)
+	MarkStockAlertDelivered(ctx context.Context, id string) error
 }
 
 type IIncomeRepo interface {
@@ -119,5 +145,109 @@ type IIncomeProductRepo interface {
 	Get(context.Context, models.PrimaryKey) (models.IncomeProduct, error)
 	GetList(context.Context, models.GetListRequest) (models.IncomeProductsResponse, error)
 	Update(context.Context, models.UpdateIncomeProduct) (string, error)
+	Delete(context.Context, models.DeleteIncomeProduct) error
+}
+
+type ISaleReturnRepo interface {
+	Create(context.Context, models.CreateSaleReturn) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.SaleReturn, error)
+	GetList(context.Context, models.GetSaleReturnsListRequest) (models.SaleReturnsResponse, error)
+}
+
+type IPromotionRepo interface {
+	Create(context.Context, models.CreatePromotion) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.Promotion, error)
+	GetList(context.Context, models.GetListRequest) (models.PromotionsResponse, error)
+	Update(context.Context, models.UpdatePromotion) (string, error)
+	Delete(context.Context, string) error
+}
+
+type ICustomerRepo interface {
+	Create(context.Context, models.CreateCustomer) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.Customer, error)
+	GetList(context.Context, models.GetListRequest) (models.CustomersResponse, error)
+	Update(context.Context, models.UpdateCustomer) (string, error)
+	Delete(context.Context, string) error
+	History(context.Context, models.GetCustomerHistoryRequest) (models.CustomerHistory, error)
+}
+
+type ISupplierRepo interface {
+	Create(context.Context, models.CreateSupplier) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.Supplier, error)
+	GetList(context.Context, models.GetListRequest) (models.SuppliersResponse, error)
+	Update(context.Context, models.UpdateSupplier) (string, error)
+	Delete(context.Context, string) error
+	CreatePayment(context.Context, models.CreateSupplierPayment) (models.SupplierPayment, error)
+	Payable(ctx context.Context, supplierID string) (models.SupplierPayable, error)
+}
+
+type IPurchaseOrderRepo interface {
+	Create(context.Context, models.CreatePurchaseOrder) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.PurchaseOrder, error)
+	GetList(context.Context, models.GetPurchaseOrdersListRequest) (models.PurchaseOrdersResponse, error)
+	Receive(context.Context, models.ReceivePurchaseOrder) error
+	Cancel(ctx context.Context, id string) error
+}
+
+type ITransferRepo interface {
+	Create(context.Context, models.CreateTransfer) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.Transfer, error)
+	GetList(context.Context, models.GetTransfersListRequest) (models.TransfersResponse, error)
 	Delete(context.Context, string) error
+	Dispatch(context.Context, models.UpdateTransferStatus) error
+	Receive(context.Context, models.UpdateTransferStatus) error
 }
+
+type IInventoryCountRepo interface {
+	Create(context.Context, models.CreateInventoryCount) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.InventoryCount, error)
+	GetList(context.Context, models.GetInventoryCountsListRequest) (models.InventoryCountsResponse, error)
+	Delete(context.Context, string) error
+	Submit(context.Context, models.SubmitInventoryCount) error
+	Post(context.Context, models.PostInventoryCount) error
+	Variance(ctx context.Context, id string) (models.InventoryVariance, error)
+}
+
+type IReportRepo interface {
+	StockMovement(context.Context, models.StockMovementRequest) (models.StockMovementReport, error)
+	StockOnDate(context.Context, models.StockOnDateRequest) (models.StockOnDate, error)
+	LowStock(context.Context, models.LowStockRequest) (models.LowStockReport, error)
+	ExpiringBatches(context.Context, models.ExpiringBatchesRequest) (models.ExpiringBatchesReport, error)
+	Margin(context.Context, models.MarginRequest) (models.MarginReport, error)
+	Sales(context.Context, models.SalesReportRequest) (models.SalesReport, error)
+}
+
+type IPriceChangeRepo interface {
+	Create(context.Context, models.CreatePriceChange) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.PriceChange, error)
+	GetList(context.Context, models.GetPriceChangesListRequest) (models.PriceChangesResponse, error)
+	Delete(context.Context, string) error
+	Approve(context.Context, models.ApprovePriceChange) error
+	ApplyDue(ctx context.Context, at time.Time) ([]string, error)
+}
+
+var (
+	ErrBranchAccessDenied = errors.New("access to another branch's data is denied")
+	ErrNotEnoughProduct   = errors.New("not enough product in storage")
+	ErrBasketQuantity     = errors.New("basket lines need a product and a positive quantity")
+	ErrReturnQuantity     = errors.New("return quantity must be positive and not exceed the sold quantity")
+	ErrProductNotFound    = errors.New("product not found for barcode")
+	ErrNoReceipt          = errors.New("sale has no receipt until it is completed")
+	ErrPaymentTotal       = errors.New("payments must be positive card, cash or points tenders that add up to the sale total")
+	ErrNotEnoughPoints    = errors.New("customer has not enough loyalty points")
+	ErrOrderQuantity      = errors.New("purchase order lines must have distinct products and positive counts")
+	ErrOrderClosed        = errors.New("purchase order is already received or cancelled")
+	ErrIncomeQuantity     = errors.New("income lines must have a product, a positive count and a non-negative price")
+	ErrTransferQuantity   = errors.New("transfer must go to another branch with products and positive counts")
+	ErrTransferStatus     = errors.New("transfer can only be dispatched or deleted as a draft and received in transit")
+	ErrCountQuantity      = errors.New("counted quantities must be non-negative and name a product of the count by id or barcode")
+	ErrCountClosed        = errors.New("inventory count is already posted")
+	ErrAdjustmentQuantity = errors.New("adjustments must be a plus or minus of a positive whole quantity of a product at a branch")
+	ErrStorageNotEmpty    = errors.New("storage can only be deleted once its count is adjusted to zero")
+	ErrReportFilter       = errors.New("report needs a branch and a product")
+	ErrReportGroup        = errors.New("unknown report grouping")
+	ErrProductPrice       = errors.New("branch prices need a product, a branch and a non-negative price")
+	ErrPriceChange        = errors.New("price changes need a percent above -100 or an absolute change, a nearest, up or down rounding to a non-negative step and either products or a category")
+	ErrPriceChangeStatus  = errors.New("price change can only be approved as a draft and deleted before it is applied")
+	ErrSelfApproval       = errors.New("price change must be approved by someone other than its author")
+)